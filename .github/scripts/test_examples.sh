@@ -2,19 +2,23 @@
 set +e
 
 # This script tests a specific example directory with terratags
-# Usage: ./test_examples.sh <example_directory> <expected_exit_code> [config_file] [plan_file]
+# Usage: ./test_examples.sh <example_directory> <expected_exit_code> [config_file] [plan_file] [state_file]
 
 EXAMPLE_DIR=$1
 EXPECTED_EXIT_CODE=$2
 CONFIG_FILE=${3:-config.yaml}
 PLAN_FILE=$4
+STATE_FILE=$5
 
 echo "Testing example: $EXAMPLE_DIR (Expected exit code: $EXPECTED_EXIT_CODE, Config: $CONFIG_FILE)"
 
-# Run terratags on the example directory or plan file
-if [ -n "$PLAN_FILE" ]; then
-  echo "Running plan validation with: ./bin/terratags -c ./examples/$CONFIG_FILE -plan ./examples/$EXAMPLE_DIR/$PLAN_FILE -i"
-  ./bin/terratags -c ./examples/$CONFIG_FILE -plan ./examples/$EXAMPLE_DIR/$PLAN_FILE -i
+# Run terratags on the example directory, plan file or state file
+if [ -n "$STATE_FILE" ]; then
+  echo "Running state validation with: ./bin/terratags -c ./examples/$CONFIG_FILE -state ./examples/$EXAMPLE_DIR/$STATE_FILE -i"
+  ./bin/terratags -c ./examples/$CONFIG_FILE -state ./examples/$EXAMPLE_DIR/$STATE_FILE -i
+elif [ -n "$PLAN_FILE" ]; then
+  echo "Running plan validation with: ./bin/terratags -c ./examples/$CONFIG_FILE -plan \"./examples/$EXAMPLE_DIR/$PLAN_FILE\" -i"
+  ./bin/terratags -c ./examples/$CONFIG_FILE -plan "./examples/$EXAMPLE_DIR/$PLAN_FILE" -i
 else
   echo "Running directory validation with: ./bin/terratags -c ./examples/$CONFIG_FILE -dir ./examples/$EXAMPLE_DIR -i"
   ./bin/terratags -c ./examples/$CONFIG_FILE -dir ./examples/$EXAMPLE_DIR -i
@@ -0,0 +1,32 @@
+# Example Terraform configuration for allowed_values validation
+# Environment is matched case-insensitively, Tier is matched exactly
+
+resource "aws_s3_bucket" "assets" {
+  bucket = "company-assets"
+
+  tags = {
+    Name        = "assets"
+    Environment = "PROD"
+    Tier        = "web"
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "company-logs"
+
+  tags = {
+    Name        = "logs"
+    Environment = "prdo"
+    Tier        = "Data"
+  }
+}
+
+resource "aws_s3_bucket" "scratch" {
+  bucket = "company-scratch"
+
+  tags = {
+    Name        = "scratch"
+    Environment = "sandbox"
+    Tier        = "app"
+  }
+}
@@ -0,0 +1,66 @@
+provider "aws" {
+  region = "us-west-2"
+}
+
+locals {
+  asg_tags = {
+    Environment = "Production"
+    Owner       = "platform-team"
+    Project     = "Infrastructure"
+  }
+}
+
+# Tags declared as static tag blocks
+resource "aws_autoscaling_group" "web" {
+  name             = "web-asg"
+  max_size         = 3
+  min_size         = 1
+  desired_capacity = 2
+
+  tag {
+    key                 = "Name"
+    value               = "web-asg"
+    propagate_at_launch = true
+  }
+
+  tag {
+    key                 = "Environment"
+    value               = "Production"
+    propagate_at_launch = true
+  }
+
+  tag {
+    key                 = "Owner"
+    value               = "platform-team"
+    propagate_at_launch = true
+  }
+
+  tag {
+    key                 = "Project"
+    value               = "Infrastructure"
+    propagate_at_launch = true
+  }
+}
+
+# Tags generated by a dynamic block from a map
+resource "aws_autoscaling_group" "workers" {
+  name             = "workers-asg"
+  max_size         = 10
+  min_size         = 0
+  desired_capacity = 2
+
+  tag {
+    key                 = "Name"
+    value               = "workers-asg"
+    propagate_at_launch = true
+  }
+
+  dynamic "tag" {
+    for_each = local.asg_tags
+    content {
+      key                 = tag.key
+      value               = tag.value
+      propagate_at_launch = true
+    }
+  }
+}
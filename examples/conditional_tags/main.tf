@@ -0,0 +1,42 @@
+# Example Terraform configuration for conditional tag requirements
+# Environment = prod comes from the provider default_tags unless a resource overrides it
+
+provider "aws" {
+  region = "us-east-1"
+
+  default_tags {
+    tags = {
+      Environment = "prod"
+    }
+  }
+}
+
+resource "aws_s3_bucket" "reports" {
+  bucket = "company-reports"
+
+  tags = {
+    Name               = "reports"
+    DataClassification = "internal"
+    BackupPolicy       = "daily"
+  }
+}
+
+resource "aws_s3_bucket" "website" {
+  bucket = "company-website"
+
+  tags = {
+    Name               = "website"
+    Public             = "true"
+    DataClassification = "public"
+  }
+}
+
+resource "aws_instance" "sandbox" {
+  ami           = "ami-12345678"
+  instance_type = "t3.micro"
+
+  tags = {
+    Name        = "sandbox"
+    Environment = "dev"
+  }
+}
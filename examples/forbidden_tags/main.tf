@@ -0,0 +1,35 @@
+# Example Terraform configuration for forbidden tags
+# Every tag is checked against forbidden_tags, not only the required ones
+
+resource "aws_s3_bucket" "compliant" {
+  bucket = "company-compliant"
+
+  tags = {
+    Name        = "compliant"
+    Environment = "prod"
+    Owner       = "platform@example.com"
+  }
+}
+
+resource "aws_s3_bucket" "legacy" {
+  bucket = "company-legacy"
+
+  tags = {
+    Name              = "legacy"
+    Environment       = "prod"
+    Env               = "prod"
+    "aws:cost-center" = "CC-1001"
+  }
+}
+
+resource "aws_instance" "worker" {
+  ami           = "ami-12345678"
+  instance_type = "t3.micro"
+
+  tags = {
+    Name        = "worker"
+    Environment = "dev"
+    Owner       = "jane.doe@gmail.com"
+    Bootstrap   = "token=abc123"
+  }
+}
@@ -0,0 +1,25 @@
+provider "aws" {
+  region = "us-west-2"
+
+  default_tags {
+    tags = {
+      Environment = "Production"
+      Project     = "Infrastructure"
+    }
+  }
+
+  # Owner is maintained by an external asset inventory, so Terraform does not manage it.
+  # Terratags warns that the required Owner tag cannot be enforced and does not check it.
+  ignore_tags {
+    keys         = ["Owner"]
+    key_prefixes = ["kubernetes.io/"]
+  }
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "terratags-logs"
+
+  tags = {
+    Name = "logs"
+  }
+}
@@ -0,0 +1,29 @@
+provider "aws" {
+  region = "us-west-2"
+
+  default_tags {
+    tags = {
+      Environment = "Production"
+      Project     = "Infrastructure"
+    }
+  }
+}
+
+# Shared by every team, so no single owner applies
+# terratags:ignore Owner reason="shared bucket, ticket OPS-123"
+resource "aws_s3_bucket" "shared" {
+  bucket = "terratags-shared"
+
+  tags = {
+    Name = "shared"
+  }
+}
+
+resource "aws_s3_bucket" "team" {
+  bucket = "terratags-team"
+
+  tags = {
+    Name  = "team"
+    Owner = "platform-team"
+  }
+}
@@ -0,0 +1,25 @@
+provider "aws" {
+  region = "us-west-2"
+
+  default_tags {
+    tags = {
+      Project = "terratags"
+    }
+  }
+}
+
+locals {
+  tags = {
+    Environment = "dev"
+    Owner       = "network-team"
+  }
+}
+
+# Tags passed to the module reach its resources through var.tags
+module "network" {
+  source = "./modules/network"
+
+  name       = "main"
+  cidr_block = "10.0.0.0/16"
+  tags       = local.tags
+}
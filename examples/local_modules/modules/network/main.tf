@@ -0,0 +1,37 @@
+variable "name" {
+  type = string
+}
+
+variable "cidr_block" {
+  type = string
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
+
+resource "aws_vpc" "this" {
+  cidr_block = var.cidr_block
+
+  tags = merge(var.tags, {
+    Name = "${var.name}-vpc"
+  })
+}
+
+# Does not forward var.tags, so Environment and Owner are missing
+resource "aws_internet_gateway" "this" {
+  vpc_id = aws_vpc.this.id
+
+  tags = {
+    Name = "${var.name}-igw"
+  }
+}
+
+module "subnets" {
+  source = "../subnets"
+
+  vpc_id = aws_vpc.this.id
+  name   = var.name
+  tags   = merge(var.tags, { Component = "subnets" })
+}
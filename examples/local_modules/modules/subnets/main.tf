@@ -0,0 +1,21 @@
+variable "vpc_id" {
+  type = string
+}
+
+variable "name" {
+  type = string
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
+
+resource "aws_subnet" "private" {
+  vpc_id     = var.vpc_id
+  cidr_block = "10.0.1.0/24"
+
+  tags = merge(var.tags, {
+    Name = "${var.name}-private"
+  })
+}
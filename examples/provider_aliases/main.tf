@@ -0,0 +1,19 @@
+# Uses the default aws provider declared in providers.tf
+resource "aws_s3_bucket" "primary" {
+  bucket = "terratags-primary"
+
+  tags = {
+    Name = "primary"
+  }
+}
+
+# Uses the aliased provider, whose default tags do not include Owner
+resource "aws_s3_bucket" "replica" {
+  provider = aws.west
+  bucket   = "terratags-replica"
+
+  tags = {
+    Name  = "replica"
+    Owner = "platform-team"
+  }
+}
@@ -0,0 +1,23 @@
+provider "aws" {
+  region = "us-east-1"
+
+  default_tags {
+    tags = {
+      Environment = "Production"
+      Owner       = "platform-team"
+      Project     = "Infrastructure"
+    }
+  }
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+
+  default_tags {
+    tags = {
+      Environment = "DisasterRecovery"
+      Project     = "Infrastructure"
+    }
+  }
+}
@@ -0,0 +1,40 @@
+# Example Terraform configuration for provider tag constraints
+# Every tag is checked against the limits of the resource's provider
+
+resource "aws_s3_bucket" "compliant" {
+  bucket = "company-compliant"
+
+  tags = {
+    name        = "compliant"
+    Description = "Logs of the compliant service"
+  }
+}
+
+resource "aws_s3_bucket" "too_long" {
+  bucket = "company-too-long"
+
+  tags = {
+    name        = "too-long"
+    Description = "This description was pasted from the design document of the service and goes on well past the two hundred and fifty six characters AWS accepts in a tag value, so the provider rejects it when the bucket is created or when its tags are updated later on, which is only noticed at apply time instead of during review"
+  }
+}
+
+resource "google_storage_bucket" "labels" {
+  name     = "company-labels"
+  location = "US"
+
+  labels = {
+    name       = "labels"
+    CostCenter = "CC-1001"
+  }
+}
+
+resource "azurerm_resource_group" "names" {
+  name     = "company-names"
+  location = "westeurope"
+
+  tags = {
+    name          = "names"
+    "cost/center" = "CC-1001"
+  }
+}
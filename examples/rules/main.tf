@@ -0,0 +1,47 @@
+# Example Terraform configuration for rules
+# Storage resources need DataClassification, Datadog monitors need team and service instead of CostCenter
+
+resource "aws_s3_bucket" "reports" {
+  bucket = "company-reports"
+
+  tags = {
+    Name               = "reports"
+    CostCenter         = "CC-1001"
+    DataClassification = "internal"
+  }
+}
+
+resource "azurerm_storage_account" "archive" {
+  name                     = "companyarchive"
+  resource_group_name      = "storage"
+  location                 = "westeurope"
+  account_tier             = "Standard"
+  account_replication_type = "LRS"
+
+  tags = {
+    Name       = "archive"
+    CostCenter = "CC-1001"
+  }
+}
+
+resource "aws_iam_role" "deploy" {
+  name               = "deploy"
+  assume_role_policy = "{}"
+
+  tags = {
+    Name       = "deploy"
+    CostCenter = "CC-1001"
+  }
+}
+
+resource "datadog_monitor" "latency" {
+  name    = "High Latency"
+  type    = "metric alert"
+  message = "Latency is high"
+  query   = "avg(last_5m):avg:trace.http.request.duration{*} > 0.5"
+
+  tags = [
+    "Name:latency-monitor",
+    "team:platform",
+  ]
+}
@@ -0,0 +1,74 @@
+provider "aws" {
+  region = "us-west-2"
+}
+
+locals {
+  common_tags = {
+    Environment = "Production"
+    Owner       = "platform-team"
+    Project     = "Infrastructure"
+  }
+}
+
+# Volume tags declared on the instance and on its block devices
+resource "aws_instance" "web" {
+  ami           = "ami-0c55b159cbfafe1f0"
+  instance_type = "t3.micro"
+
+  tags = merge(local.common_tags, {
+    Name = "web"
+  })
+
+  volume_tags = merge(local.common_tags, {
+    Name = "web-volumes"
+  })
+}
+
+resource "aws_instance" "db" {
+  ami           = "ami-0c55b159cbfafe1f0"
+  instance_type = "t3.large"
+
+  tags = merge(local.common_tags, {
+    Name = "db"
+  })
+
+  root_block_device {
+    volume_size = 50
+    tags = merge(local.common_tags, {
+      Name = "db-root"
+    })
+  }
+
+  ebs_block_device {
+    device_name = "/dev/sdf"
+    volume_size = 200
+    tags = merge(local.common_tags, {
+      Name = "db-data"
+    })
+  }
+}
+
+# Tags applied to the instances and volumes launched from the template
+resource "aws_launch_template" "app" {
+  name_prefix   = "app-"
+  image_id      = "ami-0c55b159cbfafe1f0"
+  instance_type = "t3.micro"
+
+  tags = merge(local.common_tags, {
+    Name = "app-template"
+  })
+
+  tag_specifications {
+    resource_type = "instance"
+    tags = merge(local.common_tags, {
+      Name = "app"
+    })
+  }
+
+  tag_specifications {
+    resource_type = "volume"
+    tags = merge(local.common_tags, {
+      Name = "app-volume"
+    })
+  }
+}
@@ -0,0 +1,46 @@
+provider "aws" {
+  region = "us-west-2"
+}
+
+locals {
+  common_tags = {
+    Environment = "Production"
+    Owner       = "platform-team"
+    Project     = "Infrastructure"
+  }
+}
+
+# The instance is tagged, but its volumes are missing the Owner and Project tags
+resource "aws_instance" "web" {
+  ami           = "ami-0c55b159cbfafe1f0"
+  instance_type = "t3.micro"
+
+  tags = merge(local.common_tags, {
+    Name = "web"
+  })
+
+  volume_tags = {
+    Name        = "web-volumes"
+    Environment = "Production"
+  }
+}
+
+# Instances launched from the template are missing the Project tag
+resource "aws_launch_template" "app" {
+  name_prefix   = "app-"
+  image_id      = "ami-0c55b159cbfafe1f0"
+  instance_type = "t3.micro"
+
+  tags = merge(local.common_tags, {
+    Name = "app-template"
+  })
+
+  tag_specifications {
+    resource_type = "instance"
+    tags = {
+      Name        = "app"
+      Environment = "Production"
+      Owner       = "platform-team"
+    }
+  }
+}
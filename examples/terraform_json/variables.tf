@@ -0,0 +1,8 @@
+variable "environment" {
+  type    = string
+  default = "dev"
+}
+
+locals {
+  owner = "platform-team"
+}
@@ -0,0 +1,29 @@
+provider "aws" {
+  region = "us-west-2"
+}
+
+locals {
+  common_tags = merge(var.extra_tags, {
+    Environment = var.environment
+    Owner       = var.owner
+  })
+}
+
+# Tags resolved from locals, variable defaults and terraform.tfvars
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+
+  tags = merge(local.common_tags, {
+    Name = format("%s-vpc", var.environment)
+  })
+}
+
+# Tag values that are only known after apply are treated as present
+resource "aws_subnet" "private" {
+  vpc_id     = aws_vpc.main.id
+  cidr_block = "10.0.1.0/24"
+
+  tags = merge(local.common_tags, {
+    Name = "private-${aws_vpc.main.id}"
+  })
+}
@@ -0,0 +1,13 @@
+variable "environment" {
+  type    = string
+  default = "dev"
+}
+
+variable "owner" {
+  type = string
+}
+
+variable "extra_tags" {
+  type    = map(string)
+  default = {}
+}
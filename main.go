@@ -3,9 +3,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/terratags/terratags/pkg/config"
@@ -18,6 +20,18 @@ import (
 // Build with: go build -ldflags "-X main.version=0.1.0" -o terratags main.go
 var version = "dev"
 
+// stringSliceFlag collects the values of a flag that may be repeated
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 // Custom usage function to display both long and short forms of flags
 func printUsage() {
 	version, _, err := getVersion()
@@ -29,14 +43,22 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "Options:\n")
 	fmt.Fprintf(os.Stderr, "  --config, -c <file>       Path to the config file (JSON/YAML) containing required tag keys\n")
 	fmt.Fprintf(os.Stderr, "  --dir, -d <directory>     Path to the Terraform directory to analyze (default: \".\")\n")
+	fmt.Fprintf(os.Stderr, "  --recursive, -R           Scan every directory containing Terraform files under --dir\n")
+	fmt.Fprintf(os.Stderr, "  --exclude, -x <glob>      Skip directories matching the glob in recursive mode (repeatable)\n")
 	fmt.Fprintf(os.Stderr, "  --log-level, -l <level>   Set logging level: DEBUG, INFO, WARN, ERROR (default: ERROR)\n")
 	fmt.Fprintf(os.Stderr, "  --verbose, -v             Enable verbose output (same as --log-level=INFO)\n")
 	fmt.Fprintf(os.Stderr, "  --plan, -p <file>         Path to Terraform plan JSON file to analyze\n")
-	fmt.Fprintf(os.Stderr, "                            (includes module resource validation)\n")
+	fmt.Fprintf(os.Stderr, "                            (includes module resource validation; repeatable,\n")
+	fmt.Fprintf(os.Stderr, "                            accepts globs and name=path pairs)\n")
+	fmt.Fprintf(os.Stderr, "  --state, -s <file>        Path to Terraform state JSON file (terraform show -json) to audit\n")
 	fmt.Fprintf(os.Stderr, "  --report, -r <file>       Path to output HTML report file\n")
+	fmt.Fprintf(os.Stderr, "  --json-report, -j <file>  Path to output JSON report file\n")
 	fmt.Fprintf(os.Stderr, "  --remediate, -re          Show auto-remediation suggestions for non-compliant resources\n")
 	fmt.Fprintf(os.Stderr, "  --exemptions, -e <file>   Path to exemptions file (JSON/YAML)\n")
 	fmt.Fprintf(os.Stderr, "  --ignore-case, -i        Ignore case when comparing required tag keys\n")
+	fmt.Fprintf(os.Stderr, "  --var-file <file>         Variable definitions file (.tfvars) used to evaluate tags (repeatable)\n")
+	fmt.Fprintf(os.Stderr, "  --var <name=value>        Variable value used to evaluate tags (repeatable)\n")
+	fmt.Fprintf(os.Stderr, "  --strict                  Fail when a Terraform file cannot be fully analyzed\n")
 	fmt.Fprintf(os.Stderr, "  --help, -h                Show this help message\n")
 	fmt.Fprintf(os.Stderr, "  --version, -V             Show version information\n")
 }
@@ -46,13 +68,20 @@ func main() {
 		configFile     string
 		terraformDir   string
 		logLevel       string
-		planFile       string
+		planFiles      stringSliceFlag
+		stateFile      string
 		reportFile     string
+		jsonReportFile string
 		autoRemediate  bool
 		exemptionsFile string
 		showHelp       bool
 		showVersion    bool
 		ignoreTagCase  bool
+		varFiles       stringSliceFlag
+		vars           stringSliceFlag
+		recursive      bool
+		excludes       stringSliceFlag
+		strict         bool
 	)
 
 	// Define flags with both long and short forms
@@ -62,6 +91,12 @@ func main() {
 	flag.StringVar(&terraformDir, "dir", ".", "Path to the Terraform directory to analyze")
 	flag.StringVar(&terraformDir, "d", ".", "Path to the Terraform directory to analyze")
 
+	flag.BoolVar(&recursive, "recursive", false, "Scan every directory containing Terraform files under --dir")
+	flag.BoolVar(&recursive, "R", false, "Scan every directory containing Terraform files under --dir")
+
+	flag.Var(&excludes, "exclude", "Skip directories matching the glob in recursive mode (repeatable)")
+	flag.Var(&excludes, "x", "Skip directories matching the glob in recursive mode (repeatable)")
+
 	flag.StringVar(&logLevel, "log-level", "ERROR", fmt.Sprintf("Log level (options: %s)", strings.Join(logging.ValidLogLevels, ", ")))
 	flag.StringVar(&logLevel, "l", "ERROR", "Log level")
 
@@ -70,12 +105,18 @@ func main() {
 	flag.BoolVar(&verbose, "verbose", false, "Enable verbose output (same as --log-level=INFO)")
 	flag.BoolVar(&verbose, "v", false, "Enable verbose output (same as --log-level=INFO)")
 
-	flag.StringVar(&planFile, "plan", "", "Path to Terraform plan JSON file to analyze")
-	flag.StringVar(&planFile, "p", "", "Path to Terraform plan JSON file to analyze")
+	flag.Var(&planFiles, "plan", "Path, glob or name=path of a Terraform plan JSON file to analyze (repeatable)")
+	flag.Var(&planFiles, "p", "Path, glob or name=path of a Terraform plan JSON file to analyze (repeatable)")
+
+	flag.StringVar(&stateFile, "state", "", "Path to Terraform state JSON file (terraform show -json) to audit")
+	flag.StringVar(&stateFile, "s", "", "Path to Terraform state JSON file (terraform show -json) to audit")
 
 	flag.StringVar(&reportFile, "report", "", "Path to output HTML report file")
 	flag.StringVar(&reportFile, "r", "", "Path to output HTML report file")
 
+	flag.StringVar(&jsonReportFile, "json-report", "", "Path to output JSON report file")
+	flag.StringVar(&jsonReportFile, "j", "", "Path to output JSON report file")
+
 	flag.BoolVar(&autoRemediate, "remediate", false, "Show auto-remediation suggestions for non-compliant resources")
 	flag.BoolVar(&autoRemediate, "re", false, "Show auto-remediation suggestions for non-compliant resources")
 
@@ -91,6 +132,11 @@ func main() {
 	flag.BoolVar(&ignoreTagCase, "ignore-case", false, "Ignore case when comparing required tag keys")
 	flag.BoolVar(&ignoreTagCase, "i", false, "Ignore case when comparing required tag keys")
 
+	flag.Var(&varFiles, "var-file", "Variable definitions file (.tfvars) used to evaluate tags (repeatable)")
+	flag.Var(&vars, "var", "Variable value (name=value) used to evaluate tags (repeatable)")
+
+	flag.BoolVar(&strict, "strict", false, "Fail when a Terraform file cannot be fully analyzed")
+
 	// Override default usage function
 	flag.Usage = printUsage
 
@@ -130,6 +176,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	planMode := len(planFiles) > 0
+	if planMode && stateFile != "" {
+		logging.Error("Error: --plan and --state cannot be used together")
+		os.Exit(1)
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig(configFile)
 	if err != nil {
@@ -143,6 +195,22 @@ func main() {
 		logging.Info("Case-insensitive tag key matching enabled")
 	}
 
+	// Plan resources are located in the Terraform directory when one is given explicitly,
+	// otherwise in the directory of the plan file
+	if planMode {
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "dir" || f.Name == "d" {
+				cfg.ConfigDir = terraformDir
+			}
+		})
+	}
+
+	// Set the variable values used to evaluate tag expressions
+	cfg.VarFiles = varFiles
+	cfg.Vars = vars
+	cfg.Exclude = excludes
+	cfg.Strict = strict
+
 	// Load exemptions if provided
 	if exemptionsFile != "" {
 		exemptions, err := config.LoadExemptions(exemptionsFile)
@@ -162,9 +230,27 @@ func main() {
 	var stats validator.TagComplianceStats
 	var resources []parser.Resource
 
-	if planFile != "" {
-		// Plan validation mode - validates both direct and module resources
-		valid, violations, stats, resources = validator.ValidateTerraformPlan(planFile, cfg, logLevel)
+	if planMode {
+		plans, err := validator.ResolvePlanInputs(planFiles)
+		if err != nil {
+			logging.Error("Error: %v", err)
+			os.Exit(1)
+		}
+		if len(plans) == 1 && len(planFiles) == 1 && plans[0].Path == planFiles[0] {
+			// Plan validation mode - validates both direct and module resources
+			valid, violations, stats, resources = validator.ValidateTerraformPlan(plans[0].Path, cfg, logLevel)
+		} else {
+			// Batch plan validation - validates each plan concurrently and combines the results
+			logging.Info("Validating %d Terraform plans", len(plans))
+			valid, violations, stats, resources = validator.ValidateTerraformPlans(plans, cfg, logLevel)
+		}
+	} else if stateFile != "" {
+		// State validation mode - audits deployed resources, including module resources
+		valid, violations, stats, resources = validator.ValidateTerraformState(stateFile, cfg, logLevel)
+	} else if recursive {
+		// Validate every Terraform directory under the given directory
+		logging.Info("Recursively validating Terraform directories under: %s", terraformDir)
+		valid, violations, stats, resources = validator.ValidateDirectoryRecursive(terraformDir, cfg, logLevel)
 	} else {
 		// Validate the directory (existing logic)
 		logging.Info("Validating Terraform directory: %s", terraformDir)
@@ -174,8 +260,8 @@ func main() {
 	// Generate HTML report if requested
 	if reportFile != "" {
 		var reportContent string
-		if planFile != "" {
-			// Use unified report for plan validation (includes module resources)
+		if planMode || stateFile != "" {
+			// Use unified report for plan and state validation (includes module resources)
 			reportContent = validator.GenerateUnifiedHTMLReport(violations, stats, cfg)
 		} else {
 			// Use existing report for directory validation (backwards compatibility)
@@ -195,6 +281,29 @@ func main() {
 		}
 	}
 
+	// Generate JSON report if requested
+	if jsonReportFile != "" {
+		reportContent, err := validator.GenerateJSONReport(valid, violations, stats)
+		if err != nil {
+			logging.Error("Error generating JSON report: %v", err)
+		} else if err := os.WriteFile(jsonReportFile, reportContent, 0644); err != nil {
+			logging.Error("Error writing JSON report file: %v", err)
+		} else {
+			logging.Print("JSON report written to %s", jsonReportFile)
+		}
+	}
+
+	// Warn about required tags that provider configurations ignore
+	printIgnoredRequiredTags(stats)
+
+	// Report the parts of files that could not be analyzed
+	printDiagnostics(stats)
+
+	// Warn about required tags only known after apply
+	if cfg.UnknownTagPolicy() == config.UnknownTagsWarn {
+		printUnknownTags(stats)
+	}
+
 	// Print results
 	if !valid {
 		// Check if this is a directory/file error
@@ -203,69 +312,216 @@ func main() {
 			os.Exit(1)
 		}
 
-		logging.Print("\nTag validation issues found:")
-		for _, violation := range violations {
-			// Display missing tags
-			if len(violation.MissingTags) > 0 {
-				logging.Print("Resource %s '%s' is missing required tags: %s",
-					violation.ResourceType, violation.ResourceName, strings.Join(violation.MissingTags, ", "))
+		// Strict mode fails without violations when files could not be fully analyzed
+		if len(violations) > 0 {
+			logging.Print("\nTag validation issues found:")
+		}
+		currentPlan := ""
+		for _, group := range validator.GroupViolations(violations) {
+			// Violations of a batch of plans are listed in a section per plan
+			if group.Violations[0].Plan != currentPlan {
+				currentPlan = group.Violations[0].Plan
+				logging.Print("\nPlan %s:", currentPlan)
 			}
 
-			// Display pattern violations
-			if len(violation.PatternViolations) > 0 {
-				logging.Print("Resource %s '%s' has tag pattern violations:",
-					violation.ResourceType, violation.ResourceName)
-				for _, pv := range violation.PatternViolations {
-					logging.Print("  - Tag '%s': %s", pv.TagName, pv.ErrorMessage)
-				}
+			// Failing instances of a resource with count or for_each are listed under its address
+			if len(group.Violations) > 1 {
+				logging.Print("Resource %s has %d failing instances:", group.ConfigAddress, len(group.Violations))
 			}
 
-			// Show auto-remediation suggestions if requested
-			if autoRemediate {
-				logging.Print("\nSuggested remediation:")
-
-				// Get existing tags for this resource
-				existingTags := make(map[string]string)
-				for _, resource := range resources {
-					if resource.Type == violation.ResourceType && resource.Name == violation.ResourceName {
-						existingTags = resource.Tags
-						break
-					}
+			for _, violation := range group.Violations {
+				// Display errors for directories that could not be analyzed
+				if violation.ResourceType == "error" {
+					logging.Print("Error in %s: %s", violation.ResourcePath, strings.Join(violation.MissingTags, "; "))
+					continue
 				}
 
-				// Generate remediation code for missing tags
+				// Display missing tags
 				if len(violation.MissingTags) > 0 {
-					remediation := validator.GenerateRemediationCode(
-						violation.ResourceType,
-						violation.ResourceName,
-						violation.ResourcePath,
-						violation.MissingTags,
-						existingTags)
-					logging.Print("%s", remediation)
-
-					// Suggest provider default_tags update if appropriate
-					if strings.HasPrefix(violation.ResourceType, "aws_") {
-						logging.Print("\nAlternatively, consider using provider default_tags:")
-						logging.Print("%s", validator.SuggestProviderDefaultTagsUpdate(violation.MissingTags))
+					logging.Print("Resource %s is missing required tags: %s",
+						describeResource(violation), describeTags(violation, violation.MissingTags))
+					if violation.IsExempt {
+						logging.Print("  Exempt: %s", violation.ExemptReason)
 					}
 				}
 
-				// Generate remediation suggestions for pattern violations
+				// Display pattern violations
 				if len(violation.PatternViolations) > 0 {
-					logging.Print("\nPattern violation fixes:")
+					logging.Print("Resource %s has tag pattern violations:", describeResource(violation))
 					for _, pv := range violation.PatternViolations {
-						logging.Print("  - Update tag '%s' value from '%s' to match pattern: %s",
-							pv.TagName, pv.ActualValue, pv.ExpectedPattern)
+						logging.Print("  - Tag '%s': %s%s%s", pv.TagName, pv.ErrorMessage, describeRequirement(violation, pv.TagName), describeRange(pv.Range))
+					}
+				}
+
+				// Display tag values that are not among the allowed values
+				if len(violation.ValueViolations) > 0 {
+					logging.Print("Resource %s has tag values that are not allowed:", describeResource(violation))
+					for _, vv := range violation.ValueViolations {
+						logging.Print("  - %s%s", describeValueViolation(vv), describeRequirement(violation, vv.TagName))
+					}
+				}
+
+				// Display forbidden tags
+				if len(violation.ForbiddenTags) > 0 {
+					logging.Print("Resource %s has forbidden tags:", describeResource(violation))
+					for _, fv := range violation.ForbiddenTags {
+						logging.Print("  - %s", describeForbiddenTag(fv))
+					}
+				}
+
+				// Display tags the resource's provider would reject
+				if len(violation.ConstraintViolations) > 0 {
+					logging.Print("Resource %s has tags its provider would reject:", describeResource(violation))
+					for _, cv := range violation.ConstraintViolations {
+						logging.Print("  - %s", describeConstraintViolation(cv))
+					}
+				}
+
+				// Display required tags only known after apply, which fail under the fail policy
+				if len(violation.UnknownTags) > 0 && cfg.UnknownTagPolicy() == config.UnknownTagsFail {
+					logging.Print("Resource %s has required tags only known after apply: %s",
+						describeResource(violation), strings.Join(violation.UnknownTags, ", "))
+				}
+
+				// Display required tags removed and immutable tags changed by a planned update
+				if len(violation.RemovedTags) > 0 {
+					logging.Print("Resource %s is updated to remove required tags: %s",
+						describeResource(violation), describeTagChanges(violation.RemovedTags))
+				}
+				if len(violation.ImmutableTagChanges) > 0 {
+					logging.Print("Resource %s is updated to change immutable tags: %s",
+						describeResource(violation), describeTagChanges(violation.ImmutableTagChanges))
+				}
+
+				// Display violations at secondary tag locations
+				for _, lv := range violation.LocationViolations {
+					if len(lv.MissingTags) > 0 {
+						logging.Print("Resource %s is missing required tags at %s: %s",
+							describeResource(violation), lv.Location, describeTags(violation, lv.MissingTags))
+					}
+					if len(lv.PatternViolations) > 0 {
+						logging.Print("Resource %s has tag pattern violations at %s:", describeResource(violation), lv.Location)
+						for _, pv := range lv.PatternViolations {
+							logging.Print("  - Tag '%s': %s%s", pv.TagName, pv.ErrorMessage, describeRange(pv.Range))
+						}
+					}
+					if len(lv.ValueViolations) > 0 {
+						logging.Print("Resource %s has tag values that are not allowed at %s:", describeResource(violation), lv.Location)
+						for _, vv := range lv.ValueViolations {
+							logging.Print("  - %s", describeValueViolation(vv))
+						}
+					}
+				}
+
+				// Display tags that are not propagated to launched instances
+				if len(violation.NotPropagatedTags) > 0 {
+					logging.Print("Resource %s must set propagate_at_launch = true for tags: %s",
+						describeResource(violation), strings.Join(violation.NotPropagatedTags, ", "))
+				}
+
+				// Display tag expressions that could not be evaluated
+				if len(violation.TagErrors) > 0 {
+					logging.Print("Resource %s has tag expressions that could not be evaluated:",
+						describeResource(violation))
+					for _, tagErr := range violation.TagErrors {
+						logging.Print("  - %s", tagErr)
+					}
+				}
+
+				// Show auto-remediation suggestions if requested
+				if autoRemediate {
+					logging.Print("\nSuggested remediation:")
+
+					// Get existing tags for this resource, matching instances by address within
+					// the violation's plan
+					existingTags := make(map[string]string)
+					for _, resource := range resources {
+						if resource.Plan != violation.Plan {
+							continue
+						}
+						if violation.Address != "" && resource.Address != "" {
+							if resource.Address != violation.Address {
+								continue
+							}
+						} else if resource.Type != violation.ResourceType || resource.Name != violation.ResourceName {
+							continue
+						}
+						existingTags = resource.Tags
+						break
+					}
+
+					// Generate remediation code for missing tags
+					if len(violation.MissingTags) > 0 {
+						remediation := validator.GenerateRemediationCode(
+							violation.ResourceType,
+							violation.ResourceName,
+							violation.ResourcePath,
+							violation.MissingTags,
+							existingTags)
+						logging.Print("%s", remediation)
+
+						// Suggest provider default_tags update if appropriate
+						if strings.HasPrefix(violation.ResourceType, "aws_") {
+							logging.Print("\nAlternatively, consider using provider default_tags:")
+							logging.Print("%s", validator.SuggestProviderDefaultTagsUpdate(violation.MissingTags))
+						}
+					}
+
+					// Generate remediation suggestions for pattern violations
+					if len(violation.PatternViolations) > 0 {
+						logging.Print("\nPattern violation fixes:")
+						for _, pv := range violation.PatternViolations {
+							logging.Print("  - Update tag '%s' value from '%s' to match pattern: %s",
+								pv.TagName, pv.ActualValue, pv.ExpectedPattern)
+						}
+					}
+
+					// Forbidden tags are removed, or set in the provider configuration when inherited
+					if len(violation.ForbiddenTags) > 0 {
+						logging.Print("\nForbidden tag fixes:")
+						for _, fv := range violation.ForbiddenTags {
+							switch {
+							case fv.ByValue:
+								logging.Print("  - Change the value of tag '%s', or remove it", fv.TagName)
+							case fv.Source == "provider_default":
+								logging.Print("  - Remove tag '%s' from the provider default_tags", fv.TagName)
+							default:
+								logging.Print("  - Remove tag '%s'", fv.TagName)
+							}
+						}
+					}
+
+					// Suggest the closest allowed value for values that are not allowed
+					if len(violation.ValueViolations) > 0 {
+						logging.Print("\nAllowed value fixes:")
+						for _, vv := range violation.ValueViolations {
+							if vv.Suggestion != "" {
+								logging.Print("  - Update tag '%s' value from '%s' to '%s'", vv.TagName, vv.ActualValue, vv.Suggestion)
+							} else {
+								logging.Print("  - Update tag '%s' value from '%s' to one of: %s",
+									vv.TagName, vv.ActualValue, strings.Join(vv.AllowedValues, ", "))
+							}
+						}
 					}
 				}
 			}
 		}
 
+		// Print per-directory statistics for recursive scans
+		printDirectoryStats(stats)
+		printModuleStats(stats)
+		printPlanStats(stats)
+
 		// Print summary statistics
+		// Strict mode can fail without any resource to validate, e.g. when the only file does not parse
+		var compliancePercentage float64
+		if stats.TotalResources > 0 {
+			compliancePercentage = float64(stats.CompliantResources) / float64(stats.TotalResources) * 100
+		}
 		logging.Print("\nSummary: %d/%d resources compliant (%.1f%%)",
 			stats.CompliantResources,
 			stats.TotalResources,
-			float64(stats.CompliantResources)/float64(stats.TotalResources)*100)
+			compliancePercentage)
 
 		totalExemptResources := stats.FullyExemptResources + stats.PartiallyExemptResources
 		if totalExemptResources > 0 {
@@ -273,10 +529,212 @@ func main() {
 				totalExemptResources, stats.FullyExemptResources, stats.PartiallyExemptResources)
 		}
 
-		logging.Print("\nTag validation failed. Please fix the issues above.")
+		if len(stats.ForbiddenTagsByTag) > 0 {
+			forbidden := make([]string, 0, len(stats.ForbiddenTagsByTag))
+			for _, tag := range slices.Sorted(maps.Keys(stats.ForbiddenTagsByTag)) {
+				forbidden = append(forbidden, fmt.Sprintf("%s (%d)", tag, stats.ForbiddenTagsByTag[tag]))
+			}
+			logging.Print("Forbidden tags found: %s", strings.Join(forbidden, ", "))
+		}
+
+		if len(stats.ConstraintViolationsByProvider) > 0 {
+			rejected := make([]string, 0, len(stats.ConstraintViolationsByProvider))
+			for _, provider := range slices.Sorted(maps.Keys(stats.ConstraintViolationsByProvider)) {
+				rejected = append(rejected, fmt.Sprintf("%s (%d)", provider, stats.ConstraintViolationsByProvider[provider]))
+			}
+			logging.Print("Provider tag constraint violations: %s", strings.Join(rejected, ", "))
+		}
+
+		if cfg.Strict && len(stats.Diagnostics) > 0 {
+			logging.Print("\nStrict mode: %d files could not be fully analyzed", stats.UnanalyzedFiles())
+		}
+
+		if len(violations) == 0 {
+			logging.Print("\nTag validation failed in strict mode. Please fix the files that could not be fully analyzed.")
+		} else {
+			logging.Print("\nTag validation failed. Please fix the issues above.")
+		}
 		os.Exit(1)
 	} else {
-		logging.Print("All resources have the required tags!")
+		printDirectoryStats(stats)
+		printModuleStats(stats)
+		printPlanStats(stats)
+		// Resources in files that could not be analyzed may be missing from the results
+		if len(stats.Diagnostics) > 0 {
+			logging.Print("No violations in the files that could be analyzed (%d files had errors)", stats.UnanalyzedFiles())
+		} else {
+			logging.Print("All resources have the required tags!")
+		}
+	}
+}
+
+// describeResource names the resource of a violation, with its instance key and module call chain when
+// it has them and its position when known
+func describeResource(violation validator.TagViolation) string {
+	description := fmt.Sprintf("%s '%s%s'", violation.ResourceType, violation.ResourceName, violation.InstanceKey)
+	if violation.ModulePath != "" {
+		description += " in " + violation.ModulePath
+	}
+	return description + describeRange(violation.Range)
+}
+
+// describeTagChanges formats the tag changes of a planned update with their old and new values
+func describeTagChanges(changes []validator.TagChange) string {
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.Kind == validator.TagRemoved {
+			descriptions = append(descriptions, fmt.Sprintf("%s (was '%s')", change.TagName, change.OldValue))
+		} else {
+			descriptions = append(descriptions, fmt.Sprintf("%s ('%s' -> '%s')", change.TagName, change.OldValue, change.NewValue))
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// describeTags formats a list of tags, naming the rule or the condition requiring each tag
+func describeTags(violation validator.TagViolation, tags []string) string {
+	descriptions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		descriptions = append(descriptions, tag+describeRequirement(violation, tag))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// describeRequirement names the rule or the condition that requires a tag, or returns an
+// empty string when the tag is required globally
+func describeRequirement(violation validator.TagViolation, tag string) string {
+	if rule := violation.RuleFor(tag); rule != "" {
+		return fmt.Sprintf(" (rule %s)", rule)
+	}
+	if condition := violation.ConditionFor(tag); condition != "" {
+		return fmt.Sprintf(" (required because %s)", condition)
+	}
+	return ""
+}
+
+// describeForbiddenTag formats a forbidden tag with the reason it is forbidden
+func describeForbiddenTag(fv validator.ForbiddenTagViolation) string {
+	description := fmt.Sprintf("Tag '%s': %s", fv.TagName, fv.Reason)
+	if fv.Source == "provider_default" {
+		description += " (from provider default_tags)"
+	}
+	return description + describeRange(fv.Range)
+}
+
+// describeConstraintViolation formats a tag constraint a resource's provider would reject it for
+func describeConstraintViolation(cv validator.ConstraintViolation) string {
+	description := cv.Message
+	if cv.TagName != "" {
+		description = fmt.Sprintf("Tag '%s': %s", cv.TagName, cv.Message)
+	}
+	if cv.Source == "provider_default" {
+		description += " (from provider default_tags)"
+	}
+	return description + describeRange(cv.Range)
+}
+
+// describeValueViolation formats a tag value that is not allowed, with the closest allowed
+// value when there is one
+func describeValueViolation(vv validator.AllowedValueViolation) string {
+	description := fmt.Sprintf("Tag '%s': value '%s' is not one of %s", vv.TagName, vv.ActualValue, strings.Join(vv.AllowedValues, ", "))
+	if vv.Suggestion != "" {
+		description += fmt.Sprintf(" (did you mean '%s'?)", vv.Suggestion)
+	}
+	return description + describeRange(vv.Range)
+}
+
+// describeRange formats a source position for output, or returns an empty string when it is unknown
+func describeRange(r parser.SourceRange) string {
+	if r.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", r)
+}
+
+// printIgnoredRequiredTags warns about required tags covered by a provider's ignore_tags,
+// which Terraform cannot enforce
+func printIgnoredRequiredTags(stats validator.TagComplianceStats) {
+	for _, ignored := range stats.IgnoredRequiredTags {
+		logging.Print("Warning: required tag '%s' is covered by ignore_tags of provider %s (%s) and cannot be enforced by Terraform",
+			ignored.Tag, ignored.Provider, ignored.Path)
+	}
+}
+
+// printUnknownTags warns about resources with required tags only known after apply, which
+// cannot be checked until the plan is applied
+func printUnknownTags(stats validator.TagComplianceStats) {
+	for _, unknown := range stats.UnknownTags {
+		description := fmt.Sprintf("%s '%s%s'", unknown.ResourceType, unknown.ResourceName, unknown.InstanceKey)
+		if unknown.ModulePath != "" {
+			description += " in " + unknown.ModulePath
+		}
+		logging.Print("Warning: resource %s has required tags only known after apply: %s%s",
+			description, strings.Join(unknown.Tags, ", "), describeRange(unknown.Range))
+	}
+}
+
+// printDiagnostics prints the parse errors and skipped blocks of files that could not be fully
+// analyzed, whose resources may be missing from the results
+func printDiagnostics(stats validator.TagComplianceStats) {
+	if len(stats.Diagnostics) == 0 {
+		return
+	}
+
+	logging.Print("Warning: %d files could not be fully analyzed, resources in them may not have been checked:", stats.UnanalyzedFiles())
+	for _, diag := range stats.Diagnostics {
+		logging.Print("  - %s", diag)
+	}
+}
+
+// printDirectoryStats prints the per-directory statistics of a recursive scan
+func printDirectoryStats(stats validator.TagComplianceStats) {
+	if len(stats.Directories) == 0 {
+		return
+	}
+
+	logging.Print("\nPer-directory results:")
+	for _, dir := range stats.Directories {
+		exempt := dir.FullyExemptResources + dir.PartiallyExemptResources
+		logging.Print("  %s: %d/%d resources compliant, %d exempt, %d excluded",
+			dir.Dir, dir.CompliantResources, dir.TotalResources, exempt, dir.ExcludedResourcesCount)
+	}
+	if len(stats.Directories) > 1 {
+		logging.Print("  Total: %d/%d resources compliant across %d directories",
+			stats.CompliantResources, stats.TotalResources, len(stats.Directories))
+	}
+}
+
+// printPlanStats prints the per-plan statistics of a batch of plans
+func printPlanStats(stats validator.TagComplianceStats) {
+	if len(stats.Plans) == 0 {
+		return
+	}
+
+	logging.Print("\nPer-plan results:")
+	for _, plan := range stats.Plans {
+		exempt := plan.FullyExemptResources + plan.PartiallyExemptResources
+		status := "passed"
+		if !plan.Valid {
+			status = "failed"
+		}
+		logging.Print("  %s (%s): %d/%d resources compliant, %d exempt, %d excluded, %s",
+			plan.Name, plan.Path, plan.CompliantResources, plan.TotalResources, exempt, plan.ExcludedResourcesCount, status)
+	}
+	logging.Print("  Total: %d/%d resources compliant across %d plans",
+		stats.CompliantResources, stats.TotalResources, len(stats.Plans))
+}
+
+// printModuleStats prints the statistics of each module that creates resources
+func printModuleStats(stats validator.TagComplianceStats) {
+	if len(stats.Modules) == 0 {
+		return
+	}
+
+	logging.Print("\nPer-module results:")
+	for _, module := range stats.Modules {
+		exempt := module.FullyExemptResources + module.PartiallyExemptResources
+		logging.Print("  %s (%s): %d/%d resources compliant, %d exempt",
+			module.ModulePath, module.ModuleSource, module.CompliantResources, module.TotalResources, exempt)
 	}
 }
 
@@ -332,6 +790,13 @@ func displayResourceValidation(validation validator.ResourceValidation, prefix s
 			logging.Info("  - Tag '%s': %s", violation.TagName, violation.ErrorMessage)
 		}
 	}
+
+	if len(validation.ValueViolations) > 0 {
+		logging.Info("%s has tag values that are not allowed:", resourcePrefix)
+		for _, violation := range validation.ValueViolations {
+			logging.Info("  - %s", describeValueViolation(violation))
+		}
+	}
 }
 
 // displayModuleResourceValidation displays validation results for a module resource
@@ -349,4 +814,11 @@ func displayModuleResourceValidation(validation validator.ModuleResourceValidati
 			logging.Info("  - Tag '%s': %s", violation.TagName, violation.ErrorMessage)
 		}
 	}
+
+	if len(validation.ValueViolations) > 0 {
+		logging.Info("%s has tag values that are not allowed:", prefix)
+		for _, violation := range validation.ValueViolations {
+			logging.Info("  - %s", describeValueViolation(violation))
+		}
+	}
 }
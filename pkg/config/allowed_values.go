@@ -0,0 +1,68 @@
+package config
+
+import (
+	"strings"
+)
+
+// AllowedValues returns the values a tag may have, or nil if its values are not restricted
+func (c *Config) AllowedValues(tagName string) []string {
+	req, _ := c.requirement(tagName)
+	return req.AllowedValues
+}
+
+// ValidateAllowedValue checks a tag value against the allowed values of the tag, if it has any.
+// When the value is not allowed, it also returns the allowed value closest to it by edit
+// distance as a suggestion, or an empty string if no allowed value is close enough.
+func (c *Config) ValidateAllowedValue(tagName, tagValue string) (bool, string) {
+	req, found := c.requirement(tagName)
+	if !found || len(req.AllowedValues) == 0 {
+		return true, ""
+	}
+
+	for _, allowed := range req.AllowedValues {
+		if allowed == tagValue || (req.IgnoreValueCase && strings.EqualFold(allowed, tagValue)) {
+			return true, ""
+		}
+	}
+
+	return false, closestValue(tagValue, req.AllowedValues)
+}
+
+// closestValue returns the candidate closest to a value by case-insensitive edit distance, if
+// it is within a third of the candidate's length, and at least two edits
+func closestValue(value string, candidates []string) string {
+	best := ""
+	bestDistance := -1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+		if distance > max(2, len([]rune(candidate))/3) {
+			continue
+		}
+		if bestDistance == -1 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ConditionalRequirement requires tags on the resources whose tags meet a condition. The
+// condition maps tag names to regular expressions, and is met when every tag is set to a
+// value matching its expression.
+type ConditionalRequirement struct {
+	When    map[string]string         `json:"when" yaml:"when"`       // e.g., {"Environment": "^prod$"}
+	Require map[string]TagRequirement `json:"require" yaml:"require"` // Tags required when the condition is met
+
+	compiledWhen map[string]*regexp.Regexp
+}
+
+// conditionalRequirementConfig is a conditional requirement as written in the configuration
+// file, whose required tags may use the array or the object format
+type conditionalRequirementConfig struct {
+	When    map[string]string `json:"when" yaml:"when"`
+	Require interface{}       `json:"require" yaml:"require"`
+}
+
+// parseConditionalRequirements converts the conditional requirements of a configuration file
+func parseConditionalRequirements(conditions []conditionalRequirementConfig) ([]ConditionalRequirement, error) {
+	var parsed []ConditionalRequirement
+	for i, condition := range conditions {
+		require, err := parseRequiredTags(condition.Require)
+		if err != nil {
+			return nil, fmt.Errorf("conditional requirement %d: %w", i+1, err)
+		}
+		parsed = append(parsed, ConditionalRequirement{When: condition.When, Require: require})
+	}
+	return parsed, nil
+}
+
+// compileConditionalRequirements compiles the conditions and required tag patterns of
+// conditional requirements, and checks that each has a condition and required tags
+func compileConditionalRequirements(conditions []ConditionalRequirement) error {
+	for i := range conditions {
+		condition := &conditions[i]
+		if len(condition.When) == 0 {
+			return fmt.Errorf("conditional requirement %d must set when", i+1)
+		}
+		if len(condition.Require) == 0 {
+			return fmt.Errorf("conditional requirement %d must set require", i+1)
+		}
+		condition.compiledWhen = make(map[string]*regexp.Regexp)
+		for tagName, pattern := range condition.When {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("conditional requirement %d: invalid pattern for tag '%s': %w", i+1, tagName, err)
+			}
+			condition.compiledWhen[tagName] = compiled
+		}
+		if err := compileRequirements(condition.Require); err != nil {
+			return fmt.Errorf("conditional requirement %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// evaluate checks if the tags of a resource meet the condition, and describes what met it,
+// e.g. "Environment 'prod' matches '^prod$'". Tags whose values are not known do not meet
+// conditions.
+func (r ConditionalRequirement) evaluate(tags map[string]string, ignoreCase bool) (string, bool) {
+	var reasons []string
+	for _, tagName := range slices.Sorted(maps.Keys(r.When)) {
+		value, found := lookupTag(tags, tagName, ignoreCase)
+		if !found || !r.compiledWhen[tagName].MatchString(value) {
+			return "", false
+		}
+		reasons = append(reasons, fmt.Sprintf("%s '%s' matches '%s'", tagName, value, r.When[tagName]))
+	}
+	return strings.Join(reasons, " and "), true
+}
+
+// lookupTag looks up a tag value by name, optionally ignoring the case of tag keys
+func lookupTag(tags map[string]string, name string, ignoreCase bool) (string, bool) {
+	if value, found := tags[name]; found {
+		return value, true
+	}
+	if ignoreCase {
+		for key, value := range tags {
+			if strings.EqualFold(key, name) {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ForTags returns the configuration that applies to a resource with the given tags, which
+// are the resource's own tags and the provider default tags it inherits, without values
+// only known after apply. The tags of conditional requirements whose conditions the tags
+// meet are added to the required tags. The second result describes the condition requiring
+// each added tag. When no condition is met, the configuration itself is returned.
+func (c *Config) ForTags(tags map[string]string) (*Config, map[string]string) {
+	var scoped *Config
+	conditionTags := make(map[string]string)
+	for _, condition := range c.ConditionalTags {
+		reason, met := condition.evaluate(tags, c.IgnoreTagCase)
+		if !met {
+			continue
+		}
+		if scoped == nil {
+			copied := *c
+			copied.Required = slices.Clone(c.Required)
+			copied.RequiredTags = maps.Clone(c.RequiredTags)
+			if copied.RequiredTags == nil {
+				copied.RequiredTags = make(map[string]TagRequirement)
+			}
+			scoped = &copied
+		}
+		scoped.addRequirements(condition.Require, reason, conditionTags)
+	}
+	if scoped == nil {
+		return c, nil
+	}
+	return scoped, conditionTags
+}
@@ -11,28 +11,64 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// TagRequirement represents a tag requirement with optional pattern validation
+// TagRequirement represents a tag requirement with optional pattern and allowed values validation
 type TagRequirement struct {
 	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// AllowedValues lists the values the tag may have, if it is restricted to a set of values
+	AllowedValues []string `json:"allowed_values,omitempty" yaml:"allowed_values,omitempty"`
+	// IgnoreValueCase compares tag values to the allowed values case-insensitively
+	IgnoreValueCase bool `json:"ignore_value_case,omitempty" yaml:"ignore_value_case,omitempty"`
 	// Internal field to store compiled regex (not serialized)
 	compiledPattern *regexp.Regexp `json:"-" yaml:"-"`
 }
 
 // Config represents the configuration for tag validation
 type Config struct {
-	RequiredTags  map[string]TagRequirement `json:"required_tags" yaml:"required_tags"`
-	Exemptions    []ResourceExemption       `json:"exemptions" yaml:"exemptions"`
-	ReportPath    string                    `json:"report_path" yaml:"report_path"`
-	IgnoreTagCase bool                      `json:"-" yaml:"-"` // Runtime option, not from config file
+	RequiredTags              map[string]TagRequirement `json:"required_tags" yaml:"required_tags"`
+	Exemptions                []ResourceExemption       `json:"exemptions" yaml:"exemptions"`
+	ReportPath                string                    `json:"report_path" yaml:"report_path"`
+	PropagateAtLaunch         []string                  `json:"propagate_at_launch" yaml:"propagate_at_launch"`                 // Tags that must set propagate_at_launch = true in tag blocks
+	TagLocations              []string                  `json:"tag_locations" yaml:"tag_locations"`                             // Secondary tag locations that must also have the required tags
+	DisableInlineSuppressions bool                      `json:"disable_inline_suppressions" yaml:"disable_inline_suppressions"` // Ignore terratags:ignore comments in Terraform files
+	ImmutableTags             []string                  `json:"immutable_tags" yaml:"immutable_tags"`                           // Tags whose value must not change once set, checked in plan mode
+	UnknownTags               UnknownTagPolicy          `json:"unknown_tags" yaml:"unknown_tags"`                               // How required tags only known after apply are treated: pass, warn or fail
+	Rules                     []TagRule                 `json:"rules" yaml:"rules"`                                             // Tags required on the resources selected by each rule
+	ForbiddenTags             []ForbiddenTag            `json:"forbidden_tags" yaml:"forbidden_tags"`                           // Tags that no resource may have
+	ConditionalTags           []ConditionalRequirement  `json:"conditional_tags" yaml:"conditional_tags"`                       // Tags required when other tags have certain values
+	ProviderConstraints       map[string]bool           `json:"provider_constraints" yaml:"provider_constraints"`               // Provider families whose own tag constraints are enforced, e.g. {"aws": true}
+	IgnoreTagCase             bool                      `json:"-" yaml:"-"`                                                     // Runtime option, not from config file
+	VarFiles                  []string                  `json:"-" yaml:"-"`                                                     // Runtime option: --var-file paths
+	Vars                      []string                  `json:"-" yaml:"-"`                                                     // Runtime option: --var name=value pairs
+	Exclude                   []string                  `json:"-" yaml:"-"`                                                     // Runtime option: --exclude directory globs
+	ConfigDir                 string                    `json:"-" yaml:"-"`                                                     // Runtime option: Terraform directory a plan was created from
+	Strict                    bool                      `json:"-" yaml:"-"`                                                     // Runtime option: fail when files cannot be fully analyzed
 
 	// Legacy support - will be populated from RequiredTags for backward compatibility
 	Required []string `json:"-" yaml:"-"`
 }
 
-// ResourceExemption represents a resource that is exempt from tag requirements
+// UnknownTagPolicy decides how required tags whose values are only known after apply are treated
+type UnknownTagPolicy string
+
+const (
+	// UnknownTagsPass treats unknown tags as present (the default)
+	UnknownTagsPass UnknownTagPolicy = "pass"
+	// UnknownTagsWarn treats unknown tags as present, and warns about them
+	UnknownTagsWarn UnknownTagPolicy = "warn"
+	// UnknownTagsFail treats unknown tags as violations
+	UnknownTagsFail UnknownTagPolicy = "fail"
+)
+
+// ResourceExemption represents a resource that is exempt from tag requirements. Resources are
+// selected by type, name, address, module path and module source, each a glob pattern, or a
+// regular expression when Match is "regex". Selectors that are not set match any resource.
 type ResourceExemption struct {
 	ResourceType string   `json:"resource_type" yaml:"resource_type"`
 	ResourceName string   `json:"resource_name" yaml:"resource_name"`
+	Address      string   `json:"address" yaml:"address"`             // e.g., "module.legacy.aws_s3_bucket.logs[0]"
+	ModulePath   string   `json:"module_path" yaml:"module_path"`     // e.g., "module.legacy"
+	ModuleSource string   `json:"module_source" yaml:"module_source"` // e.g., "terraform-aws-modules/s3-bucket/aws"
+	Match        string   `json:"match" yaml:"match"`                 // "glob" (default) or "regex"
 	ExemptTags   []string `json:"exempt_tags" yaml:"exempt_tags"`
 	Reason       string   `json:"reason" yaml:"reason"`
 }
@@ -88,6 +124,32 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to compile regex patterns: %w", err)
 	}
 
+	if err := validateExemptions(config.Exemptions); err != nil {
+		return nil, err
+	}
+
+	if err := validateTagRules(config.Rules); err != nil {
+		return nil, err
+	}
+
+	if err := compileForbiddenTags(config.ForbiddenTags); err != nil {
+		return nil, err
+	}
+
+	if err := compileConditionalRequirements(config.ConditionalTags); err != nil {
+		return nil, err
+	}
+
+	if err := validateProviderConstraints(config.ProviderConstraints); err != nil {
+		return nil, err
+	}
+
+	switch config.UnknownTags {
+	case "", UnknownTagsPass, UnknownTagsWarn, UnknownTagsFail:
+	default:
+		return nil, fmt.Errorf("unknown_tags must be one of pass, warn or fail, got '%s'", config.UnknownTags)
+	}
+
 	// Populate legacy Required field for backward compatibility
 	config.populateLegacyRequired()
 
@@ -119,15 +181,24 @@ func LoadExemptions(path string) ([]ResourceExemption, error) {
 		return nil, fmt.Errorf("unsupported exemptions file format: %s", ext)
 	}
 
+	if err := validateExemptions(exemptions.Exemptions); err != nil {
+		return nil, err
+	}
+
 	return exemptions.Exemptions, nil
 }
 
-// IsExemptFromTag checks if a resource is exempt from a specific tag requirement
+// IsExemptFromTag checks if a resource is exempt from a specific tag requirement. Only
+// exemptions that select resources by type and name can match; use IsExempt to also match
+// addresses, module paths and module sources.
 func (c *Config) IsExemptFromTag(resourceType, resourceName, tagName string) (bool, string) {
-	for _, exemption := range c.Exemptions {
-		if (exemption.ResourceType == resourceType || exemption.ResourceType == "*") &&
-			(exemption.ResourceName == resourceName || exemption.ResourceName == "*") {
+	return c.IsExempt(ExemptionTarget{ResourceType: resourceType, ResourceName: resourceName}, tagName)
+}
 
+// IsExempt checks if a resource is exempt from a specific tag requirement
+func (c *Config) IsExempt(target ExemptionTarget, tagName string) (bool, string) {
+	for _, exemption := range c.Exemptions {
+		if exemption.Matches(target) {
 			for _, exemptTag := range exemption.ExemptTags {
 				if c.IgnoreTagCase {
 					// Case-insensitive comparison
@@ -146,14 +217,62 @@ func (c *Config) IsExemptFromTag(resourceType, resourceName, tagName string) (bo
 	return false, ""
 }
 
+// RequiresPropagateAtLaunch checks if a tag must be propagated at launch where it is declared in tag blocks
+func (c *Config) RequiresPropagateAtLaunch(tagName string) bool {
+	for _, name := range c.PropagateAtLaunch {
+		if name == tagName || (c.IgnoreTagCase && strings.EqualFold(name, tagName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresTagLocation checks if a secondary tag location, such as "volume_tags" or
+// "tag_specifications[instance]", must also have the required tags
+func (c *Config) RequiresTagLocation(location string) bool {
+	for _, name := range c.TagLocations {
+		if name == location {
+			return true
+		}
+	}
+	return false
+}
+
+// IsImmutableTag checks if a tag's value must not change once set
+func (c *Config) IsImmutableTag(tagName string) bool {
+	for _, name := range c.ImmutableTags {
+		if name == tagName || (c.IgnoreTagCase && strings.EqualFold(name, tagName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnknownTagPolicy returns how required tags only known after apply are treated
+func (c *Config) UnknownTagPolicy() UnknownTagPolicy {
+	if c.UnknownTags == "" {
+		return UnknownTagsPass
+	}
+	return c.UnknownTags
+}
+
 // UnmarshalJSON implements custom JSON unmarshaling to support both array and object formats
 func (c *Config) UnmarshalJSON(data []byte) error {
 	// First try to unmarshal as a struct with the new format
 	type configAlias Config
 	var temp struct {
-		RequiredTags interface{}         `json:"required_tags"`
-		Exemptions   []ResourceExemption `json:"exemptions"`
-		ReportPath   string              `json:"report_path"`
+		RequiredTags              interface{}                    `json:"required_tags"`
+		Exemptions                []ResourceExemption            `json:"exemptions"`
+		ReportPath                string                         `json:"report_path"`
+		PropagateAtLaunch         []string                       `json:"propagate_at_launch"`
+		TagLocations              []string                       `json:"tag_locations"`
+		DisableInlineSuppressions bool                           `json:"disable_inline_suppressions"`
+		ImmutableTags             []string                       `json:"immutable_tags"`
+		UnknownTags               UnknownTagPolicy               `json:"unknown_tags"`
+		Rules                     []tagRuleConfig                `json:"rules"`
+		ForbiddenTags             []ForbiddenTag                 `json:"forbidden_tags"`
+		ConditionalTags           []conditionalRequirementConfig `json:"conditional_tags"`
+		ProviderConstraints       map[string]bool                `json:"provider_constraints"`
 	}
 
 	if err := json.Unmarshal(data, &temp); err != nil {
@@ -163,46 +282,46 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	// Copy non-required_tags fields
 	c.Exemptions = temp.Exemptions
 	c.ReportPath = temp.ReportPath
-	c.RequiredTags = make(map[string]TagRequirement)
+	c.PropagateAtLaunch = temp.PropagateAtLaunch
+	c.TagLocations = temp.TagLocations
+	c.DisableInlineSuppressions = temp.DisableInlineSuppressions
+	c.ImmutableTags = temp.ImmutableTags
+	c.UnknownTags = temp.UnknownTags
+	c.ForbiddenTags = temp.ForbiddenTags
+	c.ProviderConstraints = temp.ProviderConstraints
 
 	// Handle required_tags field which can be array or object
-	if temp.RequiredTags != nil {
-		switch v := temp.RequiredTags.(type) {
-		case []interface{}:
-			// Array format (legacy)
-			for _, item := range v {
-				if tagName, ok := item.(string); ok {
-					c.RequiredTags[tagName] = TagRequirement{}
-				}
-			}
-		case map[string]interface{}:
-			// Object format (new)
-			for tagName, tagConfig := range v {
-				var req TagRequirement
-				if configMap, ok := tagConfig.(map[string]interface{}); ok {
-					if pattern, exists := configMap["pattern"]; exists {
-						if patternStr, ok := pattern.(string); ok {
-							req.Pattern = patternStr
-						}
-					}
-				}
-				c.RequiredTags[tagName] = req
-			}
-		default:
-			return fmt.Errorf("required_tags must be an array of strings or an object")
-		}
+	requiredTags, err := parseRequiredTags(temp.RequiredTags)
+	if err != nil {
+		return err
 	}
+	c.RequiredTags = requiredTags
 
-	return nil
+	c.Rules, err = parseTagRules(temp.Rules)
+	if err != nil {
+		return err
+	}
+
+	c.ConditionalTags, err = parseConditionalRequirements(temp.ConditionalTags)
+	return err
 }
 
 // UnmarshalYAML implements custom YAML unmarshaling to support both array and object formats
 func (c *Config) UnmarshalYAML(value *yaml.Node) error {
 	// First unmarshal the basic structure
 	type configAlias struct {
-		RequiredTags interface{}         `yaml:"required_tags"`
-		Exemptions   []ResourceExemption `yaml:"exemptions"`
-		ReportPath   string              `yaml:"report_path"`
+		RequiredTags              interface{}                    `yaml:"required_tags"`
+		Exemptions                []ResourceExemption            `yaml:"exemptions"`
+		ReportPath                string                         `yaml:"report_path"`
+		PropagateAtLaunch         []string                       `yaml:"propagate_at_launch"`
+		TagLocations              []string                       `yaml:"tag_locations"`
+		DisableInlineSuppressions bool                           `yaml:"disable_inline_suppressions"`
+		ImmutableTags             []string                       `yaml:"immutable_tags"`
+		UnknownTags               UnknownTagPolicy               `yaml:"unknown_tags"`
+		Rules                     []tagRuleConfig                `yaml:"rules"`
+		ForbiddenTags             []ForbiddenTag                 `yaml:"forbidden_tags"`
+		ConditionalTags           []conditionalRequirementConfig `yaml:"conditional_tags"`
+		ProviderConstraints       map[string]bool                `yaml:"provider_constraints"`
 	}
 
 	var temp configAlias
@@ -213,45 +332,129 @@ func (c *Config) UnmarshalYAML(value *yaml.Node) error {
 	// Copy non-required_tags fields
 	c.Exemptions = temp.Exemptions
 	c.ReportPath = temp.ReportPath
-	c.RequiredTags = make(map[string]TagRequirement)
+	c.PropagateAtLaunch = temp.PropagateAtLaunch
+	c.TagLocations = temp.TagLocations
+	c.DisableInlineSuppressions = temp.DisableInlineSuppressions
+	c.ImmutableTags = temp.ImmutableTags
+	c.UnknownTags = temp.UnknownTags
+	c.ForbiddenTags = temp.ForbiddenTags
+	c.ProviderConstraints = temp.ProviderConstraints
 
 	// Handle required_tags field which can be array or object
-	if temp.RequiredTags != nil {
-		switch v := temp.RequiredTags.(type) {
-		case []interface{}:
-			// Array format (legacy)
-			for _, item := range v {
-				if tagName, ok := item.(string); ok {
-					c.RequiredTags[tagName] = TagRequirement{}
-				}
+	requiredTags, err := parseRequiredTags(temp.RequiredTags)
+	if err != nil {
+		return err
+	}
+	c.RequiredTags = requiredTags
+
+	c.Rules, err = parseTagRules(temp.Rules)
+	if err != nil {
+		return err
+	}
+
+	c.ConditionalTags, err = parseConditionalRequirements(temp.ConditionalTags)
+	return err
+}
+
+// parseRequiredTags reads required_tags, in the array format or the object format
+func parseRequiredTags(value interface{}) (map[string]TagRequirement, error) {
+	requiredTags := make(map[string]TagRequirement)
+	switch v := value.(type) {
+	case nil:
+	case []interface{}:
+		// Array format (legacy), whose items may also be objects naming the tag
+		for _, item := range v {
+			tagName, req, err := parseRequiredTagItem(item)
+			if err != nil {
+				return nil, err
 			}
-		case map[string]interface{}:
-			// Object format (new)
-			for tagName, tagConfig := range v {
-				var req TagRequirement
-				if tagConfig == nil {
-					// Empty object like "Name: {}"
-					req = TagRequirement{}
-				} else if configMap, ok := tagConfig.(map[string]interface{}); ok {
-					if pattern, exists := configMap["pattern"]; exists {
-						if patternStr, ok := pattern.(string); ok {
-							req.Pattern = patternStr
-						}
-					}
-				}
-				c.RequiredTags[tagName] = req
+			requiredTags[tagName] = req
+		}
+	case map[string]interface{}:
+		// Object format (new)
+		for tagName, tagConfig := range v {
+			req, err := parseTagRequirement(tagName, tagConfig)
+			if err != nil {
+				return nil, err
 			}
-		default:
-			return fmt.Errorf("required_tags must be an array of strings or an object")
+			requiredTags[tagName] = req
 		}
+	default:
+		return nil, fmt.Errorf("required_tags must be an array of strings or an object")
 	}
+	return requiredTags, nil
+}
 
-	return nil
+// parseRequiredTagItem reads an item of the array format of required_tags, either a tag name
+// or an object with the tag's name and its requirement
+func parseRequiredTagItem(item interface{}) (string, TagRequirement, error) {
+	switch v := item.(type) {
+	case string:
+		return v, TagRequirement{}, nil
+	case map[string]interface{}:
+		tagName, ok := v["name"].(string)
+		if !ok || tagName == "" {
+			return "", TagRequirement{}, fmt.Errorf("required_tags items must be tag names or objects with a name")
+		}
+		req, err := parseTagRequirement(tagName, v)
+		return tagName, req, err
+	default:
+		return "", TagRequirement{}, fmt.Errorf("required_tags items must be tag names or objects with a name")
+	}
+}
+
+// parseTagRequirement reads the requirement of a tag from the object format of required_tags.
+// An empty requirement, like "Name: {}" or "Name:" in YAML, only requires the tag.
+func parseTagRequirement(tagName string, tagConfig interface{}) (TagRequirement, error) {
+	var req TagRequirement
+	configMap, ok := tagConfig.(map[string]interface{})
+	if !ok {
+		return req, nil
+	}
+	if pattern, exists := configMap["pattern"]; exists {
+		if patternStr, ok := pattern.(string); ok {
+			req.Pattern = patternStr
+		}
+	}
+	if allowed, exists := configMap["allowed_values"]; exists {
+		values, ok := allowed.([]interface{})
+		if !ok {
+			return req, fmt.Errorf("allowed_values of tag '%s' must be a list of strings", tagName)
+		}
+		for _, value := range values {
+			valueStr, ok := value.(string)
+			if !ok {
+				return req, fmt.Errorf("allowed_values of tag '%s' must be a list of strings", tagName)
+			}
+			req.AllowedValues = append(req.AllowedValues, valueStr)
+		}
+	}
+	if ignoreCase, exists := configMap["ignore_value_case"]; exists {
+		ignoreCaseBool, ok := ignoreCase.(bool)
+		if !ok {
+			return req, fmt.Errorf("ignore_value_case of tag '%s' must be true or false", tagName)
+		}
+		req.IgnoreValueCase = ignoreCaseBool
+	}
+	return req, nil
 }
 
 // compilePatterns compiles all regex patterns in the configuration
 func (c *Config) compilePatterns() error {
-	for tagName, req := range c.RequiredTags {
+	if err := compileRequirements(c.RequiredTags); err != nil {
+		return err
+	}
+	for _, rule := range c.Rules {
+		if err := compileRequirements(rule.RequiredTags); err != nil {
+			return fmt.Errorf("rule '%s': %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// compileRequirements compiles the regex patterns of a set of tag requirements
+func compileRequirements(requiredTags map[string]TagRequirement) error {
+	for tagName, req := range requiredTags {
 		if req.Pattern != "" {
 			compiled, err := regexp.Compile(req.Pattern)
 			if err != nil {
@@ -259,7 +462,7 @@ func (c *Config) compilePatterns() error {
 			}
 			// Update the requirement with compiled pattern
 			req.compiledPattern = compiled
-			c.RequiredTags[tagName] = req
+			requiredTags[tagName] = req
 		}
 	}
 	return nil
@@ -273,24 +476,23 @@ func (c *Config) populateLegacyRequired() {
 	}
 }
 
-// ValidateTagValue validates a tag value against its pattern if one is defined
-func (c *Config) ValidateTagValue(tagName, tagValue string) (bool, string) {
-	// Find the tag requirement (case-sensitive or case-insensitive)
-	var req TagRequirement
-	var found bool
-
+// requirement finds the requirement of a tag (case-sensitive or case-insensitive)
+func (c *Config) requirement(tagName string) (TagRequirement, bool) {
 	if c.IgnoreTagCase {
 		for name, requirement := range c.RequiredTags {
 			if strings.EqualFold(name, tagName) {
-				req = requirement
-				found = true
-				break
+				return requirement, true
 			}
 		}
-	} else {
-		req, found = c.RequiredTags[tagName]
+		return TagRequirement{}, false
 	}
+	req, found := c.RequiredTags[tagName]
+	return req, found
+}
 
+// ValidateTagValue validates a tag value against its pattern if one is defined
+func (c *Config) ValidateTagValue(tagName, tagValue string) (bool, string) {
+	req, found := c.requirement(tagName)
 	if !found || req.compiledPattern == nil {
 		// No pattern defined, so value is valid
 		return true, ""
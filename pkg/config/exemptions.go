@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ExemptionTarget identifies a resource that exemptions are matched against
+type ExemptionTarget struct {
+	ResourceType string
+	ResourceName string
+	Address      string // e.g., "module.legacy.aws_s3_bucket.logs[0]"
+	ModulePath   string // empty for root module resources
+	ModuleSource string // empty for root module resources
+}
+
+// Matches checks if an exemption selects a resource. Exemptions that only select by type and
+// name must set both, as before the other selectors existed, so an exemption without a
+// resource_name does not silently exempt every resource of its type.
+func (e ResourceExemption) Matches(target ExemptionTarget) bool {
+	if e.Address == "" && e.ModulePath == "" && e.ModuleSource == "" && (e.ResourceType == "" || e.ResourceName == "") {
+		return false
+	}
+	regex := e.Match == "regex"
+	return matchSelector(e.ResourceType, target.ResourceType, regex) &&
+		matchSelector(e.ResourceName, target.ResourceName, regex) &&
+		matchSelector(e.Address, target.Address, regex) &&
+		matchSelector(e.ModulePath, target.ModulePath, regex) &&
+		matchSelector(e.ModuleSource, target.ModuleSource, regex)
+}
+
+// matchSelector matches a value against a selector of an exemption. Selectors that are not
+// set match any value.
+func matchSelector(selector, value string, regex bool) bool {
+	if selector == "" {
+		return true
+	}
+	re, err := compileSelector(selector, regex)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(value)
+}
+
+// compiledSelectors caches compiled selectors, which are matched for every required tag of
+// every resource
+var compiledSelectors sync.Map
+
+// compileSelector compiles a selector to a regular expression matching whole values. In glob
+// selectors, * matches any sequence of characters and ? any single character; all other
+// characters, including the brackets of instance keys, match themselves.
+func compileSelector(selector string, regex bool) (*regexp.Regexp, error) {
+	key := "glob:" + selector
+	if regex {
+		key = "regex:" + selector
+	}
+	if re, ok := compiledSelectors.Load(key); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	expr := selector
+	if !regex {
+		expr = regexp.QuoteMeta(selector)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, err
+	}
+	compiledSelectors.Store(key, re)
+	return re, nil
+}
+
+// validateExemptions checks that exemptions select resources with valid patterns
+func validateExemptions(exemptions []ResourceExemption) error {
+	for i, exemption := range exemptions {
+		switch exemption.Match {
+		case "", "glob", "regex":
+		default:
+			return fmt.Errorf("exemption %d: match must be glob or regex, got '%s'", i+1, exemption.Match)
+		}
+
+		selectors := []string{exemption.ResourceType, exemption.ResourceName, exemption.Address, exemption.ModulePath, exemption.ModuleSource}
+		selected := false
+		for _, selector := range selectors {
+			if selector == "" {
+				continue
+			}
+			selected = true
+			if _, err := compileSelector(selector, exemption.Match == "regex"); err != nil {
+				return fmt.Errorf("exemption %d: invalid pattern '%s': %w", i+1, selector, err)
+			}
+		}
+		if !selected {
+			return fmt.Errorf("exemption %d must set at least one of resource_type, resource_name, address, module_path or module_source", i+1)
+		}
+	}
+	return nil
+}
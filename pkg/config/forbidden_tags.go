@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ForbiddenTag is a tag that resources must not have. Keys are matched by exact name or by a
+// regular expression, and any key matches when neither is set. With a value pattern, only
+// tags whose value matches it are forbidden.
+type ForbiddenTag struct {
+	Key          string `json:"key" yaml:"key"`                     // e.g., "Env"
+	KeyPattern   string `json:"key_pattern" yaml:"key_pattern"`     // e.g., "^aws:"
+	ValuePattern string `json:"value_pattern" yaml:"value_pattern"` // e.g., "@gmail\\.com$"
+	Reason       string `json:"reason" yaml:"reason"`
+
+	compiledKey   *regexp.Regexp
+	compiledValue *regexp.Regexp
+}
+
+// Description returns the reason a tag is forbidden, or what the forbidden tag matches when
+// it has no reason
+func (f ForbiddenTag) Description() string {
+	if f.Reason != "" {
+		return f.Reason
+	}
+	switch {
+	case f.ValuePattern == "" && f.Key != "":
+		return fmt.Sprintf("key '%s' is forbidden", f.Key)
+	case f.ValuePattern == "":
+		return fmt.Sprintf("key matches forbidden pattern '%s'", f.KeyPattern)
+	case f.Key != "":
+		return fmt.Sprintf("value of '%s' matches forbidden pattern '%s'", f.Key, f.ValuePattern)
+	case f.KeyPattern != "":
+		return fmt.Sprintf("value of a key matching '%s' matches forbidden pattern '%s'", f.KeyPattern, f.ValuePattern)
+	default:
+		return fmt.Sprintf("value matches forbidden pattern '%s'", f.ValuePattern)
+	}
+}
+
+// compileForbiddenTags compiles the patterns of forbidden tags and checks that each selects tags
+func compileForbiddenTags(forbidden []ForbiddenTag) error {
+	for i := range forbidden {
+		f := &forbidden[i]
+		if f.Key == "" && f.KeyPattern == "" && f.ValuePattern == "" {
+			return fmt.Errorf("forbidden tag %d must set key, key_pattern or value_pattern", i+1)
+		}
+		if f.Key != "" && f.KeyPattern != "" {
+			return fmt.Errorf("forbidden tag %d must set only one of key and key_pattern", i+1)
+		}
+		if f.KeyPattern != "" {
+			compiled, err := regexp.Compile(f.KeyPattern)
+			if err != nil {
+				return fmt.Errorf("forbidden tag %d: invalid key_pattern: %w", i+1, err)
+			}
+			f.compiledKey = compiled
+		}
+		if f.ValuePattern != "" {
+			compiled, err := regexp.Compile(f.ValuePattern)
+			if err != nil {
+				return fmt.Errorf("forbidden tag %d: invalid value_pattern: %w", i+1, err)
+			}
+			f.compiledValue = compiled
+		}
+	}
+	return nil
+}
+
+// matches checks if a tag is forbidden. Values only known after apply do not match value
+// patterns.
+func (f ForbiddenTag) matches(key, value string, valueKnown, ignoreCase bool) bool {
+	switch {
+	case f.Key != "":
+		if key != f.Key && !(ignoreCase && strings.EqualFold(key, f.Key)) {
+			return false
+		}
+	case f.compiledKey != nil:
+		if !f.compiledKey.MatchString(key) {
+			return false
+		}
+	}
+	if f.compiledValue != nil {
+		return valueKnown && f.compiledValue.MatchString(value)
+	}
+	return true
+}
+
+// FindForbiddenTag returns the first forbidden tag a tag matches, if any
+func (c *Config) FindForbiddenTag(key, value string, valueKnown bool) (ForbiddenTag, bool) {
+	for _, f := range c.ForbiddenTags {
+		if f.matches(key, value, valueKnown, c.IgnoreTagCase) {
+			return f, true
+		}
+	}
+	return ForbiddenTag{}, false
+}
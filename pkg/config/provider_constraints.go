@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConstrainedProviders are the provider families whose own tag constraints are checked
+var ConstrainedProviders = []string{"aws", "azure", "google", "datadog"}
+
+// validateProviderConstraints checks that provider constraint switches name known provider families
+func validateProviderConstraints(constraints map[string]bool) error {
+	for provider := range constraints {
+		if !slices.Contains(ConstrainedProviders, provider) {
+			return fmt.Errorf("provider_constraints: unknown provider '%s', must be one of %s", provider, strings.Join(ConstrainedProviders, ", "))
+		}
+	}
+	return nil
+}
+
+// ChecksProviderConstraints checks if the tag constraints of a provider family are enforced.
+// Constraints are only enforced for the families enabled in provider_constraints, since they
+// may fail configurations that passed before, e.g. Google labels with upper case keys.
+func (c *Config) ChecksProviderConstraints(provider string) bool {
+	return c.ProviderConstraints[provider]
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// TagRule requires tags on the resources its selectors match, in addition to the global
+// required tags. A resource must match every selector a rule sets, and any of the values of
+// each selector. Global required tags listed in SkipTags are not required on the resources a
+// rule matches.
+type TagRule struct {
+	Name          string                    `json:"name" yaml:"name"`
+	ResourceTypes []string                  `json:"resource_types" yaml:"resource_types"` // Globs, e.g. "aws_s3_*"
+	Providers     []string                  `json:"providers" yaml:"providers"`           // Provider names or families, e.g. "aws", "azure", "google-beta"
+	ModuleSources []string                  `json:"module_sources" yaml:"module_sources"` // Globs of the source of the module creating the resource
+	Paths         []string                  `json:"paths" yaml:"paths"`                   // Globs of the directory of the file declaring the resource
+	RequiredTags  map[string]TagRequirement `json:"required_tags" yaml:"required_tags"`
+	SkipTags      []string                  `json:"skip_tags" yaml:"skip_tags"`
+}
+
+// RuleTarget identifies a resource that rules are matched against
+type RuleTarget struct {
+	ResourceType string
+	Provider     string // Name of the provider of the resource without its alias, e.g. "aws" or "google-beta"
+	Family       string // Provider family of the resource type, e.g. "azure"; empty when unknown
+	ModuleSource string // empty for root module resources
+	Path         string // Terraform file declaring the resource; empty when it is not known
+}
+
+// tagRuleConfig is a rule as written in the configuration file, whose required_tags may use
+// the array or the object format
+type tagRuleConfig struct {
+	Name          string      `json:"name" yaml:"name"`
+	ResourceTypes []string    `json:"resource_types" yaml:"resource_types"`
+	Providers     []string    `json:"providers" yaml:"providers"`
+	ModuleSources []string    `json:"module_sources" yaml:"module_sources"`
+	Paths         []string    `json:"paths" yaml:"paths"`
+	RequiredTags  interface{} `json:"required_tags" yaml:"required_tags"`
+	SkipTags      []string    `json:"skip_tags" yaml:"skip_tags"`
+}
+
+// parseTagRules converts the rules of a configuration file. Rules without a name are named
+// after their position.
+func parseTagRules(rules []tagRuleConfig) ([]TagRule, error) {
+	var parsed []TagRule
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		requiredTags, err := parseRequiredTags(rule.RequiredTags)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s': %w", name, err)
+		}
+		parsed = append(parsed, TagRule{
+			Name:          name,
+			ResourceTypes: rule.ResourceTypes,
+			Providers:     rule.Providers,
+			ModuleSources: rule.ModuleSources,
+			Paths:         rule.Paths,
+			RequiredTags:  requiredTags,
+			SkipTags:      rule.SkipTags,
+		})
+	}
+	return parsed, nil
+}
+
+// validateTagRules checks that rules select resources with valid patterns and have unique names
+func validateTagRules(rules []TagRule) error {
+	names := make(map[string]bool)
+	for _, rule := range rules {
+		if names[rule.Name] {
+			return fmt.Errorf("rule name '%s' is used more than once", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if len(rule.ResourceTypes) == 0 && len(rule.Providers) == 0 && len(rule.ModuleSources) == 0 && len(rule.Paths) == 0 {
+			return fmt.Errorf("rule '%s' must set at least one of resource_types, providers, module_sources or paths", rule.Name)
+		}
+		if len(rule.RequiredTags) == 0 && len(rule.SkipTags) == 0 {
+			return fmt.Errorf("rule '%s' must set required_tags or skip_tags", rule.Name)
+		}
+		for _, selector := range slices.Concat(rule.ResourceTypes, rule.ModuleSources, rule.Paths) {
+			if _, err := compileSelector(selector, false); err != nil {
+				return fmt.Errorf("rule '%s': invalid pattern '%s': %w", rule.Name, selector, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Matches checks if a rule selects a resource
+func (r TagRule) Matches(target RuleTarget) bool {
+	if len(r.ModuleSources) > 0 && target.ModuleSource == "" {
+		return false
+	}
+	if len(r.Paths) > 0 && target.Path == "" {
+		return false
+	}
+	return matchAnySelector(r.ResourceTypes, target.ResourceType) &&
+		matchProvider(r.Providers, target) &&
+		matchAnySelector(r.ModuleSources, target.ModuleSource) &&
+		matchAnySelector(r.Paths, ruleDirectory(target.Path))
+}
+
+// matchAnySelector matches a value against a list of glob selectors. An empty list matches
+// any value.
+func matchAnySelector(selectors []string, value string) bool {
+	if len(selectors) == 0 {
+		return true
+	}
+	for _, selector := range selectors {
+		if matchSelector(selector, value, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchProvider matches the provider of a resource against a list of provider names and
+// families. An empty list matches any provider.
+func matchProvider(providers []string, target RuleTarget) bool {
+	if len(providers) == 0 {
+		return true
+	}
+	for _, selector := range providers {
+		if selector == target.Provider || (target.Family != "" && selector == target.Family) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleDirectory returns the directory of a file as matched by path selectors: cleaned, with
+// forward slashes and without a leading "./"
+func ruleDirectory(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Dir(filepath.Clean(path)))
+}
+
+// ForResource returns the configuration that applies to a resource: the global required
+// tags, without the tags skipped by the rules that match the resource, and with the tags
+// those rules require. A rule's requirement of a tag replaces the global one when it sets a
+// pattern or allowed values. The second result names the rule requiring each tag that is
+// not otherwise required globally, or whose requirement a rule replaces. Without matching
+// rules, the configuration itself is returned.
+func (c *Config) ForResource(target RuleTarget) (*Config, map[string]string) {
+	var matched []TagRule
+	for _, rule := range c.Rules {
+		if rule.Matches(target) {
+			matched = append(matched, rule)
+		}
+	}
+	if len(matched) == 0 {
+		return c, nil
+	}
+
+	skipped := func(name string) bool {
+		for _, rule := range matched {
+			for _, skip := range rule.SkipTags {
+				if name == skip || (c.IgnoreTagCase && strings.EqualFold(name, skip)) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	scoped := *c
+	scoped.RequiredTags = make(map[string]TagRequirement)
+	scoped.Required = nil
+	for _, name := range c.Required {
+		if !skipped(name) {
+			scoped.Required = append(scoped.Required, name)
+			scoped.RequiredTags[name] = c.RequiredTags[name]
+		}
+	}
+
+	// The first rule requiring a tag decides its requirement
+	ruleTags := make(map[string]string)
+	for _, rule := range matched {
+		scoped.addRequirements(rule.RequiredTags, rule.Name, ruleTags)
+	}
+	return &scoped, ruleTags
+}
+
+// addRequirements adds tag requirements to a configuration scoped to a resource, recording
+// the source of each requirement it adds or replaces. Tags that are already required keep
+// their requirement unless the new one sets a pattern or allowed values, and tags that
+// already have a source keep it. Under IgnoreTagCase, a requirement of a tag that is already
+// required in another case replaces that requirement rather than adding one.
+func (c *Config) addRequirements(requirements map[string]TagRequirement, source string, sources map[string]string) {
+	for _, name := range slices.Sorted(maps.Keys(requirements)) {
+		req := requirements[name]
+		index := slices.IndexFunc(c.Required, func(required string) bool {
+			return required == name || (c.IgnoreTagCase && strings.EqualFold(required, name))
+		})
+		if index >= 0 {
+			name = c.Required[index]
+		}
+		if _, named := sources[name]; named {
+			continue
+		}
+		if index >= 0 {
+			if req.Pattern == "" && len(req.AllowedValues) == 0 {
+				continue
+			}
+		} else {
+			c.Required = append(c.Required, name)
+		}
+		c.RequiredTags[name] = req
+		sources[name] = source
+	}
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// DiagnosticSeverity tells how much of a file could not be analyzed
+type DiagnosticSeverity string
+
+const (
+	// DiagnosticError means a whole file was skipped
+	DiagnosticError DiagnosticSeverity = "error"
+	// DiagnosticWarning means a block, or the tags of a block, were skipped
+	DiagnosticWarning DiagnosticSeverity = "warning"
+)
+
+// Diagnostic describes a part of a Terraform file that could not be analyzed, so resources
+// or tags in it may be missing from the results
+type Diagnostic struct {
+	Severity DiagnosticSeverity
+	Summary  string
+	Detail   string
+	Range    SourceRange // Position of the problem; only the file name is set when it is unknown
+}
+
+// String formats the diagnostic as "file:line:column: severity: summary; detail"
+func (d Diagnostic) String() string {
+	location := d.Range.Filename
+	if d.Range.StartLine > 0 {
+		location = d.Range.String()
+	}
+	msg := fmt.Sprintf("%s: %s: %s", location, d.Severity, d.Summary)
+	if d.Detail != "" {
+		msg += "; " + d.Detail
+	}
+	return msg
+}
+
+// ParseError is returned when a Terraform file cannot be parsed. It holds the parser's
+// diagnostics with their positions.
+type ParseError struct {
+	Path        string
+	Diagnostics []Diagnostic
+}
+
+func (e *ParseError) Error() string {
+	messages := make([]string, 0, len(e.Diagnostics))
+	for _, diag := range e.Diagnostics {
+		messages = append(messages, diag.String())
+	}
+	return fmt.Sprintf("failed to parse HCL: %s", strings.Join(messages, "; "))
+}
+
+// newDiagnostics converts the HCL diagnostics of a file. Diagnostics without a subject are
+// attributed to the file as a whole.
+func newDiagnostics(diags hcl.Diagnostics, path string, severity DiagnosticSeverity) []Diagnostic {
+	var result []Diagnostic
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		r := SourceRange{Filename: path}
+		if diag.Subject != nil {
+			r = newSourceRange(*diag.Subject)
+		}
+		result = append(result, Diagnostic{
+			Severity: severity,
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+			Range:    r,
+		})
+	}
+	return result
+}
+
+// tagDiagnostics returns warnings for the tag expressions of a block that could not be evaluated
+func tagDiagnostics(address string, errors []string, r SourceRange) []Diagnostic {
+	var result []Diagnostic
+	for _, msg := range errors {
+		result = append(result, Diagnostic{
+			Severity: DiagnosticWarning,
+			Summary:  fmt.Sprintf("Unable to evaluate tags of %s", address),
+			Detail:   msg,
+			Range:    r,
+		})
+	}
+	return result
+}
+
+// FileDiagnostics returns the diagnostics of an error returned when parsing a file. Errors
+// other than parse errors, such as a file that cannot be read, become a single diagnostic.
+func FileDiagnostics(path string, err error) []Diagnostic {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && len(parseErr.Diagnostics) > 0 {
+		return parseErr.Diagnostics
+	}
+	return []Diagnostic{{
+		Severity: DiagnosticError,
+		Summary:  err.Error(),
+		Range:    SourceRange{Filename: path},
+	}}
+}
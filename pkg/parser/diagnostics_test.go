@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFileWithDiagnostics(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		expectParseErr  bool
+		expectResources int
+		expect          []Diagnostic
+	}{
+		{
+			name: "Syntax error",
+			content: `resource "aws_s3_bucket" "logs" {
+  tags = {
+    Name =
+  }
+}
+`,
+			expectParseErr: true,
+			expect: []Diagnostic{{
+				Severity: DiagnosticError,
+				Summary:  "Invalid expression",
+				Range:    SourceRange{StartLine: 3, StartColumn: 11},
+			}},
+		},
+		{
+			name: "Malformed resource block",
+			content: `resource "aws_s3_bucket" {
+  tags = { Name = "logs" }
+}
+
+resource "aws_s3_bucket" "data" {
+  tags = { Name = "data" }
+}
+
+moved {
+  from = aws_s3_bucket.old
+  to   = aws_s3_bucket.data
+}
+`,
+			expectResources: 1,
+			expect: []Diagnostic{{
+				Severity: DiagnosticWarning,
+				Summary:  "Missing name for resource",
+				Range:    SourceRange{StartLine: 1, StartColumn: 26},
+			}},
+		},
+		{
+			name: "Unevaluated tag expression",
+			content: `resource "aws_s3_bucket" "logs" {
+  tags = merge({ Name = "logs" }, unknown_function())
+}
+`,
+			expectResources: 1,
+			expect: []Diagnostic{{
+				Severity: DiagnosticWarning,
+				Summary:  "Unable to evaluate tags of aws_s3_bucket.logs",
+				Range:    SourceRange{StartLine: 2, StartColumn: 3},
+			}},
+		},
+		{
+			name: "Fully analyzed",
+			content: `resource "aws_s3_bucket" "logs" {
+  tags = { Name = "logs" }
+}
+`,
+			expectResources: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "main.tf")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			resources, diagnostics, err := ParseFileWithDiagnostics(path, nil)
+			if tt.expectParseErr {
+				var parseErr *ParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("Expected a ParseError, got %v", err)
+				}
+				diagnostics = FileDiagnostics(path, err)
+			} else if err != nil {
+				t.Fatalf("ParseFileWithDiagnostics failed: %v", err)
+			}
+
+			if len(resources) != tt.expectResources {
+				t.Errorf("Expected %d resources, got %d", tt.expectResources, len(resources))
+			}
+			if len(diagnostics) != len(tt.expect) {
+				t.Fatalf("Expected %d diagnostics, got %+v", len(tt.expect), diagnostics)
+			}
+			for i, expected := range tt.expect {
+				diag := diagnostics[i]
+				if diag.Severity != expected.Severity || diag.Summary != expected.Summary {
+					t.Errorf("Expected %s %q, got %s %q", expected.Severity, expected.Summary, diag.Severity, diag.Summary)
+				}
+				if diag.Range.Filename != path || diag.Range.StartLine != expected.Range.StartLine ||
+					diag.Range.StartColumn != expected.Range.StartColumn {
+					t.Errorf("Expected diagnostic at line %d column %d of %s, got %s",
+						expected.Range.StartLine, expected.Range.StartColumn, path, diag.Range)
+				}
+			}
+		})
+	}
+}
+
+func TestFileDiagnostics_ReadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.tf")
+	_, _, err := ParseFileWithDiagnostics(path, nil)
+	if err == nil {
+		t.Fatal("Expected an error for a missing file")
+	}
+
+	diagnostics := FileDiagnostics(path, err)
+	if len(diagnostics) != 1 || diagnostics[0].Severity != DiagnosticError || diagnostics[0].Range.Filename != path {
+		t.Fatalf("Expected one error diagnostic for %s, got %+v", path, diagnostics)
+	}
+	if diagnostics[0].String() != path+": error: "+err.Error() {
+		t.Errorf("Unexpected diagnostic message %q", diagnostics[0].String())
+	}
+}
@@ -0,0 +1,375 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// UnknownTagValue is recorded as the value of tags that are only known after apply
+const UnknownTagValue = "(known after apply)"
+
+// EvalOptions holds variable values supplied on the command line
+type EvalOptions struct {
+	VarFiles []string // Paths passed with --var-file, in order
+	Vars     []string // "name=value" pairs passed with --var, in order
+}
+
+// ModuleContext holds the values available when evaluating expressions in a Terraform module directory
+type ModuleContext struct {
+	Dir        string
+	ModulePath string // e.g., "module.network.module.subnets"; empty for the root module
+	parent     *ModuleContext
+	ctx        *hcl.EvalContext
+}
+
+// modulePath returns the module call chain of the module, or an empty string for the root
+// module or when no module context is available
+func (m *ModuleContext) modulePath() string {
+	if m == nil {
+		return ""
+	}
+	return m.ModulePath
+}
+
+// address returns the Terraform address of an object in the module, such as
+// "module.network.aws_subnet.private" for "aws_subnet.private"
+func (m *ModuleContext) address(local string) string {
+	if m.modulePath() == "" {
+		return local
+	}
+	return m.ModulePath + "." + local
+}
+
+// evalContext returns the HCL evaluation context, or nil when no module context is available
+func (m *ModuleContext) evalContext() *hcl.EvalContext {
+	if m == nil {
+		return nil
+	}
+	return m.ctx
+}
+
+// tagFunctions returns the Terraform functions commonly used to build tags
+func tagFunctions() map[string]function.Function {
+	return map[string]function.Function{
+		"coalesce":  stdlib.CoalesceFunc,
+		"concat":    stdlib.ConcatFunc,
+		"format":    stdlib.FormatFunc,
+		"join":      stdlib.JoinFunc,
+		"lookup":    stdlib.LookupFunc,
+		"lower":     stdlib.LowerFunc,
+		"merge":     stdlib.MergeFunc,
+		"replace":   stdlib.ReplaceFunc,
+		"title":     stdlib.TitleFunc,
+		"tomap":     stdlib.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+		"tostring":  stdlib.MakeToFunc(cty.String),
+		"trimspace": stdlib.TrimSpaceFunc,
+		"try":       tryfunc.TryFunc,
+		"upper":     stdlib.UpperFunc,
+	}
+}
+
+// NewModuleContext builds an evaluation context for a Terraform module directory from its
+// variable defaults, terraform.tfvars, *.auto.tfvars, the given var files and vars, and locals
+func NewModuleContext(dir string, opts EvalOptions) (*ModuleContext, error) {
+	variables, localExprs, err := loadModuleDeclarations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply variable values in Terraform's order of precedence
+	for _, varFile := range autoVarFiles(dir) {
+		if err := applyVarFile(varFile, variables); err != nil {
+			return nil, err
+		}
+	}
+	for _, varFile := range opts.VarFiles {
+		if err := applyVarFile(varFile, variables); err != nil {
+			return nil, err
+		}
+	}
+	for _, assignment := range opts.Vars {
+		name, value, err := parseVarAssignment(assignment)
+		if err != nil {
+			return nil, err
+		}
+		variables[name] = value
+	}
+
+	return newModuleContext(dir, "", nil, variables, localExprs), nil
+}
+
+// newChildModuleContext builds the evaluation context of a module called from parent, with
+// the input values of the module call taking precedence over variable defaults
+func newChildModuleContext(parent *ModuleContext, dir, modulePath string, inputs map[string]cty.Value) (*ModuleContext, error) {
+	variables, localExprs, err := loadModuleDeclarations(dir)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range inputs {
+		variables[name] = value
+	}
+	return newModuleContext(dir, modulePath, parent, variables, localExprs), nil
+}
+
+// loadModuleDeclarations collects the variable defaults and local expressions of a module directory
+func loadModuleDeclarations(dir string) (map[string]cty.Value, map[string]hcl.Expression, error) {
+	files, err := FindTerraformFiles(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list Terraform files: %w", err)
+	}
+
+	parser := hclparse.NewParser()
+	variables := make(map[string]cty.Value)
+	localExprs := make(map[string]hcl.Expression)
+
+	for _, file := range files {
+		src, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			logging.Debug("Skipping %s while building evaluation context: %s", file, err)
+			continue
+		}
+		hclFile, diags := parseTerraformFile(parser, src, file)
+		if diags.HasErrors() {
+			logging.Debug("Skipping %s while building evaluation context: %s", file, diags.Error())
+			continue
+		}
+		content, _, _ := hclFile.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{
+				{Type: "variable", LabelNames: []string{"name"}},
+				{Type: "locals"},
+			},
+		})
+		for _, block := range content.Blocks {
+			switch block.Type {
+			case "variable":
+				variables[block.Labels[0]] = variableDefault(block)
+			case "locals":
+				attrs, _ := block.Body.JustAttributes()
+				for name, attr := range attrs {
+					localExprs[name] = attr.Expr
+				}
+			}
+		}
+	}
+
+	return variables, localExprs, nil
+}
+
+// newModuleContext evaluates the locals of a module and builds its evaluation context
+func newModuleContext(dir, modulePath string, parent *ModuleContext, variables map[string]cty.Value, localExprs map[string]hcl.Expression) *ModuleContext {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(variables),
+		},
+		Functions: tagFunctions(),
+	}
+
+	locals := newLocalsEvaluator(localExprs, ctx)
+	ctx.Variables["local"] = cty.ObjectVal(locals.evaluateAll())
+
+	logging.Debug("Built evaluation context for %s with %d variables and %d locals", dir, len(variables), len(localExprs))
+
+	return &ModuleContext{Dir: dir, ModulePath: modulePath, parent: parent, ctx: ctx}
+}
+
+// unsetVariableMark marks the value of a variable without a default that no var file or --var
+// sets. Values derived from it carry the mark, so tags built from unset inputs are reported
+// as unresolved rather than as known after apply.
+type unsetVariableMark struct{}
+
+// variableDefault returns the default value of a variable block, or an unknown value marked
+// as unset when no default is declared
+func variableDefault(block *hcl.Block) cty.Value {
+	content, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "default"}},
+	})
+	attr, exists := content.Attributes["default"]
+	if !exists {
+		return cty.DynamicVal.Mark(unsetVariableMark{})
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.DynamicVal
+	}
+	return value
+}
+
+// autoVarFiles returns terraform.tfvars and *.auto.tfvars files in the order Terraform loads them
+func autoVarFiles(dir string) []string {
+	var files []string
+	for _, name := range []string{"terraform.tfvars", "terraform.tfvars.json"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+
+	var autoFiles []string
+	for _, pattern := range []string{"*.auto.tfvars", "*.auto.tfvars.json"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		autoFiles = append(autoFiles, matches...)
+	}
+	sort.Strings(autoFiles)
+
+	return append(files, autoFiles...)
+}
+
+// applyVarFile reads a tfvars file and sets its values on the variables map
+func applyVarFile(path string, variables map[string]cty.Value) error {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read var file: %w", err)
+	}
+
+	file, diags := parseTerraformFile(hclparse.NewParser(), content, path)
+	if diags.HasErrors() {
+		return fmt.Errorf("failed to parse var file %s: %s", path, diags.Error())
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return fmt.Errorf("failed to read var file %s: %s", path, diags.Error())
+	}
+	for name, attr := range attrs {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return fmt.Errorf("failed to evaluate %s in var file %s: %s", name, path, diags.Error())
+		}
+		variables[name] = value
+	}
+
+	logging.Debug("Loaded %d variable values from %s", len(attrs), path)
+	return nil
+}
+
+// parseVarAssignment parses a "name=value" assignment passed with --var. Values that
+// look like HCL maps or lists are parsed as expressions; everything else is a string.
+func parseVarAssignment(assignment string) (string, cty.Value, error) {
+	name, raw, found := strings.Cut(assignment, "=")
+	name = strings.TrimSpace(name)
+	if !found || name == "" {
+		return "", cty.NilVal, fmt.Errorf("invalid --var value %q: expected name=value", assignment)
+	}
+
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		expr, diags := hclsyntax.ParseExpression([]byte(trimmed), "<var "+name+">", hcl.InitialPos)
+		if diags.HasErrors() {
+			return "", cty.NilVal, fmt.Errorf("invalid --var value for %s: %s", name, diags.Error())
+		}
+		value, diags := expr.Value(nil)
+		if diags.HasErrors() {
+			return "", cty.NilVal, fmt.Errorf("invalid --var value for %s: %s", name, diags.Error())
+		}
+		return name, value, nil
+	}
+
+	return name, cty.StringVal(raw), nil
+}
+
+// localsEvaluator evaluates locals in dependency order, since locals may refer to each other
+type localsEvaluator struct {
+	exprs      map[string]hcl.Expression
+	ctx        *hcl.EvalContext
+	values     map[string]cty.Value
+	evaluating map[string]bool
+}
+
+func newLocalsEvaluator(exprs map[string]hcl.Expression, ctx *hcl.EvalContext) *localsEvaluator {
+	return &localsEvaluator{
+		exprs:      exprs,
+		ctx:        ctx,
+		values:     make(map[string]cty.Value),
+		evaluating: make(map[string]bool),
+	}
+}
+
+// evaluateAll evaluates every local and returns their values
+func (l *localsEvaluator) evaluateAll() map[string]cty.Value {
+	for name := range l.exprs {
+		l.evaluate(name)
+	}
+	return l.values
+}
+
+// evaluate evaluates a single local after the locals it refers to
+func (l *localsEvaluator) evaluate(name string) cty.Value {
+	if value, done := l.values[name]; done {
+		return value
+	}
+	expr, exists := l.exprs[name]
+	if !exists || l.evaluating[name] {
+		// Undeclared or self-referencing locals cannot be resolved
+		return cty.DynamicVal
+	}
+	l.evaluating[name] = true
+	defer delete(l.evaluating, name)
+
+	dependencies := make(map[string]cty.Value)
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() != "local" || len(traversal) < 2 {
+			continue
+		}
+		if attr, ok := traversal[1].(hcl.TraverseAttr); ok {
+			dependencies[attr.Name] = l.evaluate(attr.Name)
+		}
+	}
+
+	ctx := l.ctx.NewChild()
+	ctx.Variables = map[string]cty.Value{"local": cty.ObjectVal(dependencies)}
+
+	value, diags := expr.Value(withUnknownReferences(expr, ctx))
+	if diags.HasErrors() {
+		logging.Debug("Unable to evaluate local.%s: %s", name, diags.Error())
+		value = cty.DynamicVal
+	}
+	l.values[name] = value
+	return value
+}
+
+// withUnknownReferences returns a child context in which every reference that the
+// context cannot resolve, such as resource attributes, data sources, module outputs,
+// count and each, evaluates to an unknown value
+func withUnknownReferences(expr hcl.Expression, ctx *hcl.EvalContext) *hcl.EvalContext {
+	if ctx == nil {
+		return nil
+	}
+
+	unknowns := make(map[string]cty.Value)
+	for _, traversal := range expr.Variables() {
+		root := traversal.RootName()
+		if _, known := unknowns[root]; known || contextHasVariable(ctx, root) {
+			continue
+		}
+		unknowns[root] = cty.DynamicVal
+	}
+	if len(unknowns) == 0 {
+		return ctx
+	}
+
+	child := ctx.NewChild()
+	child.Variables = unknowns
+	return child
+}
+
+// contextHasVariable reports whether a variable is defined in a context or its parents
+func contextHasVariable(ctx *hcl.EvalContext, name string) bool {
+	for c := ctx; c != nil; c = c.Parent() {
+		if _, exists := c.Variables[name]; exists {
+			return true
+		}
+	}
+	return false
+}
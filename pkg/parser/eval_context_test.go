@@ -0,0 +1,215 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFileWithContext_ResolvesReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"variables.tf": `
+variable "environment" {
+  default = "dev"
+}
+
+variable "owner" {}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
+`,
+		"locals.tf": `
+locals {
+  name_prefix = upper(var.environment)
+  common_tags = merge(var.tags, {
+    Environment = var.environment
+    Owner       = var.owner
+  })
+}
+`,
+		"terraform.tfvars": `
+tags = {
+  Project = "terratags"
+}
+`,
+		"prod.auto.tfvars": `
+environment = "prod"
+`,
+		"main.tf": `
+resource "aws_s3_bucket" "data" {
+  tags = merge(local.common_tags, {
+    Name = format("%s-data", local.name_prefix)
+  })
+}
+
+resource "aws_instance" "web" {
+  tags = {
+    Name   = "web"
+    Subnet = aws_subnet.main.id
+  }
+}
+
+resource "aws_vpc" "main" {
+  tags = data.aws_default_tags.current.tags
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	mctx, err := NewModuleContext(tmpDir, EvalOptions{Vars: []string{"owner=platform"}})
+	if err != nil {
+		t.Fatalf("NewModuleContext failed: %v", err)
+	}
+
+	resources, err := ParseFileWithContext(filepath.Join(tmpDir, "main.tf"), mctx)
+	if err != nil {
+		t.Fatalf("ParseFileWithContext failed: %v", err)
+	}
+	if len(resources) != 3 {
+		t.Fatalf("Expected 3 resources, got %d", len(resources))
+	}
+
+	bucket := resources[0]
+	expected := map[string]string{
+		"Environment": "prod",
+		"Owner":       "platform",
+		"Project":     "terratags",
+		"Name":        "PROD-data",
+	}
+	if len(bucket.Tags) != len(expected) {
+		t.Errorf("Expected %d tags, got %d: %v", len(expected), len(bucket.Tags), bucket.Tags)
+	}
+	for key, expectedValue := range expected {
+		if actualValue := bucket.Tags[key]; actualValue != expectedValue {
+			t.Errorf("Expected tag %s to have value %q, got %q", key, expectedValue, actualValue)
+		}
+	}
+	if len(bucket.TagErrors) > 0 || len(bucket.UnknownTags) > 0 || bucket.TagsUnknown {
+		t.Errorf("Expected all tags to be resolved, got errors %v and unknown %v", bucket.TagErrors, bucket.UnknownTags)
+	}
+
+	instance := resources[1]
+	if instance.Tags["Name"] != "web" || !instance.UnknownTags["Subnet"] || instance.UnknownTags["Name"] {
+		t.Errorf("Expected Subnet to be unknown and Name to be known, got %v (unknown %v)", instance.Tags, instance.UnknownTags)
+	}
+	if len(instance.TagErrors) > 0 {
+		t.Errorf("Expected no tag errors for resource references, got %v", instance.TagErrors)
+	}
+
+	vpc := resources[2]
+	if !vpc.TagsUnknown || len(vpc.TagErrors) > 0 {
+		t.Errorf("Expected tags from a data source to be unknown, got unknown=%v errors=%v", vpc.TagsUnknown, vpc.TagErrors)
+	}
+}
+
+func TestParseFileWithDiagnostics_UnsetVariables(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `
+variable "owner" {}
+
+variable "tags" {
+  type = map(string)
+}
+
+locals {
+  common_tags = {
+    Environment = "dev"
+    Owner       = var.owner
+  }
+}
+
+resource "aws_s3_bucket" "data" {
+  tags = merge(local.common_tags, {
+    Name = "data"
+    Team = "${var.owner}-team"
+  })
+}
+
+resource "aws_vpc" "main" {
+  tags = var.tags
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write main.tf: %v", err)
+	}
+
+	mctx, err := NewModuleContext(tmpDir, EvalOptions{})
+	if err != nil {
+		t.Fatalf("NewModuleContext failed: %v", err)
+	}
+	resources, diagnostics, err := ParseFileWithDiagnostics(filepath.Join(tmpDir, "main.tf"), mctx)
+	if err != nil {
+		t.Fatalf("ParseFileWithDiagnostics failed: %v", err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(resources))
+	}
+
+	bucket := resources[0]
+	if _, exists := bucket.Tags["Owner"]; exists {
+		t.Errorf("Expected Owner from an unset variable to be left out, got %v", bucket.Tags)
+	}
+	if _, exists := bucket.Tags["Team"]; exists {
+		t.Errorf("Expected Team from an unset variable to be left out, got %v", bucket.Tags)
+	}
+	if bucket.Tags["Environment"] != "dev" || bucket.Tags["Name"] != "data" || len(bucket.UnknownTags) > 0 {
+		t.Errorf("Expected the other tags to be resolved, got %v (unknown %v)", bucket.Tags, bucket.UnknownTags)
+	}
+	if len(bucket.TagErrors) != 2 {
+		t.Errorf("Expected 2 tag errors, got %v", bucket.TagErrors)
+	}
+
+	vpc := resources[1]
+	if vpc.TagsUnknown || len(vpc.TagErrors) != 1 {
+		t.Errorf("Expected tags from an unset variable to be unresolved, got unknown=%v errors=%v", vpc.TagsUnknown, vpc.TagErrors)
+	}
+
+	if len(diagnostics) != 3 {
+		t.Errorf("Expected 3 diagnostics, got %v", diagnostics)
+	}
+}
+
+func TestParseProviderBlocksWithContext_Locals(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `
+locals {
+  default_tags = {
+    Environment = "dev"
+    Owner       = "platform"
+  }
+}
+
+provider "aws" {
+  default_tags {
+    tags = local.default_tags
+  }
+}
+`
+	tmpFile := filepath.Join(tmpDir, "providers.tf")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	mctx, err := NewModuleContext(tmpDir, EvalOptions{})
+	if err != nil {
+		t.Fatalf("NewModuleContext failed: %v", err)
+	}
+
+	providers, err := ParseProviderBlocksWithContext(tmpFile, mctx)
+	if err != nil {
+		t.Fatalf("ParseProviderBlocksWithContext failed: %v", err)
+	}
+	if len(providers) != 1 {
+		t.Fatalf("Expected 1 provider, got %d", len(providers))
+	}
+	if providers[0].DefaultTags["Environment"] != "dev" || providers[0].DefaultTags["Owner"] != "platform" {
+		t.Errorf("Expected default tags from locals, got %v", providers[0].DefaultTags)
+	}
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseProviderBlocks_IgnoreTags(t *testing.T) {
+	content := `
+locals {
+  external_prefixes = ["kubernetes.io/", "aws:"]
+}
+
+provider "aws" {
+  ignore_tags {
+    keys         = ["LastScanned"]
+    key_prefixes = local.external_prefixes
+  }
+}
+
+provider "awscc" {
+  ignore_tags {
+    keys = ["Ignored"]
+  }
+}
+`
+	tmpDir := t.TempDir()
+	tmpFile := filepath.Join(tmpDir, "providers.tf")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	mctx, err := NewModuleContext(tmpDir, EvalOptions{})
+	if err != nil {
+		t.Fatalf("NewModuleContext failed: %v", err)
+	}
+	providers, err := ParseProviderBlocksWithContext(tmpFile, mctx)
+	if err != nil {
+		t.Fatalf("ParseProviderBlocksWithContext failed: %v", err)
+	}
+	if len(providers) != 1 {
+		t.Fatalf("Expected 1 provider, got %+v", providers)
+	}
+
+	expected := IgnoreTags{
+		Keys:        []string{"LastScanned"},
+		KeyPrefixes: []string{"kubernetes.io/", "aws:"},
+	}
+	if !reflect.DeepEqual(providers[0].IgnoreTags, expected) {
+		t.Errorf("Expected ignore_tags %+v, got %+v", expected, providers[0].IgnoreTags)
+	}
+
+	tests := []struct {
+		key        string
+		ignoreCase bool
+		expected   bool
+	}{
+		{key: "LastScanned", expected: true},
+		{key: "lastscanned", expected: false},
+		{key: "lastscanned", ignoreCase: true, expected: true},
+		{key: "kubernetes.io/cluster/main", expected: true},
+		{key: "Owner", expected: false},
+	}
+	for _, tt := range tests {
+		if got := providers[0].IgnoreTags.Matches(tt.key, tt.ignoreCase); got != tt.expected {
+			t.Errorf("Matches(%q, %v) = %v, expected %v", tt.key, tt.ignoreCase, got, tt.expected)
+		}
+	}
+}
+
+func TestParseTerraformPlan_IgnoreTags(t *testing.T) {
+	planContent := `{
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.default",
+      "type": "aws_s3_bucket",
+      "name": "default",
+      "change": {
+        "actions": ["create"],
+        "after": {"tags": {"Name": "default", "LastScanned": "today"}}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.west",
+      "type": "aws_s3_bucket",
+      "name": "west",
+      "change": {
+        "actions": ["create"],
+        "after": {"tags": {"Name": "west", "LastScanned": "today"}}
+      }
+    }
+  ],
+  "configuration": {
+    "provider_config": {
+      "aws": {
+        "name": "aws",
+        "expressions": {
+          "ignore_tags": [
+            {"keys": {"constant_value": ["LastScanned"]}}
+          ]
+        }
+      },
+      "aws.west": {
+        "name": "aws",
+        "alias": "west",
+        "expressions": {"region": {"constant_value": "us-west-2"}}
+      }
+    },
+    "root_module": {
+      "resources": [
+        {"address": "aws_s3_bucket.default", "provider_config_key": "aws"},
+        {"address": "aws_s3_bucket.west", "provider_config_key": "aws.west"}
+      ]
+    }
+  }
+}`
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planFile, []byte(planContent), 0644); err != nil {
+		t.Fatalf("Failed to write plan file: %v", err)
+	}
+
+	providers, err := ParseTerraformPlanProviders(planFile)
+	if err != nil {
+		t.Fatalf("ParseTerraformPlanProviders failed: %v", err)
+	}
+	if len(providers) != 1 || providers[0].Key() != "aws" {
+		t.Fatalf("Expected only the aws provider to ignore tags, got %+v", providers)
+	}
+
+	resources, err := ParseTerraformPlan(planFile, "ERROR")
+	if err != nil {
+		t.Fatalf("ParseTerraformPlan failed: %v", err)
+	}
+	for _, resource := range resources {
+		_, hasIgnored := resource.Tags["LastScanned"]
+		switch resource.Name {
+		case "default":
+			if hasIgnored || resource.IgnoreTags.IsEmpty() {
+				t.Errorf("Expected LastScanned to be ignored, got tags %v", resource.Tags)
+			}
+		case "west":
+			if resource.Provider != "aws.west" {
+				t.Errorf("Expected provider aws.west, got %q", resource.Provider)
+			}
+			if !hasIgnored {
+				t.Errorf("Expected LastScanned to be kept for aws.west, got tags %v", resource.Tags)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTerraformPlanWithModules_InstanceKeys(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs[1]",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "index": 1,
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}
+    },
+    {
+      "address": "aws_s3_bucket.data",
+      "type": "aws_s3_bucket",
+      "name": "data",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "data"}}}
+    },
+    {
+      "address": "module.app[\"api\"].aws_sqs_queue.this[\"a.b\"]",
+      "module_address": "module.app[\"api\"]",
+      "type": "aws_sqs_queue",
+      "name": "this",
+      "index": "a.b",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "queue"}}}
+    }
+  ],
+  "configuration": {
+    "root_module": {
+      "module_calls": {
+        "app": {"source": "./modules/app"}
+      }
+    }
+  }
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	direct, modules, err := ParseTerraformPlanWithModules(planPath, "ERROR")
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+
+	keys := make(map[string]string)
+	for _, resource := range direct {
+		keys[resource.Address] = resource.InstanceKey
+	}
+	if keys["aws_s3_bucket.logs[1]"] != "[1]" {
+		t.Errorf("Expected instance key [1], got %q", keys["aws_s3_bucket.logs[1]"])
+	}
+	if keys["aws_s3_bucket.data"] != "" {
+		t.Errorf("Expected no instance key, got %q", keys["aws_s3_bucket.data"])
+	}
+
+	if len(modules) != 1 {
+		t.Fatalf("Expected 1 module resource, got %d", len(modules))
+	}
+	if modules[0].InstanceKey != `["a.b"]` {
+		t.Errorf(`Expected instance key ["a.b"], got %q`, modules[0].InstanceKey)
+	}
+	if modules[0].ModuleName != "app" || modules[0].ModuleSource != "./modules/app" {
+		t.Errorf("Expected module app from ./modules/app, got %s from %s", modules[0].ModuleName, modules[0].ModuleSource)
+	}
+}
+
+func TestConfigAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{"aws_s3_bucket.logs", "aws_s3_bucket.logs"},
+		{"aws_s3_bucket.logs[0]", "aws_s3_bucket.logs"},
+		{`module.app["api"].aws_sqs_queue.this["orders"]`, "module.app.aws_sqs_queue.this"},
+		{`aws_sqs_queue.this["a]b"]`, "aws_sqs_queue.this"},
+		{`aws_sqs_queue.this["a\"]"]`, "aws_sqs_queue.this"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ConfigAddress(tt.address); got != tt.expected {
+			t.Errorf("ConfigAddress(%q) = %q, expected %q", tt.address, got, tt.expected)
+		}
+	}
+}
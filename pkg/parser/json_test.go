@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFile_TerraformJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"variables.tf": `
+variable "owner" {
+  default = "platform"
+}
+`,
+		"main.tf.json": `{
+  "provider": {
+    "aws": [
+      {
+        "region": "us-west-2",
+        "default_tags": {
+          "tags": {
+            "Environment": "dev"
+          }
+        }
+      }
+    ],
+    "google": {
+      "default_labels": {
+        "team": "data"
+      }
+    },
+    "azapi": {
+      "default_tags": {
+        "Owner": "azure-team"
+      }
+    }
+  },
+  "resource": {
+    "aws_s3_bucket": {
+      "data": {
+        "bucket": "data",
+        "tags": {
+          "Name": "data bucket",
+          "Owner": "${var.owner}"
+        }
+      }
+    },
+    "awscc_s3_bucket": {
+      "cc": {
+        "tags": [
+          {"key": "Name", "value": "cc"}
+        ]
+      }
+    }
+  },
+  "module": {
+    "network": {
+      "source": "./modules/network",
+      "tags": {
+        "Project": "terratags"
+      }
+    }
+  }
+}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	jsonFile := filepath.Join(tmpDir, "main.tf.json")
+	mctx, err := NewModuleContext(tmpDir, EvalOptions{})
+	if err != nil {
+		t.Fatalf("NewModuleContext failed: %v", err)
+	}
+
+	resources, err := ParseFileWithContext(jsonFile, mctx)
+	if err != nil {
+		t.Fatalf("ParseFileWithContext failed: %v", err)
+	}
+
+	byName := make(map[string]Resource)
+	for _, resource := range resources {
+		byName[resource.Type+"."+resource.Name] = resource
+	}
+	if len(byName) != 3 {
+		t.Fatalf("Expected 3 resources, got %d: %v", len(byName), byName)
+	}
+
+	bucket := byName["aws_s3_bucket.data"]
+	if bucket.Tags["Name"] != "data bucket" || bucket.Tags["Owner"] != "platform" {
+		t.Errorf("Unexpected aws_s3_bucket tags: %v", bucket.Tags)
+	}
+	if byName["awscc_s3_bucket.cc"].Tags["Name"] != "cc" {
+		t.Errorf("Unexpected awscc_s3_bucket tags: %v", byName["awscc_s3_bucket.cc"].Tags)
+	}
+	if byName["module.network"].Tags["Project"] != "terratags" {
+		t.Errorf("Unexpected module tags: %v", byName["module.network"].Tags)
+	}
+
+	providers, err := ParseProviderBlocksWithContext(jsonFile, mctx)
+	if err != nil {
+		t.Fatalf("ParseProviderBlocksWithContext failed: %v", err)
+	}
+	expected := map[string]map[string]string{
+		"aws":    {"Environment": "dev"},
+		"google": {"team": "data"},
+		"azapi":  {"Owner": "azure-team"},
+	}
+	if len(providers) != len(expected) {
+		t.Fatalf("Expected %d providers, got %d", len(expected), len(providers))
+	}
+	for _, provider := range providers {
+		for key, value := range expected[provider.Name] {
+			if provider.DefaultTags[key] != value {
+				t.Errorf("Expected %s default tag %s=%s, got %v", provider.Name, key, value, provider.DefaultTags)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LocalModule represents a module call with a local source that is followed in directory mode
+type LocalModule struct {
+	Name       string            // e.g., "subnets"
+	Source     string            // e.g., "./modules/subnets"
+	Dir        string            // Module directory resolved from the source
+	ModulePath string            // e.g., "module.network.module.subnets"
+	CallPath   string            // File containing the module block
+	Tags       map[string]string // Tags passed to the module call
+	Context    *ModuleContext    // Evaluation context holding the inputs of the module call
+	// Providers maps the provider configurations used in the module to those of the caller,
+	// from the providers argument. It is nil when the module inherits the caller's defaults.
+	Providers map[string]string
+}
+
+// moduleMetaArguments are the module block arguments that are not module input variables
+var moduleMetaArguments = []hcl.AttributeSchema{
+	{Name: "source"},
+	{Name: "version"},
+	{Name: "providers"},
+	{Name: "count"},
+	{Name: "for_each"},
+	{Name: "depends_on"},
+}
+
+// ParseLocalModulesWithContext parses a Terraform file and returns its module calls with local
+// sources. The inputs of each call are evaluated in the calling module's context and become
+// the variable values of the called module.
+func ParseLocalModulesWithContext(path string, mctx *ModuleContext) ([]LocalModule, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	file, diags := parseTerraformFile(hclparse.NewParser(), content, path)
+	if diags.HasErrors() {
+		return nil, &ParseError{Path: path, Diagnostics: newDiagnostics(diags, path, DiagnosticError)}
+	}
+
+	bodyContent, _, _ := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "module", LabelNames: []string{"name"}},
+		},
+	})
+
+	ctx := mctx.evalContext()
+	var modules []LocalModule
+
+	for _, block := range bodyContent.Blocks {
+		name := block.Labels[0]
+		modulePath := childModulePath(mctx, name)
+
+		moduleContent, remain, _ := block.Body.PartialContent(&hcl.BodySchema{Attributes: moduleMetaArguments})
+		sourceAttr, exists := moduleContent.Attributes["source"]
+		if !exists {
+			continue
+		}
+		sourceValue, diags := sourceAttr.Expr.Value(nil)
+		if diags.HasErrors() || sourceValue.Type() != cty.String || sourceValue.IsNull() {
+			logging.Debug("Not following %s: source is not a literal string", modulePath)
+			continue
+		}
+		source := sourceValue.AsString()
+		if IsExternalModule(source) {
+			logging.Debug("Not following %s with non-local source %s", modulePath, source)
+			continue
+		}
+
+		dir := filepath.Join(filepath.Dir(path), source)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			logging.Warn("Source directory %s of %s not found", dir, modulePath)
+			continue
+		}
+		if mctx.callsDir(dir) {
+			logging.Warn("Not following %s: %s is already part of the module call chain", modulePath, dir)
+			continue
+		}
+
+		inputs := make(map[string]cty.Value)
+		attrs, _ := remain.JustAttributes()
+		for inputName, attr := range attrs {
+			value, diags := attr.Expr.Value(withUnknownReferences(attr.Expr, ctx))
+			if diags.HasErrors() {
+				logging.Debug("Unable to evaluate input %s of %s: %s", inputName, modulePath, diags.Error())
+				value = cty.DynamicVal
+			}
+			inputs[inputName] = value
+		}
+
+		moduleContext, err := newChildModuleContext(mctx, dir, modulePath, inputs)
+		if err != nil {
+			logging.Warn("Unable to load %s from %s: %s", modulePath, dir, err)
+			continue
+		}
+
+		tags, _ := extractTagsFromBody(block.Body, "tags", tagFormatMap, ctx, content)
+		modules = append(modules, LocalModule{
+			Name:       name,
+			Source:     source,
+			Dir:        dir,
+			ModulePath: modulePath,
+			CallPath:   path,
+			Tags:       tags.Tags,
+			Context:    moduleContext,
+			Providers:  moduleProviders(moduleContent.Attributes["providers"], modulePath),
+		})
+	}
+
+	return modules, nil
+}
+
+// LoadLocalModule parses the Terraform files of a local module with the inputs of its module
+// call. It returns the module's resources, its own provider configurations, the local modules
+// it calls in turn and diagnostics for the parts of its files that could not be analyzed.
+func LoadLocalModule(module LocalModule) ([]ModuleResource, []ProviderConfig, []LocalModule, []Diagnostic, error) {
+	files, err := FindTerraformFiles(module.Dir)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to list Terraform files: %w", err)
+	}
+
+	var resources []ModuleResource
+	var providers []ProviderConfig
+	var modules []LocalModule
+	var diagnostics []Diagnostic
+
+	for _, file := range files {
+		logging.Info("Analyzing file: %s (%s)", file, module.ModulePath)
+
+		fileResources, fileDiagnostics, err := ParseFileWithDiagnostics(file, module.Context)
+		if err != nil {
+			logging.Warn("Error parsing file %s: %s", file, err)
+			diagnostics = append(diagnostics, FileDiagnostics(file, err)...)
+			continue
+		}
+		diagnostics = append(diagnostics, fileDiagnostics...)
+		for _, resource := range fileResources {
+			resource.ModuleSource = module.Source
+			resources = append(resources, newModuleResource(resource, extractModuleName(module.ModulePath)))
+		}
+
+		fileProviders, err := ParseProviderBlocksWithContext(file, module.Context)
+		if err != nil {
+			logging.Warn("Error parsing provider blocks in %s: %s", file, err)
+		}
+		providers = append(providers, fileProviders...)
+
+		fileModules, err := ParseLocalModulesWithContext(file, module.Context)
+		if err != nil {
+			logging.Warn("Error parsing module calls in %s: %s", file, err)
+		}
+		modules = append(modules, fileModules...)
+	}
+
+	return resources, providers, modules, diagnostics, nil
+}
+
+// childModulePath returns the address of a module called from the given module context
+func childModulePath(mctx *ModuleContext, name string) string {
+	if mctx == nil || mctx.ModulePath == "" {
+		return "module." + name
+	}
+	return mctx.ModulePath + ".module." + name
+}
+
+// callsDir reports whether a directory belongs to this module or one of its callers, which
+// would make following a module call into it recursive
+func (m *ModuleContext) callsDir(dir string) bool {
+	target, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for c := m; c != nil; c = c.parent {
+		if current, err := filepath.Abs(c.Dir); err == nil && current == target {
+			return true
+		}
+	}
+	return false
+}
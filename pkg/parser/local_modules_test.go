@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLocalModule_PassesCallerInputs(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+variable "environment" {
+  default = "prod"
+}
+
+module "network" {
+  source = "./modules/network"
+  tags   = { Environment = var.environment }
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+`,
+		"modules/network/main.tf": `
+variable "tags" {
+  default = {}
+}
+
+variable "vpc_id" {}
+
+resource "aws_subnet" "private" {
+  vpc_id = var.vpc_id
+  tags   = merge(var.tags, { Name = "private" })
+}
+
+module "routes" {
+  source = "./routes"
+}
+`,
+		"modules/network/routes/main.tf": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	mctx, err := NewModuleContext(root, EvalOptions{})
+	if err != nil {
+		t.Fatalf("NewModuleContext failed: %v", err)
+	}
+	modules, err := ParseLocalModulesWithContext(filepath.Join(root, "main.tf"), mctx)
+	if err != nil {
+		t.Fatalf("ParseLocalModulesWithContext failed: %v", err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("Expected only the local module to be followed, got %d modules", len(modules))
+	}
+	if modules[0].ModulePath != "module.network" || modules[0].Tags["Environment"] != "prod" {
+		t.Errorf("Unexpected module call: %s with tags %v", modules[0].ModulePath, modules[0].Tags)
+	}
+
+	resources, _, nested, _, err := LoadLocalModule(modules[0])
+	if err != nil {
+		t.Fatalf("LoadLocalModule failed: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 module resource, got %d", len(resources))
+	}
+
+	subnet := resources[0]
+	if subnet.ModulePath != "module.network" || subnet.ModuleName != "network" || subnet.ModuleSource != "./modules/network" {
+		t.Errorf("Unexpected module fields: %+v", subnet)
+	}
+	if subnet.Tags["Environment"] != "prod" || subnet.Tags["Name"] != "private" {
+		t.Errorf("Expected caller tags to reach the module resource, got %v", subnet.Tags)
+	}
+	if len(subnet.TagErrors) > 0 {
+		t.Errorf("Expected no tag errors, got %v", subnet.TagErrors)
+	}
+
+	inheritance := NewModuleTagInheritance()
+	inheritance.AddModuleTags(modules[0].ModulePath, modules[0].Tags)
+	inheritance.InheritTags(&subnet)
+	if subnet.TagSources["Environment"].Source != "module_call" {
+		t.Errorf("Expected Environment to be attributed to the module call, got %v", subnet.TagSources)
+	}
+	if _, exists := subnet.TagSources["Name"]; exists {
+		t.Errorf("Expected Name not to be attributed to the module call")
+	}
+
+	if len(nested) != 1 || nested[0].ModulePath != "module.network.module.routes" {
+		t.Errorf("Expected nested module.network.module.routes, got %v", nested)
+	}
+}
+
+func TestParseLocalModules_Providers(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "modules", "bucket"), 0755); err != nil {
+		t.Fatalf("Failed to create module directory: %v", err)
+	}
+	content := `
+module "inherits" {
+  source = "./modules/bucket"
+}
+
+module "passes" {
+  source = "./modules/bucket"
+  providers = {
+    aws      = aws.west
+    aws.peer = aws
+  }
+}
+`
+	path := filepath.Join(root, "main.tf")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	mctx, err := NewModuleContext(root, EvalOptions{})
+	if err != nil {
+		t.Fatalf("NewModuleContext failed: %v", err)
+	}
+	modules, err := ParseLocalModulesWithContext(path, mctx)
+	if err != nil {
+		t.Fatalf("ParseLocalModulesWithContext failed: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("Expected 2 modules, got %d", len(modules))
+	}
+	if modules[0].Providers != nil {
+		t.Errorf("Expected no providers for %s, got %v", modules[0].Name, modules[0].Providers)
+	}
+	expected := map[string]string{"aws": "aws.west", "aws.peer": "aws"}
+	if !reflect.DeepEqual(modules[1].Providers, expected) {
+		t.Errorf("Expected providers %v, got %v", expected, modules[1].Providers)
+	}
+}
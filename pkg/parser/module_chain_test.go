@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTerraformPlanWithModules_NestedModuleSources(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "module.platform.aws_s3_bucket.artifacts",
+      "module_address": "module.platform",
+      "type": "aws_s3_bucket",
+      "name": "artifacts",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "artifacts"}}}
+    },
+    {
+      "address": "module.platform.module.vpc[\"east\"].aws_vpc.this",
+      "module_address": "module.platform.module.vpc[\"east\"]",
+      "type": "aws_vpc",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "east"}}}
+    },
+    {
+      "address": "module.other.aws_s3_bucket.logs",
+      "module_address": "module.other",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}
+    }
+  ],
+  "configuration": {
+    "root_module": {
+      "module_calls": {
+        "platform": {
+          "source": "./modules/platform",
+          "module": {
+            "module_calls": {
+              "vpc": {"source": "terraform-aws-modules/vpc/aws", "version": "5.0.0", "module": {}}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	_, modules, err := ParseTerraformPlanWithModules(planPath, "ERROR")
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+
+	tests := []struct {
+		address        string
+		expectedName   string
+		expectedSource string
+		expectedChain  []ModuleCall
+	}{
+		{
+			address:        "module.platform.aws_s3_bucket.artifacts",
+			expectedName:   "platform",
+			expectedSource: "./modules/platform",
+			expectedChain:  []ModuleCall{{Name: "platform", Source: "./modules/platform"}},
+		},
+		{
+			address:        `module.platform.module.vpc["east"].aws_vpc.this`,
+			expectedName:   "vpc",
+			expectedSource: "terraform-aws-modules/vpc/aws@5.0.0",
+			expectedChain: []ModuleCall{
+				{Name: "platform", Source: "./modules/platform"},
+				{Name: "vpc", Source: "terraform-aws-modules/vpc/aws", Version: "5.0.0"},
+			},
+		},
+		{
+			address:        "module.other.aws_s3_bucket.logs",
+			expectedName:   "other",
+			expectedSource: "unknown",
+			expectedChain:  []ModuleCall{{Name: "other", Source: "unknown"}},
+		},
+	}
+
+	byAddress := make(map[string]ModuleResource)
+	for _, module := range modules {
+		byAddress[module.Address] = module
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			module, ok := byAddress[tt.address]
+			if !ok {
+				t.Fatalf("Expected module resource %s", tt.address)
+			}
+			if module.ModuleName != tt.expectedName {
+				t.Errorf("Expected module name %s, got %s", tt.expectedName, module.ModuleName)
+			}
+			if module.ModuleSource != tt.expectedSource {
+				t.Errorf("Expected module source %s, got %s", tt.expectedSource, module.ModuleSource)
+			}
+			if !reflect.DeepEqual(module.ModuleChain, tt.expectedChain) {
+				t.Errorf("Expected module chain %+v, got %+v", tt.expectedChain, module.ModuleChain)
+			}
+		})
+	}
+}
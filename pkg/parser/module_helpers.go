@@ -5,42 +5,138 @@ import (
 	"strings"
 )
 
-// ModuleResource represents a resource created by a module
+// ModuleResource represents a resource created by a module. The embedded Resource carries the
+// module path and source.
 type ModuleResource struct {
 	Resource
-	ModulePath   string // e.g., "module.vpc", "module.vpc.module.subnets"
-	ModuleName   string // e.g., "vpc"
-	ModuleSource string // e.g., "terraform-aws-modules/vpc/aws"
+	// Deprecated: ModulePath repeats Resource.ModulePath, such as "module.vpc.module.subnets".
+	// Use Resource.ModulePath instead.
+	ModulePath string
+	ModuleName string // e.g., "vpc"
+	// Deprecated: ModuleSource repeats Resource.ModuleSource, such as
+	// "terraform-aws-modules/vpc/aws". Use Resource.ModuleSource instead.
+	ModuleSource string
 }
 
-// extractModuleName extracts the top-level module name from module address
+// newModuleResource returns a module resource for a resource whose module path and source
+// are set
+func newModuleResource(resource Resource, moduleName string) ModuleResource {
+	return ModuleResource{
+		Resource:     resource,
+		ModulePath:   resource.ModulePath,
+		ModuleName:   moduleName,
+		ModuleSource: resource.ModuleSource,
+	}
+}
+
+// ModuleCall is a module call in the chain of calls that leads to a module resource
+type ModuleCall struct {
+	Name    string // e.g., "vpc"
+	Source  string // e.g., "terraform-aws-modules/vpc/aws", or "unknown" if not in the configuration
+	Version string // Version constraint of a registry module, if any
+}
+
+// SourceWithVersion returns the source of the module call with its version, such as
+// "terraform-aws-modules/vpc/aws@5.0.0"
+func (c ModuleCall) SourceWithVersion() string {
+	if c.Version != "" {
+		return fmt.Sprintf("%s@%s", c.Source, c.Version)
+	}
+	return c.Source
+}
+
+// extractModuleName extracts the name of the innermost module call from a module address,
+// such as "subnets" from "module.vpc.module.subnets"
 func extractModuleName(moduleAddress string) string {
-	// moduleAddress format: "module.vpc" or "module.vpc.module.subnets"
-	parts := strings.Split(moduleAddress, ".")
-	if len(parts) >= 2 && parts[0] == "module" {
-		return parts[1]
-	}
-	return moduleAddress
-}
-
-// getModuleSource retrieves the source of a module from configuration
-func getModuleSource(moduleName string, moduleCalls map[string]struct {
-	Source  string `json:"source"`
-	Version string `json:"version,omitempty"`
-}) string {
-	if moduleCall, exists := moduleCalls[moduleName]; exists {
-		if moduleCall.Version != "" {
-			return fmt.Sprintf("%s@%s", moduleCall.Source, moduleCall.Version)
+	names := moduleCallNames(moduleAddress)
+	if len(names) == 0 {
+		return moduleAddress
+	}
+	return names[len(names)-1]
+}
+
+// moduleCallNames returns the names of the module calls in a module address, outermost first,
+// without their instance keys
+func moduleCallNames(moduleAddress string) []string {
+	var names []string
+	parts := strings.Split(ConfigAddress(moduleAddress), ".")
+	for i := 0; i+1 < len(parts); i += 2 {
+		if parts[i] != "module" {
+			break
+		}
+		names = append(names, parts[i+1])
+	}
+	return names
+}
+
+// resolveModuleChain walks the module calls of a plan's configuration along a module address
+// and returns the chain of module calls that leads to it. Calls missing from the
+// configuration have the source "unknown".
+func resolveModuleChain(moduleAddress string, root planModule) []ModuleCall {
+	var chain []ModuleCall
+	calls := root.ModuleCalls
+	for _, name := range moduleCallNames(moduleAddress) {
+		call, exists := calls[name]
+		if !exists {
+			chain = append(chain, ModuleCall{Name: name, Source: "unknown"})
+			calls = nil
+			continue
 		}
-		return moduleCall.Source
+		chain = append(chain, ModuleCall{Name: name, Source: call.Source, Version: call.Version})
+		calls = call.Module.ModuleCalls
 	}
-	return "unknown"
+	if len(chain) == 0 {
+		chain = append(chain, ModuleCall{Name: moduleAddress, Source: "unknown"})
+	}
+	return chain
 }
 
-// isExternalModule checks if a module is external (not local)
-func isExternalModule(source string) bool {
+// IsExternalModule checks if a module is external (not local)
+func IsExternalModule(source string) bool {
 	// External modules typically start with registry paths or git URLs
 	return !strings.HasPrefix(source, "./") &&
 		!strings.HasPrefix(source, "../") &&
 		!strings.HasPrefix(source, "/")
 }
+
+// instanceKey returns the instance key at the end of a resource instance address, such as
+// [0] in "module.vpc.aws_subnet.private[0]", or an empty string if there is none
+func instanceKey(address, moduleAddress, resourceType, name string) string {
+	prefix := resourceType + "." + name
+	if moduleAddress != "" {
+		prefix = moduleAddress + "." + prefix
+	}
+	if !strings.HasPrefix(address, prefix) || !strings.HasPrefix(address[len(prefix):], "[") {
+		return ""
+	}
+	return address[len(prefix):]
+}
+
+// ConfigAddress returns the address of the configuration a resource instance belongs to by
+// removing the instance keys of the resource and of its module calls, so that
+// `module.app["a"].aws_s3_bucket.logs[0]` becomes "module.app.aws_s3_bucket.logs"
+func ConfigAddress(address string) string {
+	var b strings.Builder
+	depth := 0
+	inString := false
+	for i := 0; i < len(address); i++ {
+		c := address[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"' && depth > 0:
+			inString = true
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
@@ -21,6 +20,60 @@ type Resource struct {
 	Path string
 	// New field to track tag sources
 	TagSources map[string]TagSource
+	// TagErrors records tag expressions that could not be fully evaluated
+	TagErrors []string
+	// UnknownTags holds tag keys whose values are only known after apply
+	UnknownTags map[string]bool
+	// TagsUnknown is set when the tag keys themselves are only known after apply
+	TagsUnknown bool
+	// PropagateAtLaunch holds the known propagate_at_launch setting of each tag for
+	// resources that declare tags as blocks, such as aws_autoscaling_group
+	PropagateAtLaunch map[string]bool
+	// LocationTags holds the tags of secondary tag locations, such as volume_tags
+	LocationTags []LocationTags
+	// Provider is the provider configuration set by the provider meta-argument, such as
+	// "aws.west". It is empty when the resource uses the default configuration.
+	Provider string
+	// IgnoreTags holds the ignore_tags of the provider configuration the resource uses
+	IgnoreTags IgnoreTags
+	// Suppressions holds the terratags:ignore comments directly above the block
+	Suppressions []Suppression
+	// Range is the range of the resource block
+	Range SourceRange
+	// TagsRange is the range of the tags attribute, or of the blocks declaring the tags
+	TagsRange SourceRange
+	// TagRanges holds the ranges of the tags written with literal keys
+	TagRanges map[string]SourceRange
+	// PreviousTags holds the tags of an existing resource before the planned change. It is
+	// nil unless the resource is read from a plan that updates it.
+	PreviousTags map[string]string
+	// Address is the Terraform address of the resource, such as
+	// "module.network.aws_subnet.private[0]". In directory mode it is built from the module
+	// call chain and has no instance keys.
+	Address string
+	// ModulePath is the module call chain of a resource created by a module, such as
+	// "module.vpc.module.subnets". It is empty for root module resources.
+	ModulePath string
+	// ModuleSource is the source of the module that creates the resource, such as
+	// "terraform-aws-modules/vpc/aws", with its version in plan mode
+	ModuleSource string
+	// ModuleChain holds the module calls from the root module down to the module that creates
+	// the resource, outermost first. It is only set in plan mode.
+	ModuleChain []ModuleCall
+	// InstanceKey is the count index or for_each key of a resource instance in a plan or
+	// state, written as in its address, such as [0] or ["logs"]. It is empty for resources
+	// without count or for_each and in directory mode.
+	InstanceKey string
+	// Plan is the name of the plan the resource is in when a batch of plans is validated
+	Plan string
+}
+
+// ProviderKey returns the address of the provider configuration the resource uses
+func (r Resource) ProviderKey() string {
+	if r.Provider != "" {
+		return r.Provider
+	}
+	return ImpliedProvider(r.Type)
 }
 
 // TagSource represents the source of a tag
@@ -31,22 +84,38 @@ type TagSource struct {
 
 // ParseFile parses a Terraform file and extracts resources with their tags
 func ParseFile(path string, logLevel string) ([]Resource, error) {
+	return ParseFileWithContext(path, nil)
+}
+
+// ParseFileWithContext parses a Terraform file and extracts resources with their tags,
+// resolving variables and locals from the module context when one is given
+func ParseFileWithContext(path string, mctx *ModuleContext) ([]Resource, error) {
+	resources, _, err := ParseFileWithDiagnostics(path, mctx)
+	return resources, err
+}
+
+// ParseFileWithDiagnostics parses a Terraform file like ParseFileWithContext and also returns
+// diagnostics for the blocks and tag expressions that could not be analyzed. Files that cannot
+// be parsed at all return a *ParseError.
+func ParseFileWithDiagnostics(path string, mctx *ModuleContext) ([]Resource, []Diagnostic, error) {
 	content, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	parser := hclparse.NewParser()
-	file, diags := parser.ParseHCL(content, path)
+	file, diags := parseTerraformFile(hclparse.NewParser(), content, path)
 	if diags.HasErrors() {
-		return nil, fmt.Errorf("failed to parse HCL: %s", diags.Error())
+		return nil, nil, &ParseError{Path: path, Diagnostics: newDiagnostics(diags, path, DiagnosticError)}
 	}
 
 	var resources []Resource
+	var diagnostics []Diagnostic
+	ctx := mctx.evalContext()
+	comments := collectComments(content, path)
 
-	// Create a more permissive schema that allows all block types
-	// but we'll only process resource and module blocks
-	content2, diags := file.Body.Content(&hcl.BodySchema{
+	// Only resource and module blocks are processed. Blocks of these types that are
+	// malformed, such as a resource without a name, are skipped and reported.
+	content2, _, diags := file.Body.PartialContent(&hcl.BodySchema{
 		Blocks: []hcl.BlockHeaderSchema{
 			{
 				Type:       "resource",
@@ -56,35 +125,12 @@ func ParseFile(path string, logLevel string) ([]Resource, error) {
 				Type:       "module",
 				LabelNames: []string{"name"},
 			},
-			// Allow other block types but don't process them
-			{
-				Type:       "provider",
-				LabelNames: []string{"name"},
-			},
-			{
-				Type:       "data",
-				LabelNames: []string{"type", "name"},
-			},
-			{
-				Type: "locals",
-			},
-			{
-				Type:       "variable",
-				LabelNames: []string{"name"},
-			},
-			{
-				Type:       "output",
-				LabelNames: []string{"name"},
-			},
-			{
-				Type: "terraform",
-			},
 		},
 	})
 
 	if diags.HasErrors() {
-		// If we still have errors, try a more permissive approach
-		logging.Warn("Warning: Some blocks in %s couldn't be parsed, but we'll continue with what we can parse", path)
+		logging.Warn("Some blocks in %s couldn't be parsed, continuing with the blocks that could: %s", path, diags.Error())
+		diagnostics = append(diagnostics, newDiagnostics(diags, path, DiagnosticWarning)...)
 	}
 
 	for _, block := range content2.Blocks {
@@ -95,26 +141,69 @@ func ParseFile(path string, logLevel string) ([]Resource, error) {
 
 			// Check if this resource type supports tagging
 			if isTaggableResource(resourceType) {
-				tags := extractTagsFromContent(content, resourceType, resourceName)
+				var result tagExpressionResult
+				var propagate map[string]bool
+				if blockName, ok := blockTaggedResources[resourceType]; ok {
+					result, propagate = extractTagBlocks(block.Body, blockName, ctx, content)
+				} else {
+					result, _ = extractTagsFromBody(block.Body, tagAttributeName(resourceType),
+						tagFormatForResource(resourceType), ctx, content)
+				}
+				address := fmt.Sprintf("%s.%s", resourceType, resourceName)
+				logTagErrors(address, result.Errors)
+				// Excluded resources are not validated, so their tags need not be analyzed
+				if !AwsccExcludedResources[resourceType] {
+					diagnostics = append(diagnostics, tagDiagnostics(address, result.Errors, tagsRangeOrBlock(newSourceRange(result.Range), block))...)
+				}
+				locationTags := extractLocationTags(resourceType, block.Body, ctx, content)
+				for _, location := range locationTags {
+					locationAddress := fmt.Sprintf("%s %s", address, location.Name)
+					logTagErrors(locationAddress, location.TagErrors)
+					diagnostics = append(diagnostics, tagDiagnostics(locationAddress, location.TagErrors, tagsRangeOrBlock(location.Range, block))...)
+				}
 				resources = append(resources, Resource{
-					Type:       resourceType,
-					Name:       resourceName,
-					Tags:       tags,
-					Path:       path,
-					TagSources: make(map[string]TagSource),
+					Type:              resourceType,
+					Name:              resourceName,
+					Address:           mctx.address(address),
+					ModulePath:        mctx.modulePath(),
+					Tags:              result.Tags,
+					Path:              path,
+					TagSources:        make(map[string]TagSource),
+					TagErrors:         result.Errors,
+					UnknownTags:       result.Unknown,
+					TagsUnknown:       result.AllUnknown,
+					PropagateAtLaunch: propagate,
+					LocationTags:      locationTags,
+					Provider:          resourceProvider(block.Body),
+					Suppressions:      comments.suppressionsAbove(block, path),
+					Range:             newSourceRange(blockRange(block)),
+					TagsRange:         newSourceRange(result.Range),
+					TagRanges:         sourceRanges(result.ItemRanges),
 				})
 			}
 		case "module":
 			moduleName := block.Labels[0]
-			// Extract module resources and their tags
-			moduleTags := extractModuleTagsFromContent(content, moduleName)
-			if len(moduleTags) > 0 {
+			// Extract the tags passed to the module call
+			result, hasTags := extractTagsFromBody(block.Body, "tags", tagFormatMap, ctx, content)
+			address := fmt.Sprintf("module.%s", moduleName)
+			logTagErrors(address, result.Errors)
+			diagnostics = append(diagnostics, tagDiagnostics(address, result.Errors, tagsRangeOrBlock(newSourceRange(result.Range), block))...)
+			if hasTags && (len(result.Tags) > 0 || len(result.Errors) > 0 || result.AllUnknown) {
 				resources = append(resources, Resource{
-					Type:       "module",
-					Name:       moduleName,
-					Tags:       moduleTags,
-					Path:       path,
-					TagSources: make(map[string]TagSource),
+					Type:         "module",
+					Name:         moduleName,
+					Address:      mctx.address(address),
+					ModulePath:   mctx.modulePath(),
+					Tags:         result.Tags,
+					Path:         path,
+					TagSources:   make(map[string]TagSource),
+					TagErrors:    result.Errors,
+					UnknownTags:  result.Unknown,
+					TagsUnknown:  result.AllUnknown,
+					Suppressions: comments.suppressionsAbove(block, path),
+					Range:        newSourceRange(blockRange(block)),
+					TagsRange:    newSourceRange(result.Range),
+					TagRanges:    sourceRanges(result.ItemRanges),
 				})
 			}
 		// Ignore other block types (provider, data, locals, etc.)
@@ -123,11 +212,56 @@ func ParseFile(path string, logLevel string) ([]Resource, error) {
 		}
 	}
 
-	return resources, nil
+	return resources, diagnostics, nil
+}
+
+// tagsRangeOrBlock returns the range of a block's tags, or of its header when the tags have none
+func tagsRangeOrBlock(tagsRange SourceRange, block *hcl.Block) SourceRange {
+	if tagsRange.IsZero() {
+		return newSourceRange(block.DefRange)
+	}
+	return tagsRange
+}
+
+// FindTerraformFiles returns the Terraform configuration files (*.tf and *.tf.json) in a directory
+func FindTerraformFiles(dir string) ([]string, error) {
+	var files []string
+	for _, pattern := range []string{"*.tf", "*.tf.json"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// IsTerraformFile reports whether a file name is a Terraform configuration file
+func IsTerraformFile(name string) bool {
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")
+}
+
+// parseTerraformFile parses a Terraform file in native syntax, or in JSON syntax for *.tf.json files
+func parseTerraformFile(p *hclparse.Parser, content []byte, path string) (*hcl.File, hcl.Diagnostics) {
+	if strings.HasSuffix(path, ".json") {
+		return p.ParseJSON(content, path)
+	}
+	return p.ParseHCL(content, path)
+}
+
+// logTagErrors reports tag expressions that could not be evaluated for a block
+func logTagErrors(address string, errors []string) {
+	for _, msg := range errors {
+		logging.Warn("Unable to evaluate tags for %s: %s", address, msg)
+	}
 }
 
 // isTaggableResource checks if a resource type supports tagging
 func isTaggableResource(resourceType string) bool {
+	// Resources with block-style tags are not in the generated lists
+	if UsesTagBlocks(resourceType) {
+		return true
+	}
 	// First check if it's in the excluded list
 	if AwsccExcludedResources[resourceType] {
 		logging.Debug("%s is in the excluded awscc resources list", resourceType)
@@ -157,240 +291,24 @@ func isTaggableResource(resourceType string) bool {
 	return awsTaggableResources[resourceType]
 }
 
-// extractTagsFromContent extracts tags directly from the file content
-func extractTagsFromContent(content []byte, resourceType, resourceName string) map[string]string {
-	tags := make(map[string]string)
-
-	// Convert content to string
-	fileContent := string(content)
-
-	// Find the resource block with proper handling of nested blocks
-	// This pattern matches the entire resource block including nested blocks
-	resourcePattern := fmt.Sprintf(`resource\s+"%s"\s+"%s"\s*{[\s\S]*?(?:^}|\n}|\r\n})`,
-		regexp.QuoteMeta(resourceType), regexp.QuoteMeta(resourceName))
-	resourceRegex := regexp.MustCompile(`(?sm)` + resourcePattern)
-	resourceMatch := resourceRegex.FindString(fileContent)
-
-	if resourceMatch != "" {
-		// Check if this is an AWSCC resource
-		isAWSCC := strings.HasPrefix(resourceType, "awscc_")
-		// Check if this is an Azure resource
-		isAzurerm := strings.HasPrefix(resourceType, "azurerm_")
-		// Check if this is an azapi resource
-		isAzapi := strings.HasPrefix(resourceType, "azapi_")
-		// Check if this is a Google resource
-		isGoogle := strings.HasPrefix(resourceType, "google_")
-		// Check if this is an AliCloud resource
-		isAliCloud := strings.HasPrefix(resourceType, "alicloud_")
-		// Check if this is a Datadog resource
-		isDatadog := strings.HasPrefix(resourceType, "datadog_")
-
-		if isAWSCC {
-			// For AWSCC resources, tags are in the format: tags = [{key = "Key", value = "Value"}]
-			tagsPattern := `tags\s*=\s*\[([\s\S]*?)\]`
-			tagsRegex := regexp.MustCompile(`(?s)` + tagsPattern)
-			tagsMatch := tagsRegex.FindStringSubmatch(resourceMatch)
-
-			if len(tagsMatch) > 1 {
-				logging.Debug("Found AWSCC tags attribute in %s %s", resourceType, resourceName)
-
-				// Extract key-value pairs from the list of maps format
-				tagContent := tagsMatch[1]
-				// Match each {key = "...", value = "..."} block
-				tagBlockPattern := `{[\s\S]*?key\s*=\s*["']?([^"'\s,}]*)["']?[\s\S]*?value\s*=\s*["']?([^"'\s,}]*)["']?[\s\S]*?}`
-				tagBlockRegex := regexp.MustCompile(`(?s)` + tagBlockPattern)
-				tagBlockMatches := tagBlockRegex.FindAllStringSubmatch(tagContent, -1)
-
-				for _, match := range tagBlockMatches {
-					if len(match) > 2 {
-						key := match[1]
-						value := match[2]
-						logging.Debug("Found AWSCC tag key: %s with value: %s", key, value)
-						tags[key] = value
-					}
-				}
-			} else {
-				logging.Debug("No tags attribute found in AWSCC resource %s %s", resourceType, resourceName)
-			}
-		} else if isAzurerm || isAzapi {
-			// For Azure resources, tags are in the format: tags = {Key = "Value"}
-			tagsPattern := `tags\s*=\s*{([\s\S]*?)}`
-			tagsRegex := regexp.MustCompile(`(?s)` + tagsPattern)
-			tagsMatch := tagsRegex.FindStringSubmatch(resourceMatch)
-
-			if len(tagsMatch) > 1 {
-				logging.Debug("Found tags attribute in %s %s", resourceType, resourceName)
-
-				// Extract key-value pairs
-				tagContent := tagsMatch[1]
-				keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-				keyValueRegex := regexp.MustCompile(keyValuePattern)
-				keyValueMatches := keyValueRegex.FindAllStringSubmatch(tagContent, -1)
-
-				for _, match := range keyValueMatches {
-					if len(match) > 2 {
-						key := match[1]
-						value := match[2]
-						logging.Debug("Found tag key: %s", key)
-						tags[key] = value
-					}
-				}
-			} else {
-				logging.Debug("No tags attribute found in %s %s", resourceType, resourceName)
-			}
-		} else if isGoogle {
-			// For Google resources, labels are in the format: labels = {Key = "Value"}
-			labelsPattern := `labels\s*=\s*{([\s\S]*?)}`
-			labelsRegex := regexp.MustCompile(`(?s)` + labelsPattern)
-			labelsMatch := labelsRegex.FindStringSubmatch(resourceMatch)
-
-			if len(labelsMatch) > 1 {
-				logging.Debug("Found labels attribute in %s %s", resourceType, resourceName)
-
-				// Extract key-value pairs
-				labelContent := labelsMatch[1]
-				keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-				keyValueRegex := regexp.MustCompile(keyValuePattern)
-				keyValueMatches := keyValueRegex.FindAllStringSubmatch(labelContent, -1)
-
-				for _, match := range keyValueMatches {
-					if len(match) > 2 {
-						key := match[1]
-						value := match[2]
-						logging.Debug("Found label key: %s", key)
-						tags[key] = value
-					}
-				}
-			} else {
-				logging.Debug("No labels attribute found in %s %s", resourceType, resourceName)
-			}
-		} else if isAliCloud {
-			// For AliCloud resources, tags are in the format: tags = {Key = "Value"} (same as AWS)
-			tagsPattern := `tags\s*=\s*{([\s\S]*?)}`
-			tagsRegex := regexp.MustCompile(`(?s)` + tagsPattern)
-			tagsMatch := tagsRegex.FindStringSubmatch(resourceMatch)
-
-			if len(tagsMatch) > 1 {
-				logging.Debug("Found tags attribute in %s %s", resourceType, resourceName)
-
-				// Extract key-value pairs
-				tagContent := tagsMatch[1]
-				keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-				keyValueRegex := regexp.MustCompile(keyValuePattern)
-				keyValueMatches := keyValueRegex.FindAllStringSubmatch(tagContent, -1)
-
-				for _, match := range keyValueMatches {
-					if len(match) > 2 {
-						key := match[1]
-						value := match[2]
-						logging.Debug("Found tag key: %s", key)
-						tags[key] = value
-					}
-				}
-			} else {
-				logging.Debug("No tags attribute found in %s %s", resourceType, resourceName)
-			}
-		} else if isDatadog {
-			// For Datadog resources, tags are in the format: tags = ["key:value", "key2:value2"]
-			tagsPattern := `tags\s*=\s*\[([\s\S]*?)\]`
-			tagsRegex := regexp.MustCompile(`(?s)` + tagsPattern)
-			tagsMatch := tagsRegex.FindStringSubmatch(resourceMatch)
-
-			if len(tagsMatch) > 1 {
-				logging.Debug("Found tags attribute in %s %s", resourceType, resourceName)
-
-				// Extract key:value pairs from the list format
-				tagContent := tagsMatch[1]
-				// Match quoted strings containing key:value pairs
-				tagPattern := `["']([^"']*?):([^"']*?)["']`
-				tagRegex := regexp.MustCompile(tagPattern)
-				tagMatches := tagRegex.FindAllStringSubmatch(tagContent, -1)
-
-				for _, match := range tagMatches {
-					if len(match) > 2 {
-						key := match[1]
-						value := match[2]
-						logging.Debug("Found Datadog tag key: %s with value: %s", key, value)
-						tags[key] = value
-					}
-				}
-			} else {
-				logging.Debug("No tags attribute found in %s %s", resourceType, resourceName)
-			}
-		} else {
-			// For AWS resources, tags are in the format: tags = {Key = "Value"}
-			tagsPattern := `tags\s*=\s*{([\s\S]*?)}`
-			tagsRegex := regexp.MustCompile(`(?s)` + tagsPattern)
-			tagsMatch := tagsRegex.FindStringSubmatch(resourceMatch)
-
-			if len(tagsMatch) > 1 {
-				logging.Debug("Found tags attribute in %s %s", resourceType, resourceName)
-
-				// Extract key-value pairs
-				tagContent := tagsMatch[1]
-				keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-				keyValueRegex := regexp.MustCompile(keyValuePattern)
-				keyValueMatches := keyValueRegex.FindAllStringSubmatch(tagContent, -1)
-
-				for _, match := range keyValueMatches {
-					if len(match) > 2 {
-						key := match[1]
-						value := match[2]
-						logging.Debug("Found tag key: %s", key)
-						tags[key] = value
-					}
-				}
-			} else {
-				logging.Debug("No tags attribute found in %s %s", resourceType, resourceName)
-			}
-		}
-	}
-
-	return tags
-}
-
-// extractModuleTagsFromContent extracts module tags directly from the file content
-func extractModuleTagsFromContent(content []byte, moduleName string) map[string]string {
-	tags := make(map[string]string)
-
-	// Convert content to string
-	fileContent := string(content)
-
-	// Find the module block with proper handling of nested blocks
-	modulePattern := fmt.Sprintf(`module\s+"%s"\s*{[\s\S]*?(?:^}|\n}|\r\n})`, regexp.QuoteMeta(moduleName))
-	moduleRegex := regexp.MustCompile(`(?sm)` + modulePattern)
-	moduleMatch := moduleRegex.FindString(fileContent)
-
-	if moduleMatch != "" {
-		// Find the tags block within the module
-		// Improved pattern to handle tags that might appear after nested blocks
-		tagsPattern := `tags\s*=\s*{([\s\S]*?)}`
-		tagsRegex := regexp.MustCompile(`(?s)` + tagsPattern)
-		tagsMatch := tagsRegex.FindStringSubmatch(moduleMatch)
-
-		if len(tagsMatch) > 1 {
-			logging.Debug("Found tags attribute in module %s", moduleName)
-
-			// Extract key-value pairs
-			tagContent := tagsMatch[1]
-			keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-			keyValueRegex := regexp.MustCompile(keyValuePattern)
-			keyValueMatches := keyValueRegex.FindAllStringSubmatch(tagContent, -1)
-
-			for _, match := range keyValueMatches {
-				if len(match) > 2 {
-					key := match[1]
-					value := match[2]
-					logging.Debug("Found module tag key: %s", key)
-					tags[key] = value
-				}
-			}
-		} else {
-			logging.Debug("No tags attribute found in module %s", moduleName)
-		}
+// ProviderFamily returns the provider family of a resource type, following the taggable
+// resource lists isTaggableResource uses: aws (including awscc), azure (azurerm and azapi),
+// google (including google-beta), alicloud or datadog. Other resource types have no family.
+func ProviderFamily(resourceType string) string {
+	switch {
+	case strings.HasPrefix(resourceType, "aws_"), strings.HasPrefix(resourceType, "awscc_"):
+		return "aws"
+	case strings.HasPrefix(resourceType, "azurerm_"), strings.HasPrefix(resourceType, "azapi_"):
+		return "azure"
+	case strings.HasPrefix(resourceType, "google_"):
+		return "google"
+	case strings.HasPrefix(resourceType, "alicloud_"):
+		return "alicloud"
+	case strings.HasPrefix(resourceType, "datadog_"):
+		return "datadog"
+	default:
+		return ""
 	}
-
-	return tags
 }
 
 // ParseTerraformPlan parses a Terraform plan JSON file and extracts resources with their tags
@@ -415,17 +333,14 @@ func ParseTerraformPlanWithModules(planPath string, logLevel string) ([]Resource
 			Type          string `json:"type"`
 			Name          string `json:"name"`
 			Change        struct {
-				Actions []string       `json:"actions"`
-				After   map[string]any `json:"after"`
+				Actions      []string       `json:"actions"`
+				Before       map[string]any `json:"before"`
+				After        map[string]any `json:"after"`
+				AfterUnknown map[string]any `json:"after_unknown"`
 			} `json:"change"`
 		} `json:"resource_changes"`
 		Configuration struct {
-			RootModule struct {
-				ModuleCalls map[string]struct {
-					Source  string `json:"source"`
-					Version string `json:"version,omitempty"`
-				} `json:"module_calls,omitempty"`
-			} `json:"root_module"`
+			RootModule planModule `json:"root_module"`
 		} `json:"configuration"`
 	}
 
@@ -433,6 +348,16 @@ func ParseTerraformPlanWithModules(planPath string, logLevel string) ([]Resource
 		return nil, nil, fmt.Errorf("failed to parse plan JSON: %w", err)
 	}
 
+	// Provider configurations that ignore tags, and the configuration each resource uses
+	providers, resourceProviders, err := parsePlanProviders(planData, planPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	ignoreTagsByProvider := make(map[string]IgnoreTags)
+	for _, provider := range providers {
+		ignoreTagsByProvider[provider.Key()] = provider.IgnoreTags
+	}
+
 	var directResources []Resource
 	var moduleResources []ModuleResource
 
@@ -462,28 +387,46 @@ func ParseTerraformPlanWithModules(planPath string, logLevel string) ([]Resource
 			continue
 		}
 
-		tags := extractTagsFromPlanResource(rc.Change.After)
+		tags, propagate := planResourceTags(rc.Type, rc.Change.After)
 
 		baseResource := Resource{
-			Type:       rc.Type,
-			Name:       rc.Name,
-			Tags:       tags,
-			Path:       planPath,
-			TagSources: make(map[string]TagSource),
+			Type:              rc.Type,
+			Name:              rc.Name,
+			Tags:              tags,
+			Path:              planPath,
+			TagSources:        make(map[string]TagSource),
+			PropagateAtLaunch: propagate,
+			LocationTags:      extractLocationTagsFromPlanResource(rc.Type, rc.Change.After),
+			Address:           rc.Address,
+		}
+		baseResource.InstanceKey = instanceKey(rc.Address, rc.ModuleAddress, rc.Type, rc.Name)
+		baseResource.UnknownTags, baseResource.TagsUnknown = planUnknownTags(rc.Type, rc.Change.After, rc.Change.AfterUnknown, tags)
+		if rc.ModuleAddress == "" {
+			baseResource.Provider = planResourceProvider(rc.Type, rc.Name, resourceProviders)
+		}
+
+		// Updates of existing resources also carry the tags before the change
+		if rc.Change.Before != nil {
+			baseResource.PreviousTags, _ = planResourceTags(rc.Type, rc.Change.Before)
+		}
+
+		// Tags covered by the provider's ignore_tags are not managed by Terraform
+		baseResource.IgnoreTags = ignoreTagsByProvider[baseResource.ProviderKey()]
+		removeIgnoredTags(baseResource.Tags, baseResource.IgnoreTags)
+		removeIgnoredTags(baseResource.PreviousTags, baseResource.IgnoreTags)
+		for _, location := range baseResource.LocationTags {
+			removeIgnoredTags(location.Tags, baseResource.IgnoreTags)
 		}
 
 		if rc.ModuleAddress != "" {
 			// This is a module-created resource
 			modulePath := rc.ModuleAddress
 			moduleName := extractModuleName(modulePath)
-			moduleSource := getModuleSource(moduleName, plan.Configuration.RootModule.ModuleCalls)
+			baseResource.ModulePath = modulePath
+			baseResource.ModuleChain = resolveModuleChain(modulePath, plan.Configuration.RootModule)
+			baseResource.ModuleSource = baseResource.ModuleChain[len(baseResource.ModuleChain)-1].SourceWithVersion()
 
-			moduleResource := ModuleResource{
-				Resource:     baseResource,
-				ModulePath:   modulePath,
-				ModuleName:   moduleName,
-				ModuleSource: moduleSource,
-			}
+			moduleResource := newModuleResource(baseResource, moduleName)
 
 			// Initialize TagSources if not already done
 			if moduleResource.TagSources == nil {
@@ -507,6 +450,77 @@ func ParseTerraformPlanWithModules(planPath string, logLevel string) ([]Resource
 	return directResources, moduleResources, nil
 }
 
+// planResourceTags extracts the tags of a resource from its values before or after a planned
+// change, along with the propagate_at_launch settings of resources that declare tags as blocks
+func planResourceTags(resourceType string, values map[string]any) (map[string]string, map[string]bool) {
+	if blockName, ok := blockTaggedResources[resourceType]; ok {
+		return extractTagBlocksFromPlanResource(values, blockName)
+	}
+	return extractTagsFromPlanResource(values), nil
+}
+
+// planTagAttributes lists the attributes of a planned resource that hold its tags
+var planTagAttributes = []string{"tags_all", "effective_labels", "tags", "labels"}
+
+// planUnknownTags reads the after_unknown values of a planned change, which mark the values only
+// known after apply, and records the unknown tags in tags with UnknownTagValue. It returns the
+// unknown tag keys, and whether a whole tag map is unknown, in which case its keys are not known.
+func planUnknownTags(resourceType string, after, afterUnknown map[string]any, tags map[string]string) (map[string]bool, bool) {
+	unknown := make(map[string]bool)
+	allUnknown := false
+
+	attributes := planTagAttributes
+	if blockName, ok := blockTaggedResources[resourceType]; ok {
+		attributes = []string{blockName}
+	}
+
+	markUnknown := func(key string) {
+		if _, known := tags[key]; known && !unknown[key] {
+			return
+		}
+		tags[key] = UnknownTagValue
+		unknown[key] = true
+	}
+
+	for _, attribute := range attributes {
+		switch value := afterUnknown[attribute].(type) {
+		case bool:
+			allUnknown = allUnknown || value
+		case map[string]any:
+			// A map of tags with unknown values
+			for key, isUnknown := range value {
+				if isUnknown == true {
+					markUnknown(key)
+				}
+			}
+		case []any:
+			// A list of key/value objects, such as AWSCC tags or tag blocks, whose keys are
+			// in the known values at the same index
+			known, _ := after[attribute].([]any)
+			for i, item := range value {
+				itemUnknown, _ := item.(map[string]any)
+				if item == true || itemUnknown["key"] == true {
+					allUnknown = true
+					continue
+				}
+				if itemUnknown["value"] != true || i >= len(known) {
+					continue
+				}
+				if knownItem, ok := known[i].(map[string]any); ok {
+					if key, ok := knownItem["key"].(string); ok {
+						markUnknown(key)
+					}
+				}
+			}
+		}
+	}
+
+	if len(unknown) == 0 {
+		unknown = nil
+	}
+	return unknown, allUnknown
+}
+
 // extractTagsFromPlanResource extracts tags from a resource in the plan
 func extractTagsFromPlanResource(resource map[string]any) map[string]string {
 	tags := make(map[string]string)
@@ -563,7 +577,7 @@ func extractTagsFromPlanResource(resource map[string]any) map[string]string {
 		} else if tagsMap, ok := tagsInterface.(map[string]any); ok {
 			// This is the standard AWS/Azure provider tags format (map of key/value)
 			// Only use this if tags_all is not available (to avoid overriding merged tags)
-			if _, hasTagsAll := resource["tags_all"]; !hasTagsAll {
+			if _, hasTagsAll := resource["tags_all"].(map[string]any); !hasTagsAll {
 				logging.Debug("Found tags in plan resource")
 				for k, v := range tagsMap {
 					if strValue, ok := v.(string); ok {
@@ -579,7 +593,7 @@ func extractTagsFromPlanResource(resource map[string]any) map[string]string {
 	if labelsInterface, ok := resource["labels"]; ok {
 		if labelsMap, ok := labelsInterface.(map[string]any); ok {
 			// Only use this if effective_labels is not available (to avoid overriding merged labels)
-			if _, hasEffectiveLabels := resource["effective_labels"]; !hasEffectiveLabels {
+			if _, hasEffectiveLabels := resource["effective_labels"].(map[string]any); !hasEffectiveLabels {
 				logging.Debug("Found labels in plan resource")
 				for k, v := range labelsMap {
 					if strValue, ok := v.(string); ok {
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/terratags/terratags/pkg/logging"
+)
+
+// resourcePositions holds the source ranges of a resource block
+type resourcePositions struct {
+	Range     SourceRange
+	TagsRange SourceRange
+	TagRanges map[string]SourceRange
+}
+
+// LocatePlanResources recovers the source positions of plan resources from the Terraform
+// configuration in configDir. Plans carry no source positions, so resource blocks are looked up
+// by type and name in the root module and in the local modules named by the module calls of
+// the plan's configuration. Resources that cannot be found keep empty ranges.
+func LocatePlanResources(planPath, configDir string, direct []Resource, modules []ModuleResource) error {
+	planData, err := os.ReadFile(filepath.Clean(planPath))
+	if err != nil {
+		return fmt.Errorf("failed to read plan file: %w", err)
+	}
+	var plan planConfiguration
+	if err := json.Unmarshal(planData, &plan); err != nil {
+		return fmt.Errorf("failed to parse plan JSON: %w", err)
+	}
+
+	indexes := make(map[string]map[string]resourcePositions)
+	lookup := func(dir string, resource *Resource) {
+		index, exists := indexes[dir]
+		if !exists {
+			index = indexResourcePositions(dir)
+			indexes[dir] = index
+		}
+		if positions, exists := index[resource.Type+"."+resource.Name]; exists {
+			resource.Range = positions.Range
+			resource.TagsRange = positions.TagsRange
+			resource.TagRanges = positions.TagRanges
+		}
+	}
+
+	for i := range direct {
+		lookup(configDir, &direct[i])
+	}
+	for i := range modules {
+		dir, ok := planModuleDir(configDir, plan.Configuration.RootModule, modules[i].Resource.ModulePath)
+		if !ok {
+			logging.Debug("Not locating %s.%s: %s has no local source", modules[i].Type, modules[i].Name, modules[i].Resource.ModulePath)
+			continue
+		}
+		lookup(dir, &modules[i].Resource)
+	}
+	return nil
+}
+
+// planModuleDir resolves the directory of a module from the local sources of the module calls
+// leading to it, such as module.network[0].module.subnets
+func planModuleDir(configDir string, root planModule, modulePath string) (string, bool) {
+	dir := configDir
+	module := root
+	parts := strings.Split(modulePath, ".")
+	for i := 0; i+1 < len(parts); i += 2 {
+		if parts[i] != "module" {
+			return "", false
+		}
+		name, _, _ := strings.Cut(parts[i+1], "[")
+		call, exists := module.ModuleCalls[name]
+		if !exists || IsExternalModule(call.Source) {
+			return "", false
+		}
+		dir = filepath.Join(dir, call.Source)
+		module = call.Module
+	}
+	return dir, true
+}
+
+// indexResourcePositions returns the source ranges of the resource blocks in the Terraform files
+// of a directory by resource address
+func indexResourcePositions(dir string) map[string]resourcePositions {
+	index := make(map[string]resourcePositions)
+	files, err := FindTerraformFiles(dir)
+	if err != nil {
+		logging.Debug("Unable to list Terraform files in %s: %s", dir, err)
+		return index
+	}
+
+	parser := hclparse.NewParser()
+	for _, path := range files {
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			continue
+		}
+		file, diags := parseTerraformFile(parser, content, path)
+		if diags.HasErrors() {
+			continue
+		}
+		bodyContent, _, _ := file.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "resource", LabelNames: []string{"type", "name"}}},
+		})
+		for _, block := range bodyContent.Blocks {
+			resourceType := block.Labels[0]
+			var result tagExpressionResult
+			if blockName, ok := blockTaggedResources[resourceType]; ok {
+				result, _ = extractTagBlocks(block.Body, blockName, nil, content)
+			} else {
+				result, _ = extractTagsFromBody(block.Body, tagAttributeName(resourceType),
+					tagFormatForResource(resourceType), nil, content)
+			}
+			index[resourceType+"."+block.Labels[1]] = resourcePositions{
+				Range:     newSourceRange(blockRange(block)),
+				TagsRange: newSourceRange(result.Range),
+				TagRanges: sourceRanges(result.ItemRanges),
+			}
+		}
+	}
+	return index
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// planConfiguration holds the parts of a plan's configuration that describe provider
+// configurations, the modules and the resources they declare
+type planConfiguration struct {
+	Configuration struct {
+		ProviderConfig map[string]struct {
+			Name          string                    `json:"name"`
+			Alias         string                    `json:"alias,omitempty"`
+			ModuleAddress string                    `json:"module_address,omitempty"`
+			Expressions   map[string]planExpression `json:"expressions,omitempty"`
+		} `json:"provider_config,omitempty"`
+		RootModule planModule `json:"root_module"`
+	} `json:"configuration"`
+}
+
+// planModule is a module in a plan's configuration
+type planModule struct {
+	Resources []struct {
+		Address           string `json:"address"`
+		ProviderConfigKey string `json:"provider_config_key"`
+	} `json:"resources,omitempty"`
+	ModuleCalls map[string]planModuleCall `json:"module_calls,omitempty"`
+}
+
+// planModuleCall is a module call in a plan's configuration, with the called module
+type planModuleCall struct {
+	Source  string     `json:"source"`
+	Version string     `json:"version,omitempty"`
+	Module  planModule `json:"module"`
+}
+
+// planExpression is an expression in a plan's configuration. Nested blocks appear as a list
+// of objects holding the expressions of their attributes.
+type planExpression struct {
+	ConstantValue any
+	Blocks        []map[string]planExpression
+}
+
+// UnmarshalJSON reads either a single expression or the list of objects of a nested block
+func (e *planExpression) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &e.Blocks)
+	}
+	var expr struct {
+		ConstantValue any `json:"constant_value,omitempty"`
+	}
+	if err := json.Unmarshal(data, &expr); err != nil {
+		return err
+	}
+	e.ConstantValue = expr.ConstantValue
+	return nil
+}
+
+// ParseTerraformPlanProviders returns the provider configurations of the root module of a
+// Terraform plan that ignore tags. Only constant ignore_tags values are known in a plan.
+func ParseTerraformPlanProviders(planPath string) ([]ProviderConfig, error) {
+	planData, err := os.ReadFile(filepath.Clean(planPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+	providers, _, err := parsePlanProviders(planData, planPath)
+	return providers, err
+}
+
+// parsePlanProviders returns the root module provider configurations of a plan that ignore
+// tags, and the provider configuration used by each root module resource by address
+func parsePlanProviders(planData []byte, planPath string) ([]ProviderConfig, map[string]string, error) {
+	var plan planConfiguration
+	if err := json.Unmarshal(planData, &plan); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse plan JSON: %w", err)
+	}
+
+	var providers []ProviderConfig
+	for _, config := range plan.Configuration.ProviderConfig {
+		if config.ModuleAddress != "" || !isAWSProvider(config.Name) {
+			continue
+		}
+		ignore := planIgnoreTags(config.Expressions["ignore_tags"])
+		if ignore.IsEmpty() {
+			continue
+		}
+		providers = append(providers, ProviderConfig{
+			Name:       config.Name,
+			Alias:      config.Alias,
+			Path:       planPath,
+			IgnoreTags: ignore,
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Key() < providers[j].Key()
+	})
+
+	resourceProviders := make(map[string]string)
+	for _, resource := range plan.Configuration.RootModule.Resources {
+		resourceProviders[resource.Address] = resource.ProviderConfigKey
+	}
+	return providers, resourceProviders, nil
+}
+
+// planIgnoreTags reads the constant keys and key prefixes of an ignore_tags block in a plan
+func planIgnoreTags(expr planExpression) IgnoreTags {
+	var ignore IgnoreTags
+	if len(expr.Blocks) == 0 {
+		return ignore
+	}
+	block := expr.Blocks[0]
+	ignore.Keys = planStringList(block["keys"])
+	ignore.KeyPrefixes = planStringList(block["key_prefixes"])
+	return ignore
+}
+
+// planStringList returns the strings of a constant list expression in a plan
+func planStringList(expr planExpression) []string {
+	values, ok := expr.ConstantValue.([]any)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// planResourceProvider returns the provider configuration of a root module resource in a plan,
+// or an empty string when it uses the default configuration of its provider
+func planResourceProvider(resourceType, name string, resourceProviders map[string]string) string {
+	key := resourceProviders[resourceType+"."+name]
+	// Keys of provider configurations declared in child modules are prefixed by the module
+	if _, after, found := strings.Cut(key, ":"); found {
+		key = after
+	}
+	if key == ImpliedProvider(resourceType) {
+		return ""
+	}
+	return key
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SourceRange is a range in a Terraform file. Lines and columns start at 1.
+type SourceRange struct {
+	Filename    string
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+// newSourceRange converts an HCL range
+func newSourceRange(r hcl.Range) SourceRange {
+	if r.Filename == "" {
+		return SourceRange{}
+	}
+	return SourceRange{
+		Filename:    r.Filename,
+		StartLine:   r.Start.Line,
+		StartColumn: r.Start.Column,
+		EndLine:     r.End.Line,
+		EndColumn:   r.End.Column,
+	}
+}
+
+// IsZero reports whether the range is unknown
+func (r SourceRange) IsZero() bool {
+	return r.Filename == ""
+}
+
+// String formats the start of the range as file:line:column
+func (r SourceRange) String() string {
+	if r.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d:%d", r.Filename, r.StartLine, r.StartColumn)
+}
+
+// Span formats the whole range as file:line:column-line:column
+func (r SourceRange) Span() string {
+	if r.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%s-%d:%d", r, r.EndLine, r.EndColumn)
+}
+
+// blockRange returns the range of a whole block, from its header to its closing brace.
+// Blocks in JSON files only have the range of their header.
+func blockRange(block *hcl.Block) hcl.Range {
+	if body, ok := block.Body.(*hclsyntax.Body); ok {
+		return hcl.RangeBetween(block.DefRange, body.SrcRange)
+	}
+	return block.DefRange
+}
+
+// tagItemRanges returns the ranges of the tags of a tags expression that are written with
+// literal keys, such as Name = "web" in a map or { key = "Name", value = "web" } in a list.
+// The arguments of merge() are searched as well, later arguments taking precedence.
+func tagItemRanges(expr hcl.Expression, format tagFormat) map[string]hcl.Range {
+	ranges := make(map[string]hcl.Range)
+	if args, ok := mergeArguments(expr); ok {
+		for _, arg := range args {
+			for key, r := range tagItemRanges(arg, format) {
+				ranges[key] = r
+			}
+		}
+		return ranges
+	}
+
+	switch format {
+	case tagFormatMap:
+		pairs, diags := hcl.ExprMap(expr)
+		if diags.HasErrors() {
+			return ranges
+		}
+		for _, pair := range pairs {
+			if key, ok := literalString(pair.Key); ok {
+				ranges[key] = hcl.RangeBetween(pair.Key.Range(), pair.Value.Range())
+			}
+		}
+	case tagFormatKeyValueList, tagFormatStringList:
+		items, diags := hcl.ExprList(expr)
+		if diags.HasErrors() {
+			return ranges
+		}
+		for _, item := range items {
+			if key, ok := listItemKey(item, format); ok {
+				ranges[key] = item.Range()
+			}
+		}
+	}
+	return ranges
+}
+
+// listItemKey returns the literal key of an item of a tags list: the key attribute of an
+// object, or the part before the colon of a "key:value" string
+func listItemKey(item hcl.Expression, format tagFormat) (string, bool) {
+	if format == tagFormatStringList {
+		str, ok := literalString(item)
+		if !ok {
+			return "", false
+		}
+		key, _, _ := strings.Cut(str, ":")
+		return key, true
+	}
+
+	pairs, diags := hcl.ExprMap(item)
+	if diags.HasErrors() {
+		return "", false
+	}
+	for _, pair := range pairs {
+		if name, ok := literalString(pair.Key); ok && name == "key" {
+			return literalString(pair.Value)
+		}
+	}
+	return "", false
+}
+
+// literalString evaluates an expression without a context, returning its value when it is a
+// literal string. Bare object keys such as Name in { Name = "web" } are literal strings.
+func literalString(expr hcl.Expression) (string, bool) {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// sourceRanges converts HCL ranges by key
+func sourceRanges(ranges map[string]hcl.Range) map[string]SourceRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	result := make(map[string]SourceRange, len(ranges))
+	for key, r := range ranges {
+		result[key] = newSourceRange(r)
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFile_Positions(t *testing.T) {
+	content := `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+
+  tags = merge(local.common, {
+    Name  = "logs"
+    Owner = "platform"
+  })
+}
+
+resource "azurerm_resource_group" "main" {
+  name = "main"
+}
+`
+	path := filepath.Join(t.TempDir(), "main.tf")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, err := ParseFile(path, "ERROR")
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(resources))
+	}
+
+	bucket := resources[0]
+	if bucket.Range.StartLine != 1 || bucket.Range.EndLine != 8 {
+		t.Errorf("Expected resource range lines 1-8, got %s", bucket.Range.Span())
+	}
+	if bucket.TagsRange.StartLine != 4 || bucket.TagsRange.StartColumn != 3 {
+		t.Errorf("Expected tags at line 4 column 3, got %s", bucket.TagsRange)
+	}
+	expectedLines := map[string]int{"Name": 5, "Owner": 6}
+	for tag, line := range expectedLines {
+		if bucket.TagRanges[tag].StartLine != line {
+			t.Errorf("Expected tag %s at line %d, got %s", tag, line, bucket.TagRanges[tag])
+		}
+	}
+	if bucket.TagRanges["Name"].String() != path+":5:5" {
+		t.Errorf("Expected Name at %s:5:5, got %s", path, bucket.TagRanges["Name"])
+	}
+
+	group := resources[1]
+	if group.Range.StartLine != 10 {
+		t.Errorf("Expected resource at line 10, got %s", group.Range)
+	}
+	if !group.TagsRange.IsZero() {
+		t.Errorf("Expected no tags range for an untagged resource, got %s", group.TagsRange)
+	}
+}
+
+func TestLocatePlanResources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `module "bucket" {
+  source = "./modules/bucket"
+}
+
+resource "aws_instance" "web" {
+  tags = { Name = "web" }
+}
+`,
+		"modules/bucket/main.tf": `resource "aws_s3_bucket" "this" {
+  bucket = "data"
+  tags = {
+    Name = "data"
+  }
+}
+`,
+		"plan.json": `{
+  "configuration": {
+    "root_module": {
+      "module_calls": {
+        "bucket": {"source": "./modules/bucket", "module": {}}
+      }
+    }
+  }
+}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	direct := []Resource{
+		{Type: "aws_instance", Name: "web"},
+		{Type: "aws_instance", Name: "missing"},
+	}
+	modules := []ModuleResource{{
+		Resource: Resource{Type: "aws_s3_bucket", Name: "this", ModulePath: "module.bucket"},
+	}}
+	if err := LocatePlanResources(filepath.Join(dir, "plan.json"), dir, direct, modules); err != nil {
+		t.Fatalf("LocatePlanResources failed: %v", err)
+	}
+
+	if direct[0].Range.Filename != filepath.Join(dir, "main.tf") || direct[0].Range.StartLine != 5 {
+		t.Errorf("Expected aws_instance.web at main.tf:5, got %s", direct[0].Range)
+	}
+	if direct[0].TagRanges["Name"].StartLine != 6 {
+		t.Errorf("Expected Name tag at line 6, got %s", direct[0].TagRanges["Name"])
+	}
+	if !direct[1].Range.IsZero() {
+		t.Errorf("Expected no range for a resource missing from the configuration, got %s", direct[1].Range)
+	}
+
+	module := modules[0]
+	if module.Range.Filename != filepath.Join(dir, "modules", "bucket", "main.tf") || module.Range.StartLine != 1 {
+		t.Errorf("Expected module resource at modules/bucket/main.tf:1, got %s", module.Range)
+	}
+	if module.TagsRange.StartLine != 3 || module.TagRanges["Name"].StartLine != 4 {
+		t.Errorf("Expected tags at line 3 and Name at line 4, got %s and %s", module.TagsRange, module.TagRanges["Name"])
+	}
+}
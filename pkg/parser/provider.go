@@ -4,187 +4,313 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/terratags/terratags/pkg/logging"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // ProviderConfig represents a Terraform provider configuration
 type ProviderConfig struct {
-	Name        string
+	Name string
+	// Alias is the alias of the provider configuration, empty for the default configuration
+	Alias       string
 	DefaultTags map[string]string
 	Path        string
+	// UnknownTags holds default tag keys whose values are only known after apply
+	UnknownTags map[string]bool
+	// IgnoreTags holds the tags the provider configuration does not manage
+	IgnoreTags IgnoreTags
+}
+
+// IgnoreTags holds the keys and key prefixes of an AWS provider ignore_tags block. Terraform
+// does not manage tags matching them, so they are neither applied nor reported by the provider.
+type IgnoreTags struct {
+	Keys        []string
+	KeyPrefixes []string
+}
+
+// IsEmpty reports whether no tags are ignored
+func (i IgnoreTags) IsEmpty() bool {
+	return len(i.Keys) == 0 && len(i.KeyPrefixes) == 0
+}
+
+// Matches reports whether a tag key is ignored, comparing case-insensitively when ignoreCase is set
+func (i IgnoreTags) Matches(key string, ignoreCase bool) bool {
+	if ignoreCase {
+		key = strings.ToLower(key)
+	}
+	for _, ignored := range i.Keys {
+		if ignoreCase {
+			ignored = strings.ToLower(ignored)
+		}
+		if key == ignored {
+			return true
+		}
+	}
+	for _, prefix := range i.KeyPrefixes {
+		if ignoreCase {
+			prefix = strings.ToLower(prefix)
+		}
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeIgnoredTags deletes the tags matching ignore_tags from a tag map
+func removeIgnoredTags(tags map[string]string, ignore IgnoreTags) {
+	if ignore.IsEmpty() {
+		return
+	}
+	for key := range tags {
+		if ignore.Matches(key, false) {
+			logging.Debug("Ignoring tag %s covered by provider ignore_tags", key)
+			delete(tags, key)
+		}
+	}
+}
+
+// Key returns the address resources use to refer to the provider configuration, such as
+// "aws" or "aws.west"
+func (p ProviderConfig) Key() string {
+	return ProviderKey(p.Name, p.Alias)
+}
+
+// ProviderKey returns the address of a provider configuration from its name and alias
+func ProviderKey(name, alias string) string {
+	if alias == "" {
+		return name
+	}
+	return name + "." + alias
+}
+
+// ImpliedProvider returns the name of the provider Terraform uses for a resource type when
+// the resource does not set the provider meta-argument: the prefix before the first underscore
+func ImpliedProvider(resourceType string) string {
+	name, _, _ := strings.Cut(resourceType, "_")
+	return name
 }
 
 // ParseProviderBlocks parses a Terraform file and extracts provider configurations
 func ParseProviderBlocks(path string) ([]ProviderConfig, error) {
+	return ParseProviderBlocksWithContext(path, nil)
+}
+
+// ParseProviderBlocksWithContext parses a Terraform file and extracts provider configurations,
+// resolving variables and locals in default tags from the module context when one is given
+func ParseProviderBlocksWithContext(path string, mctx *ModuleContext) ([]ProviderConfig, error) {
 	content, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Convert content to string
-	fileContent := string(content)
+	file, diags := parseTerraformFile(hclparse.NewParser(), content, path)
+	if diags.HasErrors() {
+		return nil, &ParseError{Path: path, Diagnostics: newDiagnostics(diags, path, DiagnosticError)}
+	}
+
+	bodyContent, _, _ := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "provider", LabelNames: []string{"name"}},
+		},
+	})
 
+	ctx := mctx.evalContext()
 	var providers []ProviderConfig
 
-	// Find AWS-style provider blocks with default_tags as a nested block
-	awsProviderPattern := `provider\s+"([^"]+)"\s*{([\s\S]*?default_tags[\s\S]*?{[\s\S]*?}[\s\S]*?)}`
-	awsProviderRegex := regexp.MustCompile(`(?s)` + awsProviderPattern)
-	awsProviderMatches := awsProviderRegex.FindAllStringSubmatch(fileContent, -1)
-
-	for _, match := range awsProviderMatches {
-		if len(match) > 2 {
-			providerName := match[1]
-			providerBody := match[2]
-
-			// We're only interested in AWS providers that might have default_tags
-			if strings.HasPrefix(providerName, "aws") && !strings.HasPrefix(providerName, "awscc") {
-				defaultTags := extractDefaultTagsFromProviderBody(providerBody)
-				if len(defaultTags) > 0 {
-					logging.Debug("Found AWS provider %s with default_tags", providerName)
-					for tag, value := range defaultTags {
-						logging.Debug("Found default tag key: %s with value: %s", tag, value)
-					}
-					providers = append(providers, ProviderConfig{
-						Name:        providerName,
-						DefaultTags: defaultTags,
-						Path:        path,
-					})
-				}
-			}
+	for _, block := range bodyContent.Blocks {
+		providerName := block.Labels[0]
+		defaultTags, unknownTags := extractProviderDefaultTags(providerName, block.Body, ctx, content)
+		ignoreTags := extractProviderIgnoreTags(providerName, block.Body, ctx)
+		if len(defaultTags) == 0 && ignoreTags.IsEmpty() {
+			continue
 		}
-	}
+		alias := providerAlias(block.Body)
 
-	// Find azapi-style provider blocks with default_tags as a direct attribute
-	azapiProviderPattern := `provider\s+"azapi"\s*{([\s\S]*?default_tags\s*=\s*{([\s\S]*?)}[\s\S]*?)}`
-	azapiProviderRegex := regexp.MustCompile(`(?s)` + azapiProviderPattern)
-	azapiProviderMatches := azapiProviderRegex.FindAllStringSubmatch(fileContent, -1)
-
-	for _, match := range azapiProviderMatches {
-		if len(match) > 2 {
-			providerName := "azapi"
-			tagContent := match[2]
-
-			// Extract key-value pairs from the tags block
-			defaultTags := make(map[string]string)
-			keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-			keyValueRegex := regexp.MustCompile(keyValuePattern)
-			keyValueMatches := keyValueRegex.FindAllStringSubmatch(tagContent, -1)
-
-			for _, kvMatch := range keyValueMatches {
-				if len(kvMatch) > 2 {
-					key := kvMatch[1]
-					value := kvMatch[2]
-					defaultTags[key] = value
-				}
-			}
-
-			if len(defaultTags) > 0 {
-				logging.Debug("Found azapi provider with default_tags")
-				for tag, value := range defaultTags {
-					logging.Debug("Found default tag key: %s with value: %s", tag, value)
-				}
-				providers = append(providers, ProviderConfig{
-					Name:        providerName,
-					DefaultTags: defaultTags,
-					Path:        path,
-				})
-			}
+		logging.Debug("Found %s provider with default tags", ProviderKey(providerName, alias))
+		for tag, value := range defaultTags {
+			logging.Debug("Found default tag key: %s with value: %s", tag, value)
 		}
+		providers = append(providers, ProviderConfig{
+			Name:        providerName,
+			Alias:       alias,
+			DefaultTags: defaultTags,
+			Path:        path,
+			UnknownTags: unknownTags,
+			IgnoreTags:  ignoreTags,
+		})
 	}
 
-	// Find Google and Google Beta provider blocks with default_labels as a direct attribute
-	googleProviderPattern := `provider\s+"(google|google-beta)"\s*{([\s\S]*?default_labels\s*=\s*{([\s\S]*?)}[\s\S]*?)}`
-	googleProviderRegex := regexp.MustCompile(`(?s)` + googleProviderPattern)
-	googleProviderMatches := googleProviderRegex.FindAllStringSubmatch(fileContent, -1)
-
-	for _, match := range googleProviderMatches {
-		if len(match) > 3 {
-			providerName := match[1] // "google" or "google-beta"
-			labelContent := match[3]
-
-			// Extract key-value pairs from the labels block
-			defaultLabels := make(map[string]string)
-			keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-			keyValueRegex := regexp.MustCompile(keyValuePattern)
-			keyValueMatches := keyValueRegex.FindAllStringSubmatch(labelContent, -1)
-
-			for _, kvMatch := range keyValueMatches {
-				if len(kvMatch) > 2 {
-					key := kvMatch[1]
-					value := kvMatch[2]
-					defaultLabels[key] = value
-				}
-			}
-
-			if len(defaultLabels) > 0 {
-				logging.Debug("Found %s provider with default_labels", providerName)
-				for label, value := range defaultLabels {
-					logging.Debug("Found default label key: %s with value: %s", label, value)
-				}
-				providers = append(providers, ProviderConfig{
-					Name:        providerName,
-					DefaultTags: defaultLabels,
-					Path:        path,
-				})
-			}
-		}
+	return providers, nil
+}
+
+// isAWSProvider reports whether a provider name is the AWS provider, not AWS Cloud Control
+func isAWSProvider(providerName string) bool {
+	return strings.HasPrefix(providerName, "aws") && !strings.HasPrefix(providerName, "awscc")
+}
+
+// extractProviderIgnoreTags extracts the keys and key prefixes of an AWS provider's
+// ignore_tags block. Elements that are only known after apply are skipped.
+func extractProviderIgnoreTags(providerName string, body hcl.Body, ctx *hcl.EvalContext) IgnoreTags {
+	var ignore IgnoreTags
+	if !isAWSProvider(providerName) {
+		return ignore
+	}
+
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "ignore_tags"}},
+	})
+	if len(content.Blocks) == 0 {
+		return ignore
 	}
 
-	// Find Datadog provider blocks with default_tags as a nested block
-	datadogProviderPattern := `provider\s+"datadog"\s*{([\s\S]*?default_tags[\s\S]*?{[\s\S]*?}[\s\S]*?)}`
-	datadogProviderRegex := regexp.MustCompile(`(?s)` + datadogProviderPattern)
-	datadogProviderMatches := datadogProviderRegex.FindAllStringSubmatch(fileContent, -1)
-
-	for _, match := range datadogProviderMatches {
-		if len(match) > 1 {
-			providerName := "datadog"
-			providerBody := match[1]
-
-			defaultTags := extractDefaultTagsFromProviderBody(providerBody)
-			if len(defaultTags) > 0 {
-				logging.Debug("Found Datadog provider with default_tags")
-				for tag, value := range defaultTags {
-					logging.Debug("Found default tag key: %s with value: %s", tag, value)
-				}
-				providers = append(providers, ProviderConfig{
-					Name:        providerName,
-					DefaultTags: defaultTags,
-					Path:        path,
-				})
-			}
+	attrs, _, _ := content.Blocks[0].Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "keys"}, {Name: "key_prefixes"}},
+	})
+	if attr, exists := attrs.Attributes["keys"]; exists {
+		ignore.Keys = evaluateStringList(attr, ctx)
+	}
+	if attr, exists := attrs.Attributes["key_prefixes"]; exists {
+		ignore.KeyPrefixes = evaluateStringList(attr, ctx)
+	}
+	return ignore
+}
+
+// evaluateStringList evaluates an attribute holding a list or set of strings, returning its
+// known elements
+func evaluateStringList(attr *hcl.Attribute, ctx *hcl.EvalContext) []string {
+	value, diags := attr.Expr.Value(withUnknownReferences(attr.Expr, ctx))
+	value, _ = value.UnmarkDeep()
+	if diags.HasErrors() {
+		logging.Warn("Unable to evaluate %s: %s", attr.Name, diags.Error())
+		return nil
+	}
+	if !value.IsKnown() || value.IsNull() || !value.CanIterateElements() {
+		return nil
+	}
+
+	var result []string
+	for it := value.ElementIterator(); it.Next(); {
+		_, element := it.Element()
+		if !element.IsKnown() || element.IsNull() {
+			continue
+		}
+		str, err := ctyToString(element)
+		if err != nil {
+			logging.Warn("%s: %s %s", attr.Expr.Range(), attr.Name, err)
+			continue
 		}
+		result = append(result, str)
 	}
+	return result
+}
 
-	return providers, nil
+// providerAlias returns the alias of a provider block, or an empty string for the default
+// configuration
+func providerAlias(body hcl.Body) string {
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "alias"}},
+	})
+	attr, exists := content.Attributes["alias"]
+	if !exists {
+		return ""
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || value.Type() != cty.String {
+		logging.Warn("%s: provider alias must be a literal string", attr.Expr.Range())
+		return ""
+	}
+	return value.AsString()
 }
 
-// extractDefaultTagsFromProviderBody extracts default_tags from a provider block body
-func extractDefaultTagsFromProviderBody(providerBody string) map[string]string {
-	defaultTags := make(map[string]string)
-
-	// Find the default_tags block within the provider - improved pattern
-	defaultTagsPattern := `default_tags\s*{[\s\S]*?tags\s*=\s*{([\s\S]*?)}`
-	defaultTagsRegex := regexp.MustCompile(`(?s)` + defaultTagsPattern)
-	defaultTagsMatch := defaultTagsRegex.FindStringSubmatch(providerBody)
-
-	if len(defaultTagsMatch) > 1 {
-		// Extract key-value pairs from the tags block
-		tagContent := defaultTagsMatch[1]
-		keyValuePattern := `["']?([A-Za-z0-9_-]+)["']?\s*=\s*["']?([^,"'}\s]*)["']?`
-		keyValueRegex := regexp.MustCompile(keyValuePattern)
-		keyValueMatches := keyValueRegex.FindAllStringSubmatch(tagContent, -1)
-
-		for _, match := range keyValueMatches {
-			if len(match) > 2 {
-				key := match[1]
-				value := match[2]
-				defaultTags[key] = value
-			}
+// providerReference returns the provider configuration address of a provider meta-argument
+// expression such as aws.west, or of a module providers map key or value
+func providerReference(expr hcl.Expression) (string, bool) {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() || len(traversal) == 0 || len(traversal) > 2 {
+		return "", false
+	}
+	name := traversal.RootName()
+	if len(traversal) == 1 {
+		return name, true
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return ProviderKey(name, attr.Name), true
+}
+
+// resourceProvider returns the provider configuration set by a resource's provider
+// meta-argument, or an empty string when the resource uses the default configuration
+func resourceProvider(body hcl.Body) string {
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "provider"}},
+	})
+	attr, exists := content.Attributes["provider"]
+	if !exists {
+		return ""
+	}
+	provider, ok := providerReference(attr.Expr)
+	if !ok {
+		logging.Warn("%s: provider must be a reference to a provider configuration", attr.Expr.Range())
+		return ""
+	}
+	return provider
+}
+
+// moduleProviders returns the provider configurations passed by a module call's providers
+// argument, mapping the configuration names used in the module to those of the caller.
+// It returns nil when the module call has no providers argument.
+func moduleProviders(attr *hcl.Attribute, modulePath string) map[string]string {
+	if attr == nil {
+		return nil
+	}
+	pairs, diags := hcl.ExprMap(attr.Expr)
+	if diags.HasErrors() {
+		logging.Warn("%s: providers of %s must be a map of provider configurations", attr.Expr.Range(), modulePath)
+		return nil
+	}
+	providers := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		child, childOk := providerReference(pair.Key)
+		parent, parentOk := providerReference(pair.Value)
+		if !childOk || !parentOk {
+			logging.Warn("%s: unable to read provider configuration passed to %s", pair.Key.Range(), modulePath)
+			continue
+		}
+		providers[child] = parent
+	}
+	return providers
+}
+
+// extractProviderDefaultTags extracts the default tags of a provider block. AWS and Datadog
+// declare them in a nested default_tags block, azapi in a default_tags attribute and
+// Google in a default_labels attribute.
+func extractProviderDefaultTags(providerName string, body hcl.Body, ctx *hcl.EvalContext, src []byte) (map[string]string, map[string]bool) {
+	var result tagExpressionResult
+
+	switch {
+	case isAWSProvider(providerName) || providerName == "datadog":
+		content, _, _ := body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: "default_tags"}},
+		})
+		if len(content.Blocks) == 0 {
+			return nil, nil
 		}
+		result, _ = extractTagsFromBody(content.Blocks[0].Body, "tags", tagFormatMap, ctx, src)
+	case providerName == "azapi":
+		result, _ = extractTagsFromBody(body, "default_tags", tagFormatMap, ctx, src)
+	case providerName == "google" || providerName == "google-beta":
+		result, _ = extractTagsFromBody(body, "default_labels", tagFormatMap, ctx, src)
+	default:
+		return nil, nil
 	}
 
-	return defaultTags
+	logTagErrors(fmt.Sprintf("provider.%s", providerName), result.Errors)
+	return result.Tags, result.Unknown
 }
@@ -142,3 +142,58 @@ provider "google-beta" {
 		})
 	}
 }
+
+func TestParseProviderBlocks_Aliases(t *testing.T) {
+	content := `
+provider "aws" {
+  default_tags {
+    tags = { Environment = "prod" }
+  }
+}
+
+provider "aws" {
+  alias = "west"
+  default_tags {
+    tags = { Environment = "dr" }
+  }
+}
+
+resource "aws_s3_bucket" "default" {
+  tags = { Name = "default" }
+}
+
+resource "aws_s3_bucket" "west" {
+  provider = aws.west
+  tags     = { Name = "west" }
+}
+`
+	tmpFile := filepath.Join(t.TempDir(), "main.tf")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	providers, err := ParseProviderBlocks(tmpFile)
+	if err != nil {
+		t.Fatalf("ParseProviderBlocks failed: %v", err)
+	}
+	if len(providers) != 2 {
+		t.Fatalf("Expected 2 providers, got %d", len(providers))
+	}
+	if providers[0].Key() != "aws" || providers[1].Key() != "aws.west" {
+		t.Errorf("Expected provider keys aws and aws.west, got %s and %s", providers[0].Key(), providers[1].Key())
+	}
+	if providers[1].DefaultTags["Environment"] != "dr" {
+		t.Errorf("Expected aws.west default tags, got %v", providers[1].DefaultTags)
+	}
+
+	resources, err := ParseFile(tmpFile, "ERROR")
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	expected := map[string]string{"default": "aws", "west": "aws.west"}
+	for _, resource := range resources {
+		if resource.ProviderKey() != expected[resource.Name] {
+			t.Errorf("Expected %s to use provider %s, got %s", resource.Name, expected[resource.Name], resource.ProviderKey())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/terratags/terratags/pkg/logging"
+)
+
+// stateModule is a module in the JSON output of terraform show for a state. The root module
+// has an empty address; child modules have addresses such as "module.vpc.module.subnets".
+type stateModule struct {
+	Address      string          `json:"address,omitempty"`
+	Resources    []stateResource `json:"resources"`
+	ChildModules []stateModule   `json:"child_modules"`
+}
+
+// stateResource is a resource instance recorded in a state
+type stateResource struct {
+	Address string         `json:"address"`
+	Mode    string         `json:"mode"`
+	Type    string         `json:"type"`
+	Name    string         `json:"name"`
+	Values  map[string]any `json:"values"`
+}
+
+// ParseTerraformStateWithModules parses the JSON output of terraform show for a state and
+// extracts both root module and module resources. Child modules are walked recursively.
+// States do not record module sources, so module resources have an unknown source.
+func ParseTerraformStateWithModules(statePath string) ([]Resource, []ModuleResource, error) {
+	stateData, err := os.ReadFile(filepath.Clean(statePath))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state struct {
+		Values *struct {
+			RootModule stateModule `json:"root_module"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(stateData, &state); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse state JSON: %w", err)
+	}
+	if state.Values == nil {
+		// An empty state has no values
+		logging.Debug("State %s has no resources", statePath)
+		return nil, nil, nil
+	}
+
+	var directResources []Resource
+	var moduleResources []ModuleResource
+	var walk func(module stateModule)
+	walk = func(module stateModule) {
+		for _, rs := range module.Resources {
+			if rs.Mode != "managed" || !isTaggableResource(rs.Type) || rs.Values == nil {
+				continue
+			}
+
+			tags, propagate := planResourceTags(rs.Type, rs.Values)
+			resource := Resource{
+				Type:              rs.Type,
+				Name:              rs.Name,
+				Tags:              tags,
+				Path:              statePath,
+				TagSources:        make(map[string]TagSource),
+				PropagateAtLaunch: propagate,
+				LocationTags:      extractLocationTagsFromPlanResource(rs.Type, rs.Values),
+				Address:           rs.Address,
+				InstanceKey:       instanceKey(rs.Address, module.Address, rs.Type, rs.Name),
+			}
+
+			if module.Address == "" {
+				directResources = append(directResources, resource)
+				continue
+			}
+			for key, value := range resource.Tags {
+				resource.TagSources[key] = TagSource{Source: "resource", Value: value}
+			}
+			resource.ModulePath = module.Address
+			resource.ModuleSource = "unknown"
+			moduleResources = append(moduleResources, newModuleResource(resource, extractModuleName(module.Address)))
+		}
+		for _, child := range module.ChildModules {
+			walk(child)
+		}
+	}
+	walk(state.Values.RootModule)
+
+	return directResources, moduleResources, nil
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTerraformStateWithModules(t *testing.T) {
+	content := `{
+  "format_version": "1.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.logs",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "logs",
+          "values": {
+            "tags": {"Name": "logs"},
+            "tags_all": {"Name": "logs", "Owner": "platform"}
+          }
+        },
+        {
+          "address": "data.aws_vpc.main",
+          "mode": "data",
+          "type": "aws_vpc",
+          "name": "main",
+          "values": {"tags": {"Name": "main"}}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.network",
+          "child_modules": [
+            {
+              "address": "module.network.module.gcp",
+              "resources": [
+                {
+                  "address": "module.network.module.gcp.google_storage_bucket.data",
+                  "mode": "managed",
+                  "type": "google_storage_bucket",
+                  "name": "data",
+                  "values": {
+                    "labels": {"name": "data"},
+                    "effective_labels": {"name": "data", "owner": "platform"}
+                  }
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, moduleResources, err := ParseTerraformStateWithModules(path)
+	if err != nil {
+		t.Fatalf("ParseTerraformStateWithModules failed: %v", err)
+	}
+
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 root module resource (data sources skipped), got %d", len(resources))
+	}
+	if resources[0].Type != "aws_s3_bucket" || resources[0].Tags["Owner"] != "platform" {
+		t.Errorf("Expected aws_s3_bucket with tags from tags_all, got %s with %v", resources[0].Type, resources[0].Tags)
+	}
+
+	if len(moduleResources) != 1 {
+		t.Fatalf("Expected 1 module resource, got %d", len(moduleResources))
+	}
+	bucket := moduleResources[0]
+	if bucket.ModulePath != "module.network.module.gcp" {
+		t.Errorf("Expected module path module.network.module.gcp, got %s", bucket.ModulePath)
+	}
+	if bucket.Tags["owner"] != "platform" {
+		t.Errorf("Expected labels from effective_labels, got %v", bucket.Tags)
+	}
+	if bucket.TagSources["owner"].Source != "resource" {
+		t.Errorf("Expected tag source resource, got %q", bucket.TagSources["owner"].Source)
+	}
+}
+
+func TestParseTerraformStateWithModules_EmptyState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"format_version": "1.0"}`), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, moduleResources, err := ParseTerraformStateWithModules(path)
+	if err != nil {
+		t.Fatalf("ParseTerraformStateWithModules failed: %v", err)
+	}
+	if len(resources) != 0 || len(moduleResources) != 0 {
+		t.Errorf("Expected no resources in an empty state, got %d and %d", len(resources), len(moduleResources))
+	}
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/terratags/terratags/pkg/logging"
+)
+
+// suppressionDirective marks a comment as an inline suppression
+const suppressionDirective = "terratags:ignore"
+
+// Suppression is an inline suppression annotation in a comment directly above a resource or
+// module block, such as:
+//
+//	# terratags:ignore Owner,Project reason="shared bucket, ticket OPS-123"
+//
+// Without a tag list, all required tags are suppressed.
+type Suppression struct {
+	Tags   []string // Suppressed tags, "*" for all
+	Reason string
+	Path   string
+	Line   int
+}
+
+// commentLines maps the line numbers of a native syntax file to the line comments on them
+type commentLines map[int]string
+
+// collectComments lexes a native syntax file and returns its line comments by line number.
+// JSON files have no comments.
+func collectComments(content []byte, path string) commentLines {
+	if strings.HasSuffix(path, ".json") {
+		return nil
+	}
+	tokens, _ := hclsyntax.LexConfig(content, path, hcl.InitialPos)
+	comments := make(commentLines)
+	for _, token := range tokens {
+		if token.Type != hclsyntax.TokenComment {
+			continue
+		}
+		text := strings.TrimSpace(string(token.Bytes))
+		if strings.HasPrefix(text, "/*") {
+			continue
+		}
+		comments[token.Range.Start.Line] = text
+	}
+	return comments
+}
+
+// suppressionsAbove returns the suppressions in the comment lines directly above a block
+func (c commentLines) suppressionsAbove(block *hcl.Block, path string) []Suppression {
+	if len(c) == 0 {
+		return nil
+	}
+	var suppressions []Suppression
+	for line := block.DefRange.Start.Line - 1; line > 0; line-- {
+		comment, exists := c[line]
+		if !exists {
+			break
+		}
+		if suppression, ok := parseSuppression(comment); ok {
+			suppression.Path = path
+			suppression.Line = line
+			suppressions = append(suppressions, suppression)
+		}
+	}
+	return suppressions
+}
+
+// parseSuppression parses a terratags:ignore comment
+func parseSuppression(comment string) (Suppression, bool) {
+	text := strings.TrimSpace(strings.TrimLeft(comment, "#/"))
+	rest, found := strings.CutPrefix(text, suppressionDirective)
+	if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return Suppression{}, false
+	}
+
+	var suppression Suppression
+	tagList := rest
+	if index := strings.Index(rest, "reason="); index >= 0 {
+		tagList = rest[:index]
+		reason := strings.TrimSpace(rest[index+len("reason="):])
+		if unquoted, err := strconv.Unquote(reason); err == nil {
+			reason = unquoted
+		} else if strings.HasPrefix(reason, `"`) {
+			logging.Warn("Unterminated reason in comment: %s", comment)
+			reason = strings.Trim(reason, `"`)
+		}
+		suppression.Reason = reason
+	}
+
+	for _, tag := range strings.Split(tagList, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			suppression.Tags = append(suppression.Tags, tag)
+		}
+	}
+	if len(suppression.Tags) == 0 {
+		suppression.Tags = []string{"*"}
+	}
+	return suppression, true
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseSuppression(t *testing.T) {
+	tests := []struct {
+		name     string
+		comment  string
+		expected Suppression
+		ok       bool
+	}{
+		{
+			name:     "Tags and reason",
+			comment:  `# terratags:ignore Owner,Project reason="shared bucket, ticket OPS-123"`,
+			expected: Suppression{Tags: []string{"Owner", "Project"}, Reason: "shared bucket, ticket OPS-123"},
+			ok:       true,
+		},
+		{
+			name:     "Spaces in tag list",
+			comment:  `// terratags:ignore Owner, Project`,
+			expected: Suppression{Tags: []string{"Owner", "Project"}},
+			ok:       true,
+		},
+		{
+			name:     "All tags",
+			comment:  `# terratags:ignore reason="legacy"`,
+			expected: Suppression{Tags: []string{"*"}, Reason: "legacy"},
+			ok:       true,
+		},
+		{
+			name:    "Other directive",
+			comment: `# terratags:ignored Owner`,
+		},
+		{
+			name:    "Regular comment",
+			comment: `# Bucket for shared logs`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppression, ok := parseSuppression(tt.comment)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok %v, got %v", tt.ok, ok)
+			}
+			if ok && !reflect.DeepEqual(suppression, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, suppression)
+			}
+		})
+	}
+}
+
+func TestParseFile_Suppressions(t *testing.T) {
+	content := `
+# Shared with the data platform team
+# terratags:ignore Owner reason="shared bucket, ticket OPS-123"
+resource "aws_s3_bucket" "shared" {
+  tags = { Name = "shared" }
+}
+
+# terratags:ignore Owner
+
+resource "aws_s3_bucket" "detached" {
+  tags = { Name = "detached" }
+}
+
+resource "aws_s3_bucket" "plain" {
+  # terratags:ignore Owner
+  tags = { Name = "plain" }
+}
+`
+	tmpFile := filepath.Join(t.TempDir(), "main.tf")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, err := ParseFile(tmpFile, "ERROR")
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(resources) != 3 {
+		t.Fatalf("Expected 3 resources, got %d", len(resources))
+	}
+
+	for _, resource := range resources {
+		if resource.Name != "shared" {
+			if len(resource.Suppressions) != 0 {
+				t.Errorf("Expected no suppressions for %s, got %+v", resource.Name, resource.Suppressions)
+			}
+			continue
+		}
+		expected := []Suppression{{
+			Tags:   []string{"Owner"},
+			Reason: "shared bucket, ticket OPS-123",
+			Path:   tmpFile,
+			Line:   3,
+		}}
+		if !reflect.DeepEqual(resource.Suppressions, expected) {
+			t.Errorf("Expected suppressions %+v, got %+v", expected, resource.Suppressions)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// blockTaggedResources maps resource types that declare tags as repeated nested blocks,
+// such as tag { key, value, propagate_at_launch }, to the name of that block. These
+// resources have no tags attribute in the provider schema, so they are maintained here
+// rather than in the generated taggable resource lists.
+var blockTaggedResources = map[string]string{
+	"aws_autoscaling_group": "tag",
+}
+
+// UsesTagBlocks reports whether a resource type declares its tags as nested blocks.
+// AWS provider default_tags are not applied to these resources.
+func UsesTagBlocks(resourceType string) bool {
+	_, exists := blockTaggedResources[resourceType]
+	return exists
+}
+
+// tagBlockAttributes are the attributes of a tag block
+var tagBlockAttributes = []hcl.AttributeSchema{
+	{Name: "key"},
+	{Name: "value"},
+	{Name: "propagate_at_launch"},
+}
+
+// extractTagBlocks reads tags declared as repeated nested blocks and as dynamic blocks
+// generating them. It also returns the propagate_at_launch setting of each tag whose
+// setting is known.
+func extractTagBlocks(body hcl.Body, blockName string, ctx *hcl.EvalContext, src []byte) (tagExpressionResult, map[string]bool) {
+	result := newTagExpressionResult()
+	propagate := make(map[string]bool)
+
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: blockName},
+			{Type: "dynamic", LabelNames: []string{"type"}},
+		},
+	})
+
+	for _, block := range content.Blocks {
+		switch {
+		case block.Type == blockName:
+			evaluateTagBlock(block.Body, func(expr hcl.Expression) *hcl.EvalContext {
+				return withUnknownReferences(expr, ctx)
+			}, src, &result, propagate)
+		case block.Type == "dynamic" && block.Labels[0] == blockName:
+			evaluateDynamicTagBlock(block, ctx, src, &result, propagate)
+		default:
+			continue
+		}
+		if result.Range.Filename == "" {
+			result.Range = blockRange(block)
+		} else {
+			result.Range = hcl.RangeBetween(result.Range, blockRange(block))
+		}
+	}
+
+	return result, propagate
+}
+
+// evaluateDynamicTagBlock expands a dynamic tag block over its for_each collection
+func evaluateDynamicTagBlock(block *hcl.Block, ctx *hcl.EvalContext, src []byte, result *tagExpressionResult, propagate map[string]bool) {
+	content, _, _ := block.Body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "for_each"},
+			{Name: "iterator"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{{Type: "content"}},
+	})
+
+	forEachAttr, exists := content.Attributes["for_each"]
+	if !exists || len(content.Blocks) == 0 {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: dynamic block must set for_each and content", block.DefRange))
+		return
+	}
+
+	iterator := block.Labels[0]
+	if iteratorAttr, exists := content.Attributes["iterator"]; exists {
+		if name := hcl.ExprAsKeyword(iteratorAttr.Expr); name != "" {
+			iterator = name
+		}
+	}
+
+	forEach, diags := forEachAttr.Expr.Value(withUnknownReferences(forEachAttr.Expr, ctx))
+	forEach, unset := unmarkUnset(forEach)
+	if diags.HasErrors() {
+		result.Errors = append(result.Errors, diagnosticMessages(diags)...)
+		return
+	}
+	if !forEach.IsKnown() && len(unset) > 0 {
+		result.setAllUnresolved(forEachAttr.Expr.Range())
+		return
+	}
+	if !forEach.IsKnown() {
+		// The generated tag keys are only known after apply
+		result.AllUnknown = true
+		return
+	}
+	if forEach.IsNull() {
+		return
+	}
+	if !forEach.CanIterateElements() {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: for_each must be a map or collection, got %s",
+			forEachAttr.Expr.Range(), forEach.Type().FriendlyName()))
+		return
+	}
+
+	for it := forEach.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+		iteration := map[string]cty.Value{
+			iterator: cty.ObjectVal(map[string]cty.Value{"key": key, "value": value}),
+		}
+
+		var iterCtx *hcl.EvalContext
+		if ctx != nil {
+			iterCtx = ctx.NewChild()
+			iterCtx.Variables = iteration
+		} else {
+			iterCtx = &hcl.EvalContext{Variables: iteration}
+		}
+
+		evaluateTagBlock(content.Blocks[0].Body, func(expr hcl.Expression) *hcl.EvalContext {
+			if ctx == nil {
+				return iterCtx
+			}
+			return withUnknownReferences(expr, iterCtx)
+		}, src, result, propagate)
+	}
+}
+
+// evaluateTagBlock evaluates the key, value and propagate_at_launch attributes of a single
+// tag block, using scope to obtain the evaluation context for each expression
+func evaluateTagBlock(body hcl.Body, scope func(hcl.Expression) *hcl.EvalContext, src []byte, result *tagExpressionResult, propagate map[string]bool) {
+	content, _, _ := body.PartialContent(&hcl.BodySchema{Attributes: tagBlockAttributes})
+
+	keyAttr, hasKey := content.Attributes["key"]
+	valueAttr, hasValue := content.Attributes["value"]
+	if !hasKey || !hasValue {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: tag block must set both key and value", body.MissingItemRange()))
+		return
+	}
+
+	key, diags := keyAttr.Expr.Value(scope(keyAttr.Expr))
+	key, unset := unmarkUnset(key)
+	if !diags.HasErrors() && !key.IsKnown() && len(unset) > 0 {
+		result.setAllUnresolved(keyAttr.Expr.Range())
+		return
+	}
+	if !diags.HasErrors() && !key.IsKnown() {
+		result.AllUnknown = true
+		return
+	}
+	if diags.HasErrors() || key.IsNull() {
+		result.Errors = append(result.Errors, diagnosticMessages(diags)...)
+		return
+	}
+	keyStr, err := ctyToString(key)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: tag key %s", keyAttr.Expr.Range(), err))
+		return
+	}
+
+	evaluateTagItem(keyStr, valueAttr.Expr, scope(valueAttr.Expr), src, result)
+
+	delete(propagate, keyStr)
+	propagateAttr, exists := content.Attributes["propagate_at_launch"]
+	if !exists {
+		propagate[keyStr] = false
+		return
+	}
+	value, diags := propagateAttr.Expr.Value(scope(propagateAttr.Expr))
+	value, _ = value.UnmarkDeep()
+	if diags.HasErrors() {
+		result.Errors = append(result.Errors, diagnosticMessages(diags)...)
+		return
+	}
+	if !value.IsKnown() || value.IsNull() {
+		return
+	}
+	converted, err := convert.Convert(value, cty.Bool)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: propagate_at_launch must be a bool, got %s",
+			propagateAttr.Expr.Range(), value.Type().FriendlyName()))
+		return
+	}
+	propagate[keyStr] = converted.True()
+}
+
+// extractTagBlocksFromPlanResource extracts tags declared as nested blocks from a resource in
+// the plan, where they appear as a list of objects with key, value and propagate_at_launch
+func extractTagBlocksFromPlanResource(resource map[string]any, blockName string) (map[string]string, map[string]bool) {
+	tags := make(map[string]string)
+	propagate := make(map[string]bool)
+
+	blocks, ok := resource[blockName].([]any)
+	if !ok {
+		return tags, propagate
+	}
+
+	logging.Debug("Found %s blocks in plan resource", blockName)
+	for _, block := range blocks {
+		tagBlock, ok := block.(map[string]any)
+		if !ok {
+			continue
+		}
+		key, keyOk := tagBlock["key"].(string)
+		value, valueOk := tagBlock["value"].(string)
+		if !keyOk || !valueOk {
+			continue
+		}
+		tags[key] = value
+		if propagateAtLaunch, ok := tagBlock["propagate_at_launch"].(bool); ok {
+			propagate[key] = propagateAtLaunch
+		}
+	}
+
+	return tags, propagate
+}
@@ -0,0 +1,190 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFile_TagBlocks(t *testing.T) {
+	tests := []struct {
+		name              string
+		content           string
+		expected          map[string]string
+		expectedPropagate map[string]bool
+		expectUnknown     bool
+	}{
+		{
+			name: "Static tag blocks",
+			content: `
+resource "aws_autoscaling_group" "web" {
+  max_size = 3
+
+  tag {
+    key                 = "Name"
+    value               = "web asg"
+    propagate_at_launch = true
+  }
+
+  tag {
+    key                 = "Owner"
+    value               = "platform"
+    propagate_at_launch = false
+  }
+}
+`,
+			expected:          map[string]string{"Name": "web asg", "Owner": "platform"},
+			expectedPropagate: map[string]bool{"Name": true, "Owner": false},
+		},
+		{
+			name: "Dynamic tag block over a literal map",
+			content: `
+resource "aws_autoscaling_group" "web" {
+  dynamic "tag" {
+    for_each = {
+      Environment = "dev"
+      Project     = "terratags"
+    }
+    content {
+      key                 = tag.key
+      value               = tag.value
+      propagate_at_launch = true
+    }
+  }
+}
+`,
+			expected:          map[string]string{"Environment": "dev", "Project": "terratags"},
+			expectedPropagate: map[string]bool{"Environment": true, "Project": true},
+		},
+		{
+			name: "Dynamic tag block over a local with a custom iterator",
+			content: `
+locals {
+  tags = {
+    Name  = "web"
+    Owner = "platform"
+  }
+}
+
+resource "aws_autoscaling_group" "web" {
+  dynamic "tag" {
+    for_each = local.tags
+    iterator = t
+    content {
+      key                 = t.key
+      value               = t.value
+      propagate_at_launch = t.key == "Name"
+    }
+  }
+}
+`,
+			expected:          map[string]string{"Name": "web", "Owner": "platform"},
+			expectedPropagate: map[string]bool{"Name": true, "Owner": false},
+		},
+		{
+			name: "Dynamic tag block over an unknown value",
+			content: `
+resource "aws_autoscaling_group" "web" {
+  dynamic "tag" {
+    for_each = data.aws_default_tags.current.tags
+    content {
+      key                 = tag.key
+      value               = tag.value
+      propagate_at_launch = true
+    }
+  }
+}
+`,
+			expected:          map[string]string{},
+			expectedPropagate: map[string]bool{},
+			expectUnknown:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			tmpFile := filepath.Join(tmpDir, "main.tf")
+			if err := os.WriteFile(tmpFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			mctx, err := NewModuleContext(tmpDir, EvalOptions{})
+			if err != nil {
+				t.Fatalf("NewModuleContext failed: %v", err)
+			}
+			resources, err := ParseFileWithContext(tmpFile, mctx)
+			if err != nil {
+				t.Fatalf("ParseFileWithContext failed: %v", err)
+			}
+			if len(resources) != 1 {
+				t.Fatalf("Expected 1 resource, got %d", len(resources))
+			}
+
+			resource := resources[0]
+			if len(resource.Tags) != len(tt.expected) {
+				t.Errorf("Expected %d tags, got %d: %v", len(tt.expected), len(resource.Tags), resource.Tags)
+			}
+			for key, expectedValue := range tt.expected {
+				if actualValue := resource.Tags[key]; actualValue != expectedValue {
+					t.Errorf("Expected tag %s to have value %q, got %q", key, expectedValue, actualValue)
+				}
+			}
+			if len(resource.PropagateAtLaunch) != len(tt.expectedPropagate) {
+				t.Errorf("Expected propagate_at_launch %v, got %v", tt.expectedPropagate, resource.PropagateAtLaunch)
+			}
+			for key, expected := range tt.expectedPropagate {
+				if actual, exists := resource.PropagateAtLaunch[key]; !exists || actual != expected {
+					t.Errorf("Expected tag %s propagate_at_launch %v, got %v", key, expected, resource.PropagateAtLaunch)
+				}
+			}
+			if resource.TagsUnknown != tt.expectUnknown {
+				t.Errorf("Expected TagsUnknown %v, got %v", tt.expectUnknown, resource.TagsUnknown)
+			}
+			if len(resource.TagErrors) > 0 {
+				t.Errorf("Expected no tag errors, got %v", resource.TagErrors)
+			}
+		})
+	}
+}
+
+func TestParseTerraformPlan_TagBlocks(t *testing.T) {
+	planContent := `{
+  "resource_changes": [
+    {
+      "address": "aws_autoscaling_group.web",
+      "type": "aws_autoscaling_group",
+      "name": "web",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "tag": [
+            {"key": "Name", "value": "web", "propagate_at_launch": true},
+            {"key": "Owner", "value": "platform", "propagate_at_launch": false}
+          ]
+        }
+      }
+    }
+  ]
+}`
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planFile, []byte(planContent), 0644); err != nil {
+		t.Fatalf("Failed to write plan file: %v", err)
+	}
+
+	resources, err := ParseTerraformPlan(planFile, "ERROR")
+	if err != nil {
+		t.Fatalf("ParseTerraformPlan failed: %v", err)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("Expected 1 resource, got %d", len(resources))
+	}
+
+	resource := resources[0]
+	if resource.Tags["Name"] != "web" || resource.Tags["Owner"] != "platform" {
+		t.Errorf("Unexpected tags: %v", resource.Tags)
+	}
+	if !resource.PropagateAtLaunch["Name"] || resource.PropagateAtLaunch["Owner"] {
+		t.Errorf("Unexpected propagate_at_launch: %v", resource.PropagateAtLaunch)
+	}
+}
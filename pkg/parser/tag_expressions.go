@@ -0,0 +1,494 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// tagFormat describes how a provider family encodes tags in configuration
+type tagFormat int
+
+const (
+	// tagFormatMap is the common map format: tags = { Key = "Value" }
+	tagFormatMap tagFormat = iota
+	// tagFormatKeyValueList is the AWSCC format: tags = [{ key = "Key", value = "Value" }]
+	tagFormatKeyValueList
+	// tagFormatStringList is the Datadog format: tags = ["key:value"]
+	tagFormatStringList
+)
+
+// tagAttributeName returns the name of the attribute holding tags for a resource type
+func tagAttributeName(resourceType string) string {
+	// Google resources use labels instead of tags
+	if strings.HasPrefix(resourceType, "google_") {
+		return "labels"
+	}
+	return "tags"
+}
+
+// tagFormatForResource returns the tag encoding used by a resource type
+func tagFormatForResource(resourceType string) tagFormat {
+	switch {
+	case strings.HasPrefix(resourceType, "awscc_"):
+		return tagFormatKeyValueList
+	case strings.HasPrefix(resourceType, "datadog_"):
+		return tagFormatStringList
+	default:
+		return tagFormatMap
+	}
+}
+
+// tagExpressionResult holds the outcome of evaluating a tags expression
+type tagExpressionResult struct {
+	Tags   map[string]string
+	Errors []string
+	// Unknown holds keys whose values are only known after apply
+	Unknown map[string]bool
+	// AllUnknown is set when the set of tag keys itself is only known after apply
+	AllUnknown bool
+	// Range is the range of the tags attribute, or of the blocks declaring the tags
+	Range hcl.Range
+	// ItemRanges holds the ranges of the tags written with literal keys
+	ItemRanges map[string]hcl.Range
+}
+
+// newTagExpressionResult creates an empty tagExpressionResult
+func newTagExpressionResult() tagExpressionResult {
+	return tagExpressionResult{
+		Tags:    make(map[string]string),
+		Unknown: make(map[string]bool),
+	}
+}
+
+// setUnknown records a tag key whose value is only known after apply
+func (r *tagExpressionResult) setUnknown(key string) {
+	r.Tags[key] = UnknownTagValue
+	r.Unknown[key] = true
+}
+
+// setValue records a tag key with a known value
+func (r *tagExpressionResult) setValue(key, value string) {
+	r.Tags[key] = value
+	delete(r.Unknown, key)
+}
+
+// setUnresolved records a tag key whose value depends on a variable without a value. The tag
+// is left out, so it is reported as missing, and the reason is recorded as an error.
+func (r *tagExpressionResult) setUnresolved(key string, rng hcl.Range) {
+	delete(r.Tags, key)
+	delete(r.Unknown, key)
+	r.Errors = append(r.Errors, fmt.Sprintf("%s: value of tag %q depends on a variable without a value", rng, key))
+}
+
+// setAllUnresolved records that the tag keys depend on a variable without a value. The tags
+// whose values are unknown are left out instead of being treated as known after apply.
+func (r *tagExpressionResult) setAllUnresolved(rng hcl.Range) {
+	for key := range r.Unknown {
+		delete(r.Tags, key)
+		delete(r.Unknown, key)
+	}
+	r.AllUnknown = false
+	r.Errors = append(r.Errors, fmt.Sprintf("%s: tags depend on a variable without a value", rng))
+}
+
+// unmarkUnset removes the marks from an evaluated value and returns the paths of its parts
+// that depend on variables without a value
+func unmarkUnset(value cty.Value) (cty.Value, []cty.Path) {
+	unmarked, pathMarks := value.UnmarkDeepWithPaths()
+	var paths []cty.Path
+	for _, pm := range pathMarks {
+		if _, unset := pm.Marks[unsetVariableMark{}]; unset {
+			paths = append(paths, pm.Path)
+		}
+	}
+	return unmarked, paths
+}
+
+// applyUnset records the unknown parts of an evaluated tags value that depend on variables
+// without a value, by the tag key at the start of their path
+func (r *tagExpressionResult) applyUnset(paths []cty.Path, rng hcl.Range) {
+	for _, path := range paths {
+		if len(path) == 0 {
+			r.setAllUnresolved(rng)
+			continue
+		}
+		var key string
+		switch step := path[0].(type) {
+		case cty.GetAttrStep:
+			key = step.Name
+		case cty.IndexStep:
+			if step.Key.Type() == cty.String {
+				key = step.Key.AsString()
+			}
+		}
+		if r.Unknown[key] {
+			r.setUnresolved(key, rng)
+		}
+	}
+}
+
+// extractTagsFromBody reads the named tags attribute from a block body and evaluates it
+func extractTagsFromBody(body hcl.Body, attrName string, format tagFormat, ctx *hcl.EvalContext, src []byte) (tagExpressionResult, bool) {
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: attrName}},
+	})
+	attr, exists := content.Attributes[attrName]
+	if !exists {
+		return newTagExpressionResult(), false
+	}
+	result := evaluateTagExpression(attr.Expr, format, ctx, src)
+	result.Range = attr.Range
+	result.ItemRanges = tagItemRanges(attr.Expr, format)
+	return result, true
+}
+
+// evaluateTagExpression evaluates a tags expression into a map of tag keys to values.
+// When the whole expression cannot be evaluated, the individual items of object and
+// tuple constructors are evaluated one by one so that literal keys are still found.
+// With a nil context every reference is an error; otherwise references the context
+// cannot resolve are treated as values known only after apply.
+func evaluateTagExpression(expr hcl.Expression, format tagFormat, ctx *hcl.EvalContext, src []byte) tagExpressionResult {
+	result := newTagExpressionResult()
+	ctx = withUnknownReferences(expr, ctx)
+
+	value, diags := expr.Value(ctx)
+	value, unset := unmarkUnset(value)
+	if !diags.HasErrors() && format != tagFormatMap && !value.IsWhollyKnown() {
+		// Evaluate list entries one by one so only the entries that are
+		// actually unknown are reported as such
+		if _, listDiags := hcl.ExprList(expr); !listDiags.HasErrors() {
+			return evaluateTagItems(expr, format, ctx, src, diags)
+		}
+	}
+	if !diags.HasErrors() {
+		if !value.IsKnown() && format == tagFormatMap {
+			// merge() of a partially unknown set of maps is wholly unknown, but
+			// the keys of its known arguments are still worth reporting
+			if args, ok := mergeArguments(expr); ok {
+				for _, arg := range args {
+					argResult := evaluateTagExpression(arg, format, ctx, src)
+					for key, tagValue := range argResult.Tags {
+						if argResult.Unknown[key] {
+							result.setUnknown(key)
+						} else {
+							result.setValue(key, tagValue)
+						}
+					}
+					result.Errors = append(result.Errors, argResult.Errors...)
+					result.AllUnknown = result.AllUnknown || argResult.AllUnknown
+				}
+				return result
+			}
+		}
+		if err := collectTagValues(value, format, &result); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", expr.Range(), err))
+		}
+		result.applyUnset(unset, expr.Range())
+		return result
+	}
+
+	return evaluateTagItems(expr, format, ctx, src, diags)
+}
+
+// evaluateTagItems evaluates the items of an object or tuple constructor one by one,
+// reporting diags when the expression is not a constructor
+func evaluateTagItems(expr hcl.Expression, format tagFormat, ctx *hcl.EvalContext, src []byte, diags hcl.Diagnostics) tagExpressionResult {
+	result := newTagExpressionResult()
+
+	switch format {
+	case tagFormatMap:
+		items, itemDiags := hcl.ExprMap(expr)
+		if itemDiags.HasErrors() {
+			result.Errors = append(result.Errors, diagnosticMessages(diags)...)
+			return result
+		}
+		for _, item := range items {
+			key, keyDiags := item.Key.Value(ctx)
+			key, unset := unmarkUnset(key)
+			if !keyDiags.HasErrors() && !key.IsKnown() {
+				if len(unset) > 0 {
+					result.setAllUnresolved(item.Key.Range())
+				} else {
+					result.AllUnknown = true
+				}
+				continue
+			}
+			if keyDiags.HasErrors() || key.IsNull() {
+				result.Errors = append(result.Errors, diagnosticMessages(keyDiags)...)
+				continue
+			}
+			keyStr, err := ctyToString(key)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: tag key %s", item.Key.Range(), err))
+				continue
+			}
+			evaluateTagItem(keyStr, item.Value, ctx, src, &result)
+		}
+	case tagFormatKeyValueList:
+		elems, elemDiags := hcl.ExprList(expr)
+		if elemDiags.HasErrors() {
+			result.Errors = append(result.Errors, diagnosticMessages(diags)...)
+			return result
+		}
+		for _, elem := range elems {
+			fields, fieldDiags := hcl.ExprMap(elem)
+			if fieldDiags.HasErrors() {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: tag entry is not an object", elem.Range()))
+				continue
+			}
+			var keyExpr, valueExpr hcl.Expression
+			for _, field := range fields {
+				switch hcl.ExprAsKeyword(field.Key) {
+				case "key":
+					keyExpr = field.Value
+				case "value":
+					valueExpr = field.Value
+				}
+			}
+			if keyExpr == nil || valueExpr == nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: tag entry must set both key and value", elem.Range()))
+				continue
+			}
+			key, keyDiags := keyExpr.Value(ctx)
+			key, unset := unmarkUnset(key)
+			if !keyDiags.HasErrors() && !key.IsKnown() {
+				if len(unset) > 0 {
+					result.setAllUnresolved(keyExpr.Range())
+				} else {
+					result.AllUnknown = true
+				}
+				continue
+			}
+			if keyDiags.HasErrors() || key.IsNull() {
+				result.Errors = append(result.Errors, diagnosticMessages(keyDiags)...)
+				continue
+			}
+			keyStr, err := ctyToString(key)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: tag key %s", keyExpr.Range(), err))
+				continue
+			}
+			evaluateTagItem(keyStr, valueExpr, ctx, src, &result)
+		}
+	case tagFormatStringList:
+		elems, elemDiags := hcl.ExprList(expr)
+		if elemDiags.HasErrors() {
+			result.Errors = append(result.Errors, diagnosticMessages(diags)...)
+			return result
+		}
+		for _, elem := range elems {
+			value, valueDiags := elem.Value(ctx)
+			value, unset := unmarkUnset(value)
+			if !valueDiags.HasErrors() && value.IsKnown() && !value.IsNull() {
+				if str, err := ctyToString(value); err == nil {
+					key, tagValue := splitDatadogTag(str)
+					result.setValue(key, tagValue)
+					continue
+				}
+			}
+			// The key of a "key:${value}" template is still known from its source text
+			text := strings.Trim(string(elem.Range().SliceBytes(src)), `"`)
+			idx := strings.Index(text, ":")
+			hasLiteralKey := idx > 0 && !strings.Contains(text[:idx], "${")
+			if !valueDiags.HasErrors() && !value.IsKnown() {
+				switch {
+				case hasLiteralKey && len(unset) > 0:
+					result.setUnresolved(text[:idx], elem.Range())
+				case hasLiteralKey:
+					result.setUnknown(text[:idx])
+				case len(unset) > 0:
+					result.setAllUnresolved(elem.Range())
+				default:
+					result.AllUnknown = true
+				}
+				continue
+			}
+			if hasLiteralKey {
+				result.setValue(text[:idx], text[idx+1:])
+			}
+			result.Errors = append(result.Errors, diagnosticMessages(valueDiags)...)
+		}
+	}
+
+	return result
+}
+
+// mergeArguments returns the arguments of a merge() call expression
+func mergeArguments(expr hcl.Expression) ([]hcl.Expression, bool) {
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() || call.Name != "merge" {
+		return nil, false
+	}
+	return call.Arguments, true
+}
+
+// evaluateTagItem evaluates a single tag value whose key is already known
+func evaluateTagItem(key string, valueExpr hcl.Expression, ctx *hcl.EvalContext, src []byte, result *tagExpressionResult) {
+	value, diags := valueExpr.Value(ctx)
+	value, unset := unmarkUnset(value)
+	if diags.HasErrors() {
+		// Keep the key so the tag is not reported as missing, using the source text as its value
+		result.setValue(key, string(valueExpr.Range().SliceBytes(src)))
+		result.Errors = append(result.Errors, diagnosticMessages(diags)...)
+		return
+	}
+	if !value.IsKnown() {
+		if len(unset) > 0 {
+			result.setUnresolved(key, valueExpr.Range())
+		} else {
+			result.setUnknown(key)
+		}
+		return
+	}
+	if value.IsNull() {
+		// A null tag value means Terraform does not set the tag
+		return
+	}
+	str, err := ctyToString(value)
+	if err != nil {
+		result.setValue(key, string(valueExpr.Range().SliceBytes(src)))
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: value of tag %q %s", valueExpr.Range(), key, err))
+		return
+	}
+	result.setValue(key, str)
+}
+
+// collectTagValues converts an evaluated tags value into tag keys and values
+func collectTagValues(value cty.Value, format tagFormat, result *tagExpressionResult) error {
+	if !value.IsKnown() {
+		result.AllUnknown = true
+		return nil
+	}
+	if value.IsNull() {
+		return nil
+	}
+	if !value.CanIterateElements() {
+		return fmt.Errorf("tags must be a %s, got %s", formatDescription(format), value.Type().FriendlyName())
+	}
+
+	switch format {
+	case tagFormatMap:
+		if !value.Type().IsMapType() && !value.Type().IsObjectType() {
+			return fmt.Errorf("tags must be a map, got %s", value.Type().FriendlyName())
+		}
+		for it := value.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			if !elem.IsKnown() {
+				result.setUnknown(key.AsString())
+				continue
+			}
+			if elem.IsNull() {
+				continue
+			}
+			str, err := ctyToString(elem)
+			if err != nil {
+				return fmt.Errorf("value of tag %q %s", key.AsString(), err)
+			}
+			result.setValue(key.AsString(), str)
+		}
+	case tagFormatKeyValueList:
+		for it := value.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			if !elem.IsKnown() {
+				result.AllUnknown = true
+				continue
+			}
+			if elem.IsNull() || !elem.Type().IsObjectType() ||
+				!elem.Type().HasAttribute("key") || !elem.Type().HasAttribute("value") {
+				return fmt.Errorf("tag entries must be objects with key and value attributes")
+			}
+			keyValue := elem.GetAttr("key")
+			if !keyValue.IsKnown() {
+				result.AllUnknown = true
+				continue
+			}
+			key, err := ctyToString(keyValue)
+			if err != nil {
+				return fmt.Errorf("tag key %s", err)
+			}
+			if !elem.GetAttr("value").IsKnown() {
+				result.setUnknown(key)
+				continue
+			}
+			str, err := ctyToString(elem.GetAttr("value"))
+			if err != nil {
+				return fmt.Errorf("value of tag %q %s", key, err)
+			}
+			result.setValue(key, str)
+		}
+	case tagFormatStringList:
+		for it := value.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			if !elem.IsKnown() {
+				result.AllUnknown = true
+				continue
+			}
+			str, err := ctyToString(elem)
+			if err != nil {
+				return fmt.Errorf("tag %s", err)
+			}
+			key, tagValue := splitDatadogTag(str)
+			result.setValue(key, tagValue)
+		}
+	}
+	return nil
+}
+
+// splitDatadogTag splits a Datadog "key:value" tag on its first colon
+func splitDatadogTag(tag string) (string, string) {
+	if idx := strings.Index(tag, ":"); idx >= 0 {
+		return tag[:idx], tag[idx+1:]
+	}
+	// Datadog allows tags without a value
+	return tag, ""
+}
+
+// ctyToString converts a primitive cty value to its string form
+func ctyToString(value cty.Value) (string, error) {
+	if value.IsNull() {
+		return "", fmt.Errorf("is null")
+	}
+	if !value.IsKnown() {
+		return "", fmt.Errorf("is not known")
+	}
+	converted, err := convert.Convert(value, cty.String)
+	if err != nil {
+		return "", fmt.Errorf("must be a string, got %s", value.Type().FriendlyName())
+	}
+	return converted.AsString(), nil
+}
+
+// formatDescription describes a tag format for error messages
+func formatDescription(format tagFormat) string {
+	switch format {
+	case tagFormatKeyValueList:
+		return "list of key/value objects"
+	case tagFormatStringList:
+		return "list of key:value strings"
+	default:
+		return "map"
+	}
+}
+
+// diagnosticMessages renders HCL error diagnostics as "file:line,col: summary; detail" strings
+func diagnosticMessages(diags hcl.Diagnostics) []string {
+	var messages []string
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		msg := diag.Summary
+		if diag.Detail != "" {
+			msg = fmt.Sprintf("%s; %s", diag.Summary, diag.Detail)
+		}
+		if diag.Subject != nil {
+			msg = fmt.Sprintf("%s: %s", diag.Subject, msg)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFile_TagExpressions(t *testing.T) {
+	tests := []struct {
+		name           string
+		content        string
+		expected       map[string]string
+		expectTagError bool
+	}{
+		{
+			name: "AWS tags after nested blocks",
+			content: `
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+  root_block_device {
+    volume_size = 20
+  }
+
+  tags = {
+    Name = "web server 01"
+  }
+}
+`,
+			expected: map[string]string{"Name": "web server 01"},
+		},
+		{
+			name: "Quoted keys with slashes and colons",
+			content: `
+resource "aws_subnet" "private" {
+  tags = {
+    "kubernetes.io/cluster/main" = "shared"
+    "aws:createdBy"              = "terraform"
+    Owner                        = "platform"
+  }
+}
+`,
+			expected: map[string]string{
+				"kubernetes.io/cluster/main": "shared",
+				"aws:createdBy":              "terraform",
+				"Owner":                      "platform",
+			},
+		},
+		{
+			name: "Non-string literal values",
+			content: `
+resource "aws_s3_bucket" "data" {
+  tags = {
+    Replicas = 3
+    Public   = false
+    Skipped  = null
+  }
+}
+`,
+			expected: map[string]string{"Replicas": "3", "Public": "false"},
+		},
+		{
+			name: "Google labels",
+			content: `
+resource "google_storage_bucket" "data" {
+  labels = {
+    environment = "dev"
+  }
+}
+`,
+			expected: map[string]string{"environment": "dev"},
+		},
+		{
+			name: "AWSCC key/value list",
+			content: `
+resource "awscc_s3_bucket" "data" {
+  tags = [
+    {
+      key   = "Name"
+      value = "Data Bucket"
+    },
+  ]
+}
+`,
+			expected: map[string]string{"Name": "Data Bucket"},
+		},
+		{
+			name: "Datadog string list",
+			content: `
+resource "datadog_monitor" "cpu" {
+  tags = ["team:platform", "url:https://example.com", "critical"]
+}
+`,
+			expected: map[string]string{"team": "platform", "url": "https://example.com", "critical": ""},
+		},
+		{
+			name: "Unresolvable value keeps the literal key",
+			content: `
+resource "aws_s3_bucket" "data" {
+  tags = {
+    Name  = "data"
+    Owner = var.owner
+  }
+}
+`,
+			expected:       map[string]string{"Name": "data", "Owner": "var.owner"},
+			expectTagError: true,
+		},
+		{
+			name: "Unresolvable tags expression",
+			content: `
+resource "aws_s3_bucket" "data" {
+  tags = var.tags
+}
+`,
+			expected:       map[string]string{},
+			expectTagError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := filepath.Join(t.TempDir(), "main.tf")
+			if err := os.WriteFile(tmpFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			resources, err := ParseFile(tmpFile, "ERROR")
+			if err != nil {
+				t.Fatalf("ParseFile failed: %v", err)
+			}
+			if len(resources) != 1 {
+				t.Fatalf("Expected 1 resource, got %d", len(resources))
+			}
+
+			resource := resources[0]
+			if len(resource.Tags) != len(tt.expected) {
+				t.Errorf("Expected %d tags, got %d: %v", len(tt.expected), len(resource.Tags), resource.Tags)
+			}
+			for key, expectedValue := range tt.expected {
+				if actualValue, exists := resource.Tags[key]; !exists {
+					t.Errorf("Expected tag %s not found", key)
+				} else if actualValue != expectedValue {
+					t.Errorf("Expected tag %s to have value %q, got %q", key, expectedValue, actualValue)
+				}
+			}
+			if hasErrors := len(resource.TagErrors) > 0; hasErrors != tt.expectTagError {
+				t.Errorf("Expected tag errors: %v, got %v", tt.expectTagError, resource.TagErrors)
+			}
+		})
+	}
+}
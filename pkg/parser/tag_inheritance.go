@@ -1,14 +1,13 @@
 package parser
 
 import (
-	"path/filepath"
-
 	"github.com/terratags/terratags/pkg/logging"
 )
 
 // ModuleTagInheritance handles tag inheritance from module calls to resources
 type ModuleTagInheritance struct {
-	moduleTags map[string]map[string]string // module name -> tags
+	moduleTags  map[string]map[string]string // module path -> tags
+	diagnostics []Diagnostic                 // files LoadModuleTags could not fully analyze
 }
 
 // NewModuleTagInheritance creates a new tag inheritance handler
@@ -20,22 +19,28 @@ func NewModuleTagInheritance() *ModuleTagInheritance {
 
 // LoadModuleTags loads tags from module calls in Terraform files
 func (m *ModuleTagInheritance) LoadModuleTags(terraformDir string) error {
-	files, err := filepath.Glob(filepath.Join(terraformDir, "*.tf"))
+	files, err := FindTerraformFiles(terraformDir)
+	if err != nil {
+		return err
+	}
+
+	mctx, err := NewModuleContext(terraformDir, EvalOptions{})
 	if err != nil {
 		return err
 	}
 
 	for _, file := range files {
-		resources, err := ParseFile(file, "ERROR")
+		resources, diagnostics, err := ParseFileWithDiagnostics(file, mctx)
 		if err != nil {
-			logging.Debug("Skipping file %s due to parse error: %v", file, err)
-			continue // Skip files with parse errors
+			logging.Warn("Skipping file %s due to parse error: %v", file, err)
+			m.diagnostics = append(m.diagnostics, FileDiagnostics(file, err)...)
+			continue
 		}
+		m.diagnostics = append(m.diagnostics, diagnostics...)
 
 		for _, resource := range resources {
 			if resource.Type == "module" {
-				m.moduleTags[resource.Name] = resource.Tags
-				logging.Debug("Loaded tags for module %s: %v", resource.Name, resource.Tags)
+				m.AddModuleTags("module."+resource.Name, resource.Tags)
 			}
 		}
 	}
@@ -43,24 +48,42 @@ func (m *ModuleTagInheritance) LoadModuleTags(terraformDir string) error {
 	return nil
 }
 
-// InheritTags applies module tags to module resources
+// Diagnostics returns the parse errors and skipped blocks of the files read by LoadModuleTags,
+// whose module calls may be missing
+func (m *ModuleTagInheritance) Diagnostics() []Diagnostic {
+	return m.diagnostics
+}
+
+// AddModuleTags records the tags passed to the module call at the given module path
+func (m *ModuleTagInheritance) AddModuleTags(modulePath string, tags map[string]string) {
+	m.moduleTags[modulePath] = tags
+	logging.Debug("Loaded tags for %s: %v", modulePath, tags)
+}
+
+// InheritTags records which tags of a module resource were passed in by its module call.
+// Module resources only receive the caller's tags where the module forwards them, for
+// example through merge(var.tags, ...), so tags are attributed rather than added.
 func (m *ModuleTagInheritance) InheritTags(moduleResource *ModuleResource) {
 	if moduleResource == nil {
 		return
 	}
 
-	if moduleTags, exists := m.moduleTags[moduleResource.ModuleName]; exists {
-		// Merge module tags with resource tags (resource tags take precedence)
-		for key, value := range moduleTags {
-			if _, exists := moduleResource.Tags[key]; !exists {
-				moduleResource.Tags[key] = value
-				moduleResource.TagSources[key] = TagSource{
-					Source: "module_call",
-					Value:  value,
-				}
-				logging.Debug("Inherited tag %s=%s for resource %s from module %s",
-					key, value, moduleResource.Name, moduleResource.ModuleName)
+	moduleTags, exists := m.moduleTags[moduleResource.Resource.ModulePath]
+	if !exists {
+		return
+	}
+	if moduleResource.TagSources == nil {
+		moduleResource.TagSources = make(map[string]TagSource)
+	}
+
+	for key, value := range moduleTags {
+		if resourceValue, exists := moduleResource.Tags[key]; exists && resourceValue == value {
+			moduleResource.TagSources[key] = TagSource{
+				Source: "module_call",
+				Value:  value,
 			}
+			logging.Debug("Inherited tag %s=%s for resource %s from %s",
+				key, value, moduleResource.Name, moduleResource.Resource.ModulePath)
 		}
 	}
 }
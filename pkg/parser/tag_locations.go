@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LocationTags holds the tags found at a secondary tag location of a resource, such as
+// volume_tags or the tags of a launch template's tag_specifications
+type LocationTags struct {
+	Location    string // Location name used in configuration, e.g. "ebs_block_device.tags"
+	Name        string // Name of this occurrence, e.g. "ebs_block_device[1].tags"
+	Tags        map[string]string
+	TagErrors   []string
+	UnknownTags map[string]bool
+	TagsUnknown bool
+	Range       SourceRange // Range of the tags attribute
+	// InheritsDefaultTags is set when provider default tags apply to the location, as they do
+	// to the tags of block devices. Plans already include them in the tags read.
+	InheritsDefaultTags bool
+}
+
+// tagLocationKind describes where a secondary tag location is declared
+type tagLocationKind int
+
+const (
+	// tagLocationAttribute is a tags attribute on the resource itself: volume_tags = {...}
+	tagLocationAttribute tagLocationKind = iota
+	// tagLocationBlock is a tags attribute in a nested block: root_block_device { tags = {...} }
+	tagLocationBlock
+	// tagLocationSpecification is a tags attribute in nested blocks told apart by their
+	// resource_type: tag_specifications { resource_type = "instance" tags = {...} }
+	tagLocationSpecification
+)
+
+// tagLocation describes a secondary tag location of a resource type
+type tagLocation struct {
+	Kind      tagLocationKind
+	Block     string // Nested block name, for block and specification locations
+	Attribute string // Attribute holding the tags
+	Repeated  bool   // Whether the nested block may appear more than once
+}
+
+// name returns the configuration name of the location
+func (l tagLocation) name() string {
+	if l.Kind == tagLocationAttribute {
+		return l.Attribute
+	}
+	if l.Kind == tagLocationSpecification {
+		return l.Block
+	}
+	return l.Block + "." + l.Attribute
+}
+
+// instanceBlockDeviceTagLocations are the volume tag locations of resources launching EC2 instances
+var instanceBlockDeviceTagLocations = []tagLocation{
+	{Kind: tagLocationAttribute, Attribute: "volume_tags"},
+	{Kind: tagLocationBlock, Block: "root_block_device", Attribute: "tags"},
+	{Kind: tagLocationBlock, Block: "ebs_block_device", Attribute: "tags", Repeated: true},
+}
+
+// secondaryTagLocations lists, per resource type, the tag locations other than the
+// resource's own tags
+var secondaryTagLocations = map[string][]tagLocation{
+	"aws_instance":              instanceBlockDeviceTagLocations,
+	"aws_spot_instance_request": instanceBlockDeviceTagLocations,
+	"aws_launch_template": {
+		{Kind: tagLocationSpecification, Block: "tag_specifications", Attribute: "tags"},
+	},
+}
+
+// specificationLocationName returns the name of a tag_specifications location for a resource type
+func specificationLocationName(block, resourceType string) string {
+	return fmt.Sprintf("%s[%s]", block, resourceType)
+}
+
+// extractLocationTags reads the secondary tag locations declared by a resource block
+func extractLocationTags(resourceType string, body hcl.Body, ctx *hcl.EvalContext, src []byte) []LocationTags {
+	locations, exists := secondaryTagLocations[resourceType]
+	if !exists {
+		return nil
+	}
+
+	var result []LocationTags
+	for _, location := range locations {
+		switch location.Kind {
+		case tagLocationAttribute:
+			tags, exists := extractTagsFromBody(body, location.Attribute, tagFormatMap, ctx, src)
+			if exists {
+				result = append(result, newLocationTags(location.name(), location.name(), tags))
+			}
+		case tagLocationBlock, tagLocationSpecification:
+			content, _, _ := body.PartialContent(&hcl.BodySchema{
+				Blocks: []hcl.BlockHeaderSchema{{Type: location.Block}},
+			})
+			for i, block := range content.Blocks {
+				configName, name := location.name(), location.name()
+				if location.Kind == tagLocationSpecification {
+					specType, ok := specificationResourceType(block.Body, ctx)
+					if !ok {
+						continue
+					}
+					configName = specificationLocationName(location.Block, specType)
+					name = configName
+				} else if location.Repeated {
+					name = fmt.Sprintf("%s[%d].%s", location.Block, i, location.Attribute)
+				}
+				tags, exists := extractTagsFromBody(block.Body, location.Attribute, tagFormatMap, ctx, src)
+				if exists {
+					locationTags := newLocationTags(configName, name, tags)
+					locationTags.InheritsDefaultTags = location.Kind == tagLocationBlock
+					result = append(result, locationTags)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// specificationResourceType evaluates the resource_type of a tag_specifications block
+func specificationResourceType(body hcl.Body, ctx *hcl.EvalContext) (string, bool) {
+	content, _, _ := body.PartialContent(&hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "resource_type"}},
+	})
+	attr, exists := content.Attributes["resource_type"]
+	if !exists {
+		return "", false
+	}
+	value, diags := attr.Expr.Value(withUnknownReferences(attr.Expr, ctx))
+	value, _ = value.UnmarkDeep()
+	if diags.HasErrors() || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// newLocationTags converts an evaluated tags expression into LocationTags
+func newLocationTags(location, name string, result tagExpressionResult) LocationTags {
+	return LocationTags{
+		Location:    location,
+		Name:        name,
+		Tags:        result.Tags,
+		TagErrors:   result.Errors,
+		UnknownTags: result.Unknown,
+		TagsUnknown: result.AllUnknown,
+		Range:       newSourceRange(result.Range),
+	}
+}
+
+// extractLocationTagsFromPlanResource reads the secondary tag locations of a resource in the
+// plan. Nested blocks appear as lists of objects, and tags_all is preferred where the
+// provider reports the tags merged with its default tags.
+func extractLocationTagsFromPlanResource(resourceType string, resource map[string]any) []LocationTags {
+	locations, exists := secondaryTagLocations[resourceType]
+	if !exists {
+		return nil
+	}
+
+	var result []LocationTags
+	for _, location := range locations {
+		switch location.Kind {
+		case tagLocationAttribute:
+			if tags, ok := planTagMap(resource, location.Attribute); ok {
+				result = append(result, LocationTags{Location: location.name(), Name: location.name(), Tags: tags})
+			}
+		case tagLocationBlock, tagLocationSpecification:
+			blocks, ok := resource[location.Block].([]any)
+			if !ok {
+				continue
+			}
+			for i, block := range blocks {
+				blockMap, ok := block.(map[string]any)
+				if !ok {
+					continue
+				}
+				tags, ok := planTagMap(blockMap, location.Attribute+"_all")
+				if !ok {
+					tags, ok = planTagMap(blockMap, location.Attribute)
+				}
+				if !ok {
+					continue
+				}
+
+				configName, name := location.name(), location.name()
+				if location.Kind == tagLocationSpecification {
+					specType, ok := blockMap["resource_type"].(string)
+					if !ok {
+						continue
+					}
+					configName = specificationLocationName(location.Block, specType)
+					name = configName
+				} else if location.Repeated {
+					name = fmt.Sprintf("%s[%d].%s", location.Block, i, location.Attribute)
+				}
+				result = append(result, LocationTags{Location: configName, Name: name, Tags: tags})
+			}
+		}
+	}
+	return result
+}
+
+// planTagMap reads a map of string tags from a plan object, reporting whether it was set
+func planTagMap(object map[string]any, key string) (map[string]string, bool) {
+	values, ok := object[key].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	tags := make(map[string]string)
+	for k, v := range values {
+		if str, ok := v.(string); ok {
+			tags[k] = str
+		}
+	}
+	return tags, true
+}
@@ -0,0 +1,167 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseFile_LocationTags(t *testing.T) {
+	content := `
+resource "aws_instance" "web" {
+  ami = "ami-123"
+
+  tags = {
+    Name = "web"
+  }
+
+  volume_tags = {
+    Name = "web-volumes"
+  }
+
+  root_block_device {
+    volume_size = 20
+    tags = {
+      Owner = "platform"
+    }
+  }
+
+  ebs_block_device {
+    device_name = "/dev/sdb"
+  }
+
+  ebs_block_device {
+    device_name = "/dev/sdc"
+    tags = {
+      Project = "terratags"
+    }
+  }
+}
+
+resource "aws_launch_template" "web" {
+  tags = {
+    Name = "web-template"
+  }
+
+  tag_specifications {
+    resource_type = "instance"
+    tags = {
+      Name = "web-instance"
+    }
+  }
+
+  tag_specifications {
+    resource_type = "volume"
+    tags = {
+      Name = "web-volume"
+    }
+  }
+}
+`
+	tmpFile := filepath.Join(t.TempDir(), "main.tf")
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	resources, err := ParseFile(tmpFile, "ERROR")
+	if err != nil {
+		t.Fatalf("ParseFile failed: %v", err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(resources))
+	}
+
+	expected := map[string][]LocationTags{
+		"aws_instance": {
+			{Location: "volume_tags", Name: "volume_tags", Tags: map[string]string{"Name": "web-volumes"}},
+			{Location: "root_block_device.tags", Name: "root_block_device.tags", Tags: map[string]string{"Owner": "platform"}},
+			{Location: "ebs_block_device.tags", Name: "ebs_block_device[1].tags", Tags: map[string]string{"Project": "terratags"}},
+		},
+		"aws_launch_template": {
+			{Location: "tag_specifications[instance]", Name: "tag_specifications[instance]", Tags: map[string]string{"Name": "web-instance"}},
+			{Location: "tag_specifications[volume]", Name: "tag_specifications[volume]", Tags: map[string]string{"Name": "web-volume"}},
+		},
+	}
+
+	for _, resource := range resources {
+		want := expected[resource.Type]
+		if len(resource.LocationTags) != len(want) {
+			t.Fatalf("Expected %d tag locations for %s, got %+v", len(want), resource.Type, resource.LocationTags)
+		}
+		for i, location := range resource.LocationTags {
+			if location.Location != want[i].Location || location.Name != want[i].Name {
+				t.Errorf("Expected location %s (%s), got %s (%s)", want[i].Location, want[i].Name, location.Location, location.Name)
+			}
+			if !reflect.DeepEqual(location.Tags, want[i].Tags) {
+				t.Errorf("Expected %s tags %v, got %v", want[i].Name, want[i].Tags, location.Tags)
+			}
+		}
+	}
+}
+
+func TestParseTerraformPlan_LocationTags(t *testing.T) {
+	planContent := `{
+  "resource_changes": [
+    {
+      "address": "aws_instance.web",
+      "type": "aws_instance",
+      "name": "web",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "tags": {"Name": "web"},
+          "volume_tags": null,
+          "root_block_device": [
+            {"tags": {"Owner": "platform"}, "tags_all": {"Owner": "platform", "Environment": "dev"}}
+          ],
+          "ebs_block_device": []
+        }
+      }
+    },
+    {
+      "address": "aws_launch_template.web",
+      "type": "aws_launch_template",
+      "name": "web",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "tag_specifications": [
+            {"resource_type": "instance", "tags": {"Name": "web-instance"}}
+          ]
+        }
+      }
+    }
+  ]
+}`
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planFile, []byte(planContent), 0644); err != nil {
+		t.Fatalf("Failed to write plan file: %v", err)
+	}
+
+	resources, err := ParseTerraformPlan(planFile, "ERROR")
+	if err != nil {
+		t.Fatalf("ParseTerraformPlan failed: %v", err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(resources))
+	}
+
+	for _, resource := range resources {
+		if len(resource.LocationTags) != 1 {
+			t.Fatalf("Expected 1 tag location for %s, got %+v", resource.Type, resource.LocationTags)
+		}
+		location := resource.LocationTags[0]
+		switch resource.Type {
+		case "aws_instance":
+			expected := map[string]string{"Owner": "platform", "Environment": "dev"}
+			if location.Name != "root_block_device.tags" || !reflect.DeepEqual(location.Tags, expected) {
+				t.Errorf("Expected root_block_device.tags %v, got %s %v", expected, location.Name, location.Tags)
+			}
+		case "aws_launch_template":
+			if location.Name != "tag_specifications[instance]" || location.Tags["Name"] != "web-instance" {
+				t.Errorf("Unexpected launch template location: %+v", location)
+			}
+		}
+	}
+}
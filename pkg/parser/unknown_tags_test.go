@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPlanUnknownTags(t *testing.T) {
+	tests := []struct {
+		name            string
+		resourceType    string
+		after           string
+		afterUnknown    string
+		expectedTags    map[string]string
+		expectedUnknown map[string]bool
+		expectAll       bool
+	}{
+		{
+			name:         "Known tags",
+			resourceType: "aws_s3_bucket",
+			after:        `{"tags": {"Name": "logs"}}`,
+			afterUnknown: `{}`,
+			expectedTags: map[string]string{"Name": "logs"},
+		},
+		{
+			name:            "Unknown value",
+			resourceType:    "aws_s3_bucket",
+			after:           `{"tags": {"Name": "logs"}, "tags_all": {"Name": "logs"}}`,
+			afterUnknown:    `{"tags": {"Owner": true}, "tags_all": {"Owner": true}}`,
+			expectedTags:    map[string]string{"Name": "logs", "Owner": UnknownTagValue},
+			expectedUnknown: map[string]bool{"Owner": true},
+		},
+		{
+			name:         "Unknown tags_all",
+			resourceType: "aws_s3_bucket",
+			after:        `{"tags": {"Name": "logs"}}`,
+			afterUnknown: `{"tags_all": true}`,
+			expectedTags: map[string]string{"Name": "logs"},
+			expectAll:    true,
+		},
+		{
+			name:            "Unknown AWSCC tag value",
+			resourceType:    "awscc_s3_bucket",
+			after:           `{"tags": [{"key": "Name", "value": "logs"}, {"key": "Owner"}]}`,
+			afterUnknown:    `{"tags": [{}, {"value": true}]}`,
+			expectedTags:    map[string]string{"Name": "logs", "Owner": UnknownTagValue},
+			expectedUnknown: map[string]bool{"Owner": true},
+		},
+		{
+			name:         "Unknown AWSCC tag key",
+			resourceType: "awscc_s3_bucket",
+			after:        `{"tags": [{"key": "Name", "value": "logs"}, {}]}`,
+			afterUnknown: `{"tags": [{}, {"key": true, "value": true}]}`,
+			expectedTags: map[string]string{"Name": "logs"},
+			expectAll:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var after, afterUnknown map[string]any
+			if err := json.Unmarshal([]byte(tt.after), &after); err != nil {
+				t.Fatalf("Invalid after: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.afterUnknown), &afterUnknown); err != nil {
+				t.Fatalf("Invalid after_unknown: %v", err)
+			}
+
+			tags, _ := planResourceTags(tt.resourceType, after)
+			unknown, all := planUnknownTags(tt.resourceType, after, afterUnknown, tags)
+			if !reflect.DeepEqual(tags, tt.expectedTags) {
+				t.Errorf("Expected tags %v, got %v", tt.expectedTags, tags)
+			}
+			if !reflect.DeepEqual(unknown, tt.expectedUnknown) {
+				t.Errorf("Expected unknown tags %v, got %v", tt.expectedUnknown, unknown)
+			}
+			if all != tt.expectAll {
+				t.Errorf("Expected all tags unknown %v, got %v", tt.expectAll, all)
+			}
+		})
+	}
+}
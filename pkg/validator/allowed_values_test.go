@@ -0,0 +1,156 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateResources_AllowedValues(t *testing.T) {
+	resources := []parser.Resource{
+		{
+			Type:       "aws_s3_bucket",
+			Name:       "allowed",
+			Tags:       map[string]string{"Environment": "prod"},
+			Path:       "main.tf",
+			TagSources: make(map[string]parser.TagSource),
+		},
+		{
+			Type:       "aws_s3_bucket",
+			Name:       "typo",
+			Tags:       map[string]string{"Environment": "prdo"},
+			Path:       "main.tf",
+			TagSources: make(map[string]parser.TagSource),
+		},
+		{
+			Type:       "aws_s3_bucket",
+			Name:       "upper",
+			Tags:       map[string]string{"Environment": "PROD"},
+			Path:       "main.tf",
+			TagSources: make(map[string]parser.TagSource),
+		},
+		{
+			Type:       "aws_s3_bucket",
+			Name:       "unrelated",
+			Tags:       map[string]string{"Environment": "sandbox"},
+			Path:       "main.tf",
+			TagSources: make(map[string]parser.TagSource),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		filename   string
+		content    string
+		violations map[string]AllowedValueViolation
+	}{
+		{
+			name:     "YAML object format",
+			filename: "config.yaml",
+			content: `required_tags:
+  Environment:
+    allowed_values: [dev, test, staging, prod]
+`,
+			violations: map[string]AllowedValueViolation{
+				"typo":      {TagName: "Environment", ActualValue: "prdo", Suggestion: "prod"},
+				"upper":     {TagName: "Environment", ActualValue: "PROD", Suggestion: "prod"},
+				"unrelated": {TagName: "Environment", ActualValue: "sandbox"},
+			},
+		},
+		{
+			name:     "YAML array format",
+			filename: "config.yaml",
+			content: `required_tags:
+  - name: Environment
+    allowed_values: [dev, test, staging, prod]
+    ignore_value_case: true
+`,
+			violations: map[string]AllowedValueViolation{
+				"typo":      {TagName: "Environment", ActualValue: "prdo", Suggestion: "prod"},
+				"unrelated": {TagName: "Environment", ActualValue: "sandbox"},
+			},
+		},
+		{
+			name:     "JSON object format",
+			filename: "config.json",
+			content:  `{"required_tags": {"Environment": {"allowed_values": ["dev", "test", "staging", "prod"], "ignore_value_case": true}}}`,
+			violations: map[string]AllowedValueViolation{
+				"typo":      {TagName: "Environment", ActualValue: "prdo", Suggestion: "prod"},
+				"unrelated": {TagName: "Environment", ActualValue: "sandbox"},
+			},
+		},
+		{
+			name:     "JSON array format",
+			filename: "config.json",
+			content:  `{"required_tags": ["Name", {"name": "Environment", "allowed_values": ["dev", "test", "staging", "prod"]}]}`,
+			violations: map[string]AllowedValueViolation{
+				"typo":      {TagName: "Environment", ActualValue: "prdo", Suggestion: "prod"},
+				"upper":     {TagName: "Environment", ActualValue: "PROD", Suggestion: "prod"},
+				"unrelated": {TagName: "Environment", ActualValue: "sandbox"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+			cfg, err := config.LoadConfig(path)
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			cfg.Required = []string{"Environment"}
+
+			valid, violations, stats, _ := ValidateResources(resources, nil, cfg)
+			if valid {
+				t.Fatal("Expected validation to fail")
+			}
+
+			got := make(map[string]AllowedValueViolation)
+			for _, violation := range violations {
+				if len(violation.PatternViolations) > 0 || len(violation.MissingTags) > 0 {
+					t.Errorf("Expected only allowed value violations for %s, got %+v", violation.ResourceName, violation)
+				}
+				for _, vv := range violation.ValueViolations {
+					if !reflect.DeepEqual(vv.AllowedValues, []string{"dev", "test", "staging", "prod"}) {
+						t.Errorf("Expected the allowed values of Environment, got %v", vv.AllowedValues)
+					}
+					vv.AllowedValues = nil
+					got[violation.ResourceName] = vv
+				}
+			}
+			if !reflect.DeepEqual(got, tt.violations) {
+				t.Errorf("Expected violations %+v, got %+v", tt.violations, got)
+			}
+			if stats.ValueViolationsByTag["Environment"] != len(tt.violations) {
+				t.Errorf("Expected %d Environment value violations, got %d", len(tt.violations), stats.ValueViolationsByTag["Environment"])
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidAllowedValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "Not a list", content: "required_tags:\n  Environment:\n    allowed_values: prod\n"},
+		{name: "Not strings", content: "required_tags:\n  Environment:\n    allowed_values: [[prod]]\n"},
+		{name: "Invalid ignore_value_case", content: "required_tags:\n  Environment:\n    ignore_value_case: sometimes\n"},
+		{name: "Array item without a name", content: "required_tags:\n  - allowed_values: [prod]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadTestConfig(t, tt.content); err == nil {
+				t.Error("Expected an error loading the config")
+			}
+		})
+	}
+}
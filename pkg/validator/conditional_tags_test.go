@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateResources_ConditionalTags(t *testing.T) {
+	cfg, err := loadTestConfig(t, `required_tags:
+  - Name
+
+conditional_tags:
+  - when:
+      Environment: "^prod$"
+    require: [DataClassification, BackupPolicy]
+  - when:
+      Public: "^true$"
+      Environment: "^prod$"
+    require:
+      SecurityReview:
+        pattern: "^SR-[0-9]+$"
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	providers := []parser.ProviderConfig{{
+		Name:        "aws",
+		DefaultTags: map[string]string{"Environment": "prod"},
+	}}
+	prodReason := "Environment 'prod' matches '^prod$'"
+
+	tests := []struct {
+		name               string
+		resource           parser.Resource
+		providers          []parser.ProviderConfig
+		expectedMissing    []string
+		expectedConditions map[string]string
+		expectedPattern    bool
+	}{
+		{
+			name:     "Condition not met",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "dev", Tags: map[string]string{"Name": "dev", "Environment": "dev"}},
+		},
+		{
+			name:               "Condition met by resource tags",
+			resource:           parser.Resource{Type: "aws_s3_bucket", Name: "prod", Tags: map[string]string{"Name": "prod", "Environment": "prod", "BackupPolicy": "daily"}},
+			expectedMissing:    []string{"DataClassification"},
+			expectedConditions: map[string]string{"DataClassification": prodReason, "BackupPolicy": prodReason},
+		},
+		{
+			name:               "Condition met by provider default tags",
+			resource:           parser.Resource{Type: "aws_s3_bucket", Name: "inherited", Tags: map[string]string{"Name": "inherited"}},
+			providers:          providers,
+			expectedMissing:    []string{"BackupPolicy", "DataClassification"},
+			expectedConditions: map[string]string{"DataClassification": prodReason, "BackupPolicy": prodReason},
+		},
+		{
+			name:      "Resource tags override provider default tags",
+			resource:  parser.Resource{Type: "aws_s3_bucket", Name: "override", Tags: map[string]string{"Name": "override", "Environment": "dev"}},
+			providers: providers,
+		},
+		{
+			name:     "Unknown values do not meet conditions",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "unknown", Tags: map[string]string{"Name": "unknown", "Environment": ""}, UnknownTags: map[string]bool{"Environment": true}},
+		},
+		{
+			name: "Every tag of a condition must match",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "public", Tags: map[string]string{
+				"Name": "public", "Environment": "prod", "Public": "true", "DataClassification": "public", "BackupPolicy": "none", "SecurityReview": "pending",
+			}},
+			expectedConditions: map[string]string{
+				"DataClassification": prodReason,
+				"BackupPolicy":       prodReason,
+				"SecurityReview":     "Environment 'prod' matches '^prod$' and Public 'true' matches '^true$'",
+			},
+			expectedPattern: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.resource.TagSources = make(map[string]parser.TagSource)
+			valid, violations, _, _ := ValidateResources([]parser.Resource{tt.resource}, tt.providers, cfg)
+
+			compliant := len(tt.expectedMissing) == 0 && !tt.expectedPattern
+			if valid != compliant {
+				t.Fatalf("Expected valid %v, got %v with %+v", compliant, valid, violations)
+			}
+			if compliant {
+				return
+			}
+
+			violation := violations[0]
+			if !reflect.DeepEqual(violation.MissingTags, tt.expectedMissing) {
+				t.Errorf("Expected missing tags %v, got %v", tt.expectedMissing, violation.MissingTags)
+			}
+			if !reflect.DeepEqual(violation.TagConditions, tt.expectedConditions) {
+				t.Errorf("Expected tag conditions %v, got %v", tt.expectedConditions, violation.TagConditions)
+			}
+			if (len(violation.PatternViolations) > 0) != tt.expectedPattern {
+				t.Errorf("Expected pattern violations %v, got %+v", tt.expectedPattern, violation.PatternViolations)
+			}
+			for tag, condition := range tt.expectedConditions {
+				if got := violation.ConditionFor(tag); got != condition {
+					t.Errorf("Expected tag %s to be required because %s, got %q", tag, condition, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidConditionalTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "No condition", content: "conditional_tags:\n  - require: [Owner]\n"},
+		{name: "No required tags", content: "conditional_tags:\n  - when: {Environment: prod}\n"},
+		{name: "Invalid condition pattern", content: "conditional_tags:\n  - when: {Environment: \"(\"}\n    require: [Owner]\n"},
+		{name: "Invalid required tags", content: "conditional_tags:\n  - when: {Environment: prod}\n    require: Owner\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadTestConfig(t, tt.content); err == nil {
+				t.Error("Expected an error loading the config")
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateDirectory_Diagnostics(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+resource "aws_s3_bucket" "logs" {
+  tags = { Name = "logs" }
+}
+
+module "bucket" {
+  source = "./modules/bucket"
+}
+`,
+		"broken.tf": `
+resource "aws_s3_bucket" "data" {
+  tags = {
+`,
+		"modules/bucket/main.tf": `
+resource "aws_s3_bucket" {
+  tags = { Name = "bucket" }
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		strict      bool
+		expectValid bool
+	}{
+		{name: "Diagnostics reported", expectValid: true},
+		{name: "Strict mode fails", strict: true, expectValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Required: []string{"Name"}, Strict: tt.strict}
+			valid, violations, stats, _ := ValidateDirectory(root, cfg, "ERROR")
+			if valid != tt.expectValid {
+				t.Fatalf("Expected valid %v, got %v (violations: %+v)", tt.expectValid, valid, violations)
+			}
+			if len(violations) != 0 {
+				t.Errorf("Expected no violations, got %+v", violations)
+			}
+
+			expected := map[string]parser.DiagnosticSeverity{
+				filepath.Join(root, "broken.tf"):                    parser.DiagnosticError,
+				filepath.Join(root, "modules", "bucket", "main.tf"): parser.DiagnosticWarning,
+			}
+			if len(stats.Diagnostics) != len(expected) {
+				t.Fatalf("Expected %d diagnostics, got %+v", len(expected), stats.Diagnostics)
+			}
+			for _, diag := range stats.Diagnostics {
+				if severity, exists := expected[diag.Range.Filename]; !exists || severity != diag.Severity {
+					t.Errorf("Unexpected diagnostic %s", diag)
+				}
+				if diag.Range.StartLine == 0 {
+					t.Errorf("Expected diagnostic %s to have a position", diag)
+				}
+			}
+			if stats.UnanalyzedFiles() != 2 {
+				t.Errorf("Expected 2 files not fully analyzed, got %d", stats.UnanalyzedFiles())
+			}
+		})
+	}
+}
+
+func TestGenerateJSONReport_StrictDiagnostics(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "broken.tf")
+	if err := os.WriteFile(path, []byte("resource \"aws_s3_bucket\" \"data\" {\n  tags = {\n"), 0600); err != nil {
+		t.Fatalf("Failed to write broken.tf: %v", err)
+	}
+
+	cfg := &config.Config{Required: []string{"Name"}, Strict: true}
+	valid, violations, stats, _ := ValidateDirectory(root, cfg, "ERROR")
+	if valid {
+		t.Fatal("Expected strict validation to fail")
+	}
+
+	content, err := GenerateJSONReport(valid, violations, stats)
+	if err != nil {
+		t.Fatalf("Failed to generate JSON report: %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Failed to read JSON report: %v", err)
+	}
+	if report.Valid {
+		t.Error("Expected the JSON report to be invalid")
+	}
+	if len(report.Diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic in the JSON report, got %+v", report.Diagnostics)
+	}
+	diag := report.Diagnostics[0]
+	if diag.Severity != string(parser.DiagnosticError) || !strings.HasPrefix(diag.Location, path) || diag.Summary == "" {
+		t.Errorf("Expected an error diagnostic for %s, got %+v", path, diag)
+	}
+}
@@ -0,0 +1,242 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateTerraformPlan_AddressExemptions(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "module.legacy.aws_s3_bucket.logs[0]",
+      "module_address": "module.legacy",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "index": 0,
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}
+    },
+    {
+      "address": "module.legacy.aws_s3_bucket.logs[1]",
+      "module_address": "module.legacy",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "index": 1,
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}
+    },
+    {
+      "address": "module.app.aws_s3_bucket.logs",
+      "module_address": "module.app",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}
+    },
+    {
+      "address": "module.vpc.aws_vpc.this",
+      "module_address": "module.vpc",
+      "type": "aws_vpc",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "main"}}}
+    }
+  ],
+  "configuration": {
+    "root_module": {
+      "module_calls": {
+        "legacy": {"source": "./modules/legacy"},
+        "app": {"source": "./modules/app"},
+        "vpc": {"source": "terraform-aws-modules/vpc/aws", "version": "5.0.0"}
+      }
+    }
+  }
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		exemption    config.ResourceExemption
+		expectExempt []string
+	}{
+		{
+			name:         "Exact address",
+			exemption:    config.ResourceExemption{Address: "module.legacy.aws_s3_bucket.logs[0]"},
+			expectExempt: []string{"module.legacy"},
+		},
+		{
+			name:         "Address glob",
+			exemption:    config.ResourceExemption{Address: "module.legacy.aws_s3_bucket.logs[*]"},
+			expectExempt: []string{"module.legacy", "module.legacy"},
+		},
+		{
+			name:         "Module path and type",
+			exemption:    config.ResourceExemption{ResourceType: "aws_s3_bucket", ModulePath: "module.app"},
+			expectExempt: []string{"module.app"},
+		},
+		{
+			name:         "Module source glob",
+			exemption:    config.ResourceExemption{ModuleSource: "terraform-aws-modules/*"},
+			expectExempt: []string{"module.vpc"},
+		},
+		{
+			name:         "Address regex",
+			exemption:    config.ResourceExemption{Address: `module\.(app|vpc)\..*`, Match: "regex"},
+			expectExempt: []string{"module.app", "module.vpc"},
+		},
+		{
+			name:         "Type and name",
+			exemption:    config.ResourceExemption{ResourceType: "aws_s3_bucket", ResourceName: "logs"},
+			expectExempt: []string{"module.app", "module.legacy", "module.legacy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.exemption.ExemptTags = []string{"Owner"}
+			cfg := &config.Config{Required: []string{"Name", "Owner"}, Exemptions: []config.ResourceExemption{tt.exemption}}
+			_, violations, _, _ := ValidateTerraformPlan(planPath, cfg, "ERROR")
+
+			var exempt []string
+			for _, v := range violations {
+				if v.IsExempt {
+					exempt = append(exempt, v.ModulePath)
+				}
+			}
+			sort.Strings(exempt)
+			if !reflect.DeepEqual(exempt, tt.expectExempt) {
+				t.Errorf("Expected exempt resources in %v, got %v", tt.expectExempt, exempt)
+			}
+		})
+	}
+}
+
+func TestValidateDirectory_AddressExemptions(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+module "legacy" {
+  source = "./modules/bucket"
+}
+
+module "app" {
+  source = "./modules/bucket"
+}
+`,
+		"modules/bucket/main.tf": `
+resource "aws_s3_bucket" "logs" {
+  tags = { Name = "logs" }
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.Config{
+		Required: []string{"Name", "Owner"},
+		Exemptions: []config.ResourceExemption{
+			{Address: "module.legacy.aws_s3_bucket.logs", ExemptTags: []string{"Owner"}},
+		},
+	}
+	_, violations, stats, _ := ValidateDirectory(root, cfg, "ERROR")
+
+	exempt := make(map[string]bool)
+	for _, v := range violations {
+		exempt[v.ModulePath] = v.IsExempt
+	}
+	if !exempt["module.legacy"] || exempt["module.app"] {
+		t.Errorf("Expected only module.legacy to be exempt, got %v", exempt)
+	}
+	if stats.FullyExemptResources != 1 {
+		t.Errorf("Expected 1 fully exempt resource, got %d", stats.FullyExemptResources)
+	}
+}
+
+func TestGenerateJSONReport_PartiallyExemptResources(t *testing.T) {
+	cfg, err := loadTestConfig(t, `required_tags: [Name, Owner]
+
+exemptions:
+  - resource_type: aws_s3_bucket
+    resource_name: partial
+    exempt_tags: [Owner]
+    reason: Owner is being migrated
+  - resource_type: aws_s3_bucket
+    resource_name: full
+    exempt_tags: [Name, Owner]
+    reason: Legacy bucket
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	resources := []parser.Resource{
+		{Type: "aws_s3_bucket", Name: "compliant", Tags: map[string]string{"Name": "a", "Owner": "b"}},
+		{Type: "aws_s3_bucket", Name: "partial", Tags: map[string]string{}},
+		{Type: "aws_s3_bucket", Name: "full", Tags: map[string]string{}},
+		{Type: "aws_s3_bucket", Name: "missing", Tags: map[string]string{"Name": "d"}},
+	}
+	for i := range resources {
+		resources[i].TagSources = make(map[string]parser.TagSource)
+	}
+	valid, violations, stats, _ := ValidateResources(resources, nil, cfg)
+
+	content, err := GenerateJSONReport(valid, violations, stats)
+	if err != nil {
+		t.Fatalf("Failed to generate JSON report: %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Failed to read JSON report: %v", err)
+	}
+
+	expected := JSONSummary{
+		TotalResources:           4,
+		CompliantResources:       1,
+		NonCompliantResources:    2,
+		FullyExemptResources:     1,
+		PartiallyExemptResources: 1,
+		CompliancePercentage:     25,
+	}
+	if report.Summary != expected {
+		t.Errorf("Expected summary %+v, got %+v", expected, report.Summary)
+	}
+}
+
+func TestIsExempt_TypeAndNameSelectors(t *testing.T) {
+	tests := []struct {
+		name      string
+		exemption config.ResourceExemption
+		expected  bool
+	}{
+		{name: "Type and name", exemption: config.ResourceExemption{ResourceType: "aws_s3_bucket", ResourceName: "logs"}, expected: true},
+		{name: "Type and any name", exemption: config.ResourceExemption{ResourceType: "aws_s3_bucket", ResourceName: "*"}, expected: true},
+		{name: "Type without name", exemption: config.ResourceExemption{ResourceType: "aws_s3_bucket"}},
+		{name: "Name without type", exemption: config.ResourceExemption{ResourceName: "logs"}},
+		{name: "Type with module path", exemption: config.ResourceExemption{ResourceType: "aws_s3_bucket", ModulePath: "module.app"}, expected: true},
+	}
+
+	target := config.ExemptionTarget{ResourceType: "aws_s3_bucket", ResourceName: "logs", Address: "module.app.aws_s3_bucket.logs", ModulePath: "module.app"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.exemption.ExemptTags = []string{"Owner"}
+			cfg := &config.Config{Exemptions: []config.ResourceExemption{tt.exemption}}
+			if exempt, _ := cfg.IsExempt(target, "Owner"); exempt != tt.expected {
+				t.Errorf("Expected exempt %v, got %v", tt.expected, exempt)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// ForbiddenTagViolation represents a tag that a resource must not have. Values are not kept,
+// since forbidden values may be secrets.
+type ForbiddenTagViolation struct {
+	TagName string
+	Reason  string
+	ByValue bool               // The tag is forbidden for its value rather than its key
+	Source  string             // "resource" or "provider_default"
+	Range   parser.SourceRange // Range of the tag, when known
+}
+
+// findForbiddenTags checks every tag of a resource, including the provider default tags it
+// inherits, against the forbidden tags of the configuration. Tags the resource is exempt
+// from are not reported.
+func findForbiddenTags(resource parser.Resource, cfg *config.Config, defaultTags map[string]string, unknownDefaultTags map[string]bool) []ForbiddenTagViolation {
+	if len(cfg.ForbiddenTags) == 0 {
+		return nil
+	}
+
+	var violations []ForbiddenTagViolation
+	check := func(key, value string, valueKnown bool, source string, r parser.SourceRange) {
+		forbidden, found := cfg.FindForbiddenTag(key, value, valueKnown)
+		if !found {
+			return
+		}
+		if exempt, _ := cfg.IsExempt(exemptionTarget(resource), key); exempt {
+			return
+		}
+		violations = append(violations, ForbiddenTagViolation{
+			TagName: key,
+			Reason:  forbidden.Description(),
+			ByValue: forbidden.ValuePattern != "",
+			Source:  source,
+			Range:   r,
+		})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(resource.Tags)) {
+		check(key, resource.Tags[key], !resource.UnknownTags[key], "resource", tagRange(resource, key))
+	}
+	for _, key := range slices.Sorted(maps.Keys(defaultTags)) {
+		if _, overridden := resource.Tags[key]; overridden {
+			continue
+		}
+		check(key, defaultTags[key], !unknownDefaultTags[key], "provider_default", parser.SourceRange{})
+	}
+	return violations
+}
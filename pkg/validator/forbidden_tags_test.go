@@ -0,0 +1,138 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateResources_ForbiddenTags(t *testing.T) {
+	cfg, err := loadTestConfig(t, `required_tags:
+  - Name
+
+forbidden_tags:
+  - key_pattern: "^aws:"
+    reason: reserved prefix
+  - key: Env
+  - value_pattern: "(?i)password="
+    reason: secret
+  - key: Owner
+    value_pattern: "@gmail\\.com$"
+
+exemptions:
+  - resource_type: aws_s3_bucket
+    resource_name: exempt
+    exempt_tags: [Env]
+    reason: Migration in progress
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	providers := []parser.ProviderConfig{{
+		Name:        "aws",
+		DefaultTags: map[string]string{"Env": "prod"},
+	}}
+
+	tests := []struct {
+		name      string
+		resource  parser.Resource
+		providers []parser.ProviderConfig
+		expected  []ForbiddenTagViolation
+	}{
+		{
+			name:     "Allowed tags",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "ok", Tags: map[string]string{"Name": "ok", "Owner": "team@example.com"}},
+		},
+		{
+			name:     "Forbidden keys",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "keys", Tags: map[string]string{"Name": "keys", "Env": "prod", "aws:owner": "x"}},
+			expected: []ForbiddenTagViolation{
+				{TagName: "Env", Reason: "key 'Env' is forbidden", Source: "resource"},
+				{TagName: "aws:owner", Reason: "reserved prefix", Source: "resource"},
+			},
+		},
+		{
+			name:     "Forbidden values",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "values", Tags: map[string]string{"Name": "values", "Bootstrap": "PASSWORD=hunter2", "Owner": "jane@gmail.com"}},
+			expected: []ForbiddenTagViolation{
+				{TagName: "Bootstrap", Reason: "secret", ByValue: true, Source: "resource"},
+				{TagName: "Owner", Reason: "value of 'Owner' matches forbidden pattern '@gmail\\.com$'", ByValue: true, Source: "resource"},
+			},
+		},
+		{
+			name:     "Unknown values do not match value patterns",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "unknown", Tags: map[string]string{"Name": "unknown", "Bootstrap": ""}, UnknownTags: map[string]bool{"Bootstrap": true}},
+		},
+		{
+			name:      "Provider default tags",
+			resource:  parser.Resource{Type: "aws_s3_bucket", Name: "defaults", Tags: map[string]string{"Name": "defaults"}},
+			providers: providers,
+			expected: []ForbiddenTagViolation{
+				{TagName: "Env", Reason: "key 'Env' is forbidden", Source: "provider_default"},
+			},
+		},
+		{
+			name:     "Exempt tags",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "exempt", Tags: map[string]string{"Name": "exempt", "Env": "prod"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.resource.TagSources = make(map[string]parser.TagSource)
+			valid, violations, stats, _ := ValidateResources([]parser.Resource{tt.resource}, tt.providers, cfg)
+			if valid != (len(tt.expected) == 0) {
+				t.Fatalf("Expected valid %v, got %v with %+v", len(tt.expected) == 0, valid, violations)
+			}
+			if len(tt.expected) == 0 {
+				return
+			}
+
+			if len(violations) != 1 {
+				t.Fatalf("Expected 1 violation, got %+v", violations)
+			}
+			if !reflect.DeepEqual(violations[0].ForbiddenTags, tt.expected) {
+				t.Errorf("Expected forbidden tags %+v, got %+v", tt.expected, violations[0].ForbiddenTags)
+			}
+			for _, expected := range tt.expected {
+				if stats.ForbiddenTagsByTag[expected.TagName] != 1 {
+					t.Errorf("Expected forbidden tag %s to be counted once, got %d", expected.TagName, stats.ForbiddenTagsByTag[expected.TagName])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidForbiddenTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "No selector", content: "forbidden_tags:\n  - reason: nothing\n"},
+		{name: "Key and key pattern", content: "forbidden_tags:\n  - key: Env\n    key_pattern: \"^Env$\"\n"},
+		{name: "Invalid key pattern", content: "forbidden_tags:\n  - key_pattern: \"[\"\n"},
+		{name: "Invalid value pattern", content: "forbidden_tags:\n  - value_pattern: \"(\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadTestConfig(t, tt.content); err == nil {
+				t.Error("Expected an error loading the config")
+			}
+		})
+	}
+}
+
+func TestFindForbiddenTag_IgnoreTagCase(t *testing.T) {
+	cfg := &config.Config{ForbiddenTags: []config.ForbiddenTag{{Key: "Env"}}, IgnoreTagCase: true}
+	if _, found := cfg.FindForbiddenTag("env", "prod", true); !found {
+		t.Error("Expected 'env' to match forbidden key 'Env' when tag case is ignored")
+	}
+	cfg.IgnoreTagCase = false
+	if _, found := cfg.FindForbiddenTag("env", "prod", true); found {
+		t.Error("Expected 'env' not to match forbidden key 'Env'")
+	}
+}
@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+)
+
+func TestValidateTerraformPlan_Instances(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs[0]",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "index": 0,
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs", "Owner": "platform"}}}
+    },
+    {
+      "address": "aws_s3_bucket.logs[1]",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "index": 1,
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}
+    },
+    {
+      "address": "aws_s3_bucket.logs[2]",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "index": 2,
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {}}}
+    },
+    {
+      "address": "module.app[\"api\"].aws_sqs_queue.this",
+      "module_address": "module.app[\"api\"]",
+      "type": "aws_sqs_queue",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "api"}}}
+    },
+    {
+      "address": "module.app[\"worker\"].aws_sqs_queue.this",
+      "module_address": "module.app[\"worker\"]",
+      "type": "aws_sqs_queue",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "worker"}}}
+    },
+    {
+      "address": "aws_s3_bucket.data",
+      "type": "aws_s3_bucket",
+      "name": "data",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "data"}}}
+    }
+  ],
+  "configuration": {
+    "root_module": {
+      "module_calls": {
+        "app": {"source": "./modules/app"}
+      }
+    }
+  }
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	cfg := &config.Config{Required: []string{"Name", "Owner"}}
+	_, violations, stats, _ := ValidateTerraformPlan(planPath, cfg, "ERROR")
+
+	if stats.TotalResources != 6 || stats.CompliantResources != 1 {
+		t.Errorf("Expected 1/6 compliant resources, got %d/%d", stats.CompliantResources, stats.TotalResources)
+	}
+
+	missing := make(map[string][]string)
+	for _, v := range violations {
+		missing[v.Address] = v.MissingTags
+	}
+	expected := map[string][]string{
+		"aws_s3_bucket.logs[1]":                   {"Owner"},
+		"aws_s3_bucket.logs[2]":                   {"Name", "Owner"},
+		`module.app["api"].aws_sqs_queue.this`:    {"Owner"},
+		`module.app["worker"].aws_sqs_queue.this`: {"Owner"},
+		"aws_s3_bucket.data":                      {"Owner"},
+	}
+	if !reflect.DeepEqual(missing, expected) {
+		t.Errorf("Expected violations %v, got %v", expected, missing)
+	}
+
+	groups := GroupViolations(violations)
+	type group struct {
+		configAddress string
+		instances     []string
+		hasInstances  bool
+	}
+	var got []group
+	for _, g := range groups {
+		got = append(got, group{g.ConfigAddress, g.InstanceAddresses(), g.HasInstances()})
+	}
+	want := []group{
+		{"aws_s3_bucket.logs", []string{"aws_s3_bucket.logs[1]", "aws_s3_bucket.logs[2]"}, true},
+		{"aws_s3_bucket.data", []string{"aws_s3_bucket.data"}, false},
+		{"module.app.aws_sqs_queue.this", []string{`module.app["api"].aws_sqs_queue.this`, `module.app["worker"].aws_sqs_queue.this`}, true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected groups %+v, got %+v", want, got)
+	}
+
+	content, err := GenerateJSONReport(false, violations, stats)
+	if err != nil {
+		t.Fatalf("Failed to generate JSON report: %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Failed to read JSON report: %v", err)
+	}
+	instances := make(map[string][2]string)
+	for _, v := range report.Violations {
+		instances[v.Address] = [2]string{v.ConfigAddress, v.InstanceKey}
+	}
+	expectedInstances := map[string][2]string{
+		"aws_s3_bucket.logs[1]":                   {"aws_s3_bucket.logs", "[1]"},
+		"aws_s3_bucket.logs[2]":                   {"aws_s3_bucket.logs", "[2]"},
+		`module.app["api"].aws_sqs_queue.this`:    {"module.app.aws_sqs_queue.this", ""},
+		`module.app["worker"].aws_sqs_queue.this`: {"module.app.aws_sqs_queue.this", ""},
+		"aws_s3_bucket.data":                      {"aws_s3_bucket.data", ""},
+	}
+	if !reflect.DeepEqual(instances, expectedInstances) {
+		t.Errorf("Expected configuration addresses and instance keys %v, got %v", expectedInstances, instances)
+	}
+}
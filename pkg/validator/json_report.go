@@ -0,0 +1,285 @@
+package validator
+
+import (
+	"encoding/json"
+
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// JSONReport is the machine-readable report of a validation run
+type JSONReport struct {
+	Valid      bool            `json:"valid"`
+	Summary    JSONSummary     `json:"summary"`
+	Plans      []JSONPlan      `json:"plans,omitempty"`
+	Violations []JSONViolation `json:"violations"`
+	// Diagnostics lists the parts of Terraform files that could not be analyzed
+	Diagnostics []JSONDiagnostic `json:"diagnostics,omitempty"`
+	// IgnoredRequiredTags lists required tags covered by a provider's ignore_tags
+	IgnoredRequiredTags []JSONIgnoredTag `json:"ignored_required_tags,omitempty"`
+	// UnknownTags lists the resources with required tags only known after apply
+	UnknownTags []JSONUnknownTags `json:"unknown_tags,omitempty"`
+}
+
+// JSONDiagnostic is a part of a Terraform file that could not be analyzed
+type JSONDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+	Location string `json:"location"`
+}
+
+// JSONIgnoredTag is a required tag covered by the ignore_tags of a provider configuration
+type JSONIgnoredTag struct {
+	Tag      string `json:"tag"`
+	Provider string `json:"provider"`
+	Path     string `json:"path"`
+}
+
+// JSONUnknownTags holds the required tags of a resource that are only known after apply
+type JSONUnknownTags struct {
+	Plan         string   `json:"plan,omitempty"`
+	ResourceType string   `json:"resource_type"`
+	ResourceName string   `json:"resource_name"`
+	ResourcePath string   `json:"resource_path"`
+	ModulePath   string   `json:"module_path,omitempty"`
+	InstanceKey  string   `json:"instance_key,omitempty"`
+	Location     string   `json:"location,omitempty"`
+	Tags         []string `json:"tags"`
+}
+
+// JSONSummary holds the compliance statistics of a validation run or of a single plan
+type JSONSummary struct {
+	TotalResources           int     `json:"total_resources"`
+	CompliantResources       int     `json:"compliant_resources"`
+	NonCompliantResources    int     `json:"non_compliant_resources"`
+	FullyExemptResources     int     `json:"fully_exempt_resources"`
+	PartiallyExemptResources int     `json:"partially_exempt_resources"`
+	ExcludedResources        int     `json:"excluded_resources"`
+	CompliancePercentage     float64 `json:"compliance_percentage"`
+}
+
+// JSONPlan holds the result of one plan of a batch
+type JSONPlan struct {
+	Name    string      `json:"name"`
+	Path    string      `json:"path"`
+	Valid   bool        `json:"valid"`
+	Summary JSONSummary `json:"summary"`
+}
+
+// JSONViolation is a resource that does not comply with the required tags
+type JSONViolation struct {
+	Plan                string                    `json:"plan,omitempty"`
+	Address             string                    `json:"address,omitempty"`
+	ConfigAddress       string                    `json:"config_address,omitempty"` // Address without instance keys, shared by the instances of a resource
+	ResourceType        string                    `json:"resource_type"`
+	ResourceName        string                    `json:"resource_name"`
+	ResourcePath        string                    `json:"resource_path"`
+	ModulePath          string                    `json:"module_path,omitempty"`
+	ModuleSource        string                    `json:"module_source,omitempty"`
+	InstanceKey         string                    `json:"instance_key,omitempty"`
+	Location            string                    `json:"location,omitempty"`
+	MissingTags         []string                  `json:"missing_tags,omitempty"`
+	PatternViolations   []JSONPatternViolation    `json:"pattern_violations,omitempty"`
+	ValueViolations     []JSONValueViolation      `json:"allowed_value_violations,omitempty"`
+	ForbiddenTags       []JSONForbiddenTag        `json:"forbidden_tags,omitempty"`
+	ProviderConstraints []JSONConstraintViolation `json:"provider_constraint_violations,omitempty"`
+	IsExempt            bool                      `json:"exempt,omitempty"`
+	ExemptReason        string                    `json:"exempt_reason,omitempty"`
+	UnknownTags         []string                  `json:"unknown_tags,omitempty"`
+	RemovedTags         []string                  `json:"removed_tags,omitempty"`
+	ImmutableTagChanges []string                  `json:"immutable_tag_changes,omitempty"`
+	NotPropagatedTags   []string                  `json:"not_propagated_tags,omitempty"`
+	LocationViolations  []JSONLocationViolation   `json:"tag_location_violations,omitempty"`
+	TagErrors           []string                  `json:"tag_errors,omitempty"`
+	Rules               map[string]string         `json:"rules,omitempty"`      // Rule requiring each tag, for tags required by a rule
+	Conditions          map[string]string         `json:"conditions,omitempty"` // Condition requiring each tag, for tags required by a conditional requirement
+}
+
+// JSONPatternViolation is a tag value that does not match its required pattern
+type JSONPatternViolation struct {
+	Tag     string `json:"tag"`
+	Value   string `json:"value"`
+	Pattern string `json:"pattern"`
+	Message string `json:"message"`
+}
+
+// JSONValueViolation is a tag value that is not one of the tag's allowed values
+type JSONValueViolation struct {
+	Tag           string   `json:"tag"`
+	Value         string   `json:"value"`
+	AllowedValues []string `json:"allowed_values"`
+	Suggestion    string   `json:"suggestion,omitempty"`
+}
+
+// JSONForbiddenTag is a tag that a resource must not have
+type JSONForbiddenTag struct {
+	Tag    string `json:"tag"`
+	Reason string `json:"reason"`
+	Source string `json:"source"`
+}
+
+// JSONConstraintViolation is a tag that the provider of a resource would reject
+type JSONConstraintViolation struct {
+	Provider string `json:"provider"`
+	Tag      string `json:"tag,omitempty"`
+	Message  string `json:"message"`
+	Source   string `json:"source"`
+}
+
+// JSONLocationViolation holds the violations at a secondary tag location of a resource
+type JSONLocationViolation struct {
+	Location          string                 `json:"location"`
+	MissingTags       []string               `json:"missing_tags,omitempty"`
+	PatternViolations []JSONPatternViolation `json:"pattern_violations,omitempty"`
+	ValueViolations   []JSONValueViolation   `json:"allowed_value_violations,omitempty"`
+}
+
+// GenerateJSONReport generates a machine-readable report of a validation run, with a summary
+// per plan when a batch of plans is validated
+func GenerateJSONReport(valid bool, violations []TagViolation, stats TagComplianceStats) ([]byte, error) {
+	report := JSONReport{
+		Valid: valid,
+		Summary: jsonSummary(stats.TotalResources, stats.CompliantResources, stats.FullyExemptResources,
+			stats.PartiallyExemptResources, stats.ExcludedResourcesCount),
+		Violations: make([]JSONViolation, 0, len(violations)),
+	}
+
+	for _, plan := range stats.Plans {
+		report.Plans = append(report.Plans, JSONPlan{
+			Name:  plan.Name,
+			Path:  plan.Path,
+			Valid: plan.Valid,
+			Summary: jsonSummary(plan.TotalResources, plan.CompliantResources, plan.FullyExemptResources,
+				plan.PartiallyExemptResources, plan.ExcludedResourcesCount),
+		})
+	}
+
+	for _, v := range violations {
+		violation := JSONViolation{
+			Plan:              v.Plan,
+			Address:           v.Address,
+			ConfigAddress:     parser.ConfigAddress(v.Address),
+			ResourceType:      v.ResourceType,
+			ResourceName:      v.ResourceName,
+			ResourcePath:      v.ResourcePath,
+			ModulePath:        v.ModulePath,
+			ModuleSource:      v.ModuleSource,
+			InstanceKey:       v.InstanceKey,
+			MissingTags:       v.MissingTags,
+			PatternViolations: jsonPatternViolations(v.PatternViolations),
+			ValueViolations:   jsonValueViolations(v.ValueViolations),
+			IsExempt:          v.IsExempt,
+			ExemptReason:      v.ExemptReason,
+			UnknownTags:       v.UnknownTags,
+			NotPropagatedTags: v.NotPropagatedTags,
+			TagErrors:         v.TagErrors,
+			Rules:             v.TagRules,
+			Conditions:        v.TagConditions,
+		}
+		if !v.Range.IsZero() {
+			violation.Location = v.Range.String()
+		}
+		for _, change := range v.RemovedTags {
+			violation.RemovedTags = append(violation.RemovedTags, change.TagName)
+		}
+		for _, change := range v.ImmutableTagChanges {
+			violation.ImmutableTagChanges = append(violation.ImmutableTagChanges, change.TagName)
+		}
+		for _, fv := range v.ForbiddenTags {
+			violation.ForbiddenTags = append(violation.ForbiddenTags, JSONForbiddenTag{Tag: fv.TagName, Reason: fv.Reason, Source: fv.Source})
+		}
+		for _, cv := range v.ConstraintViolations {
+			violation.ProviderConstraints = append(violation.ProviderConstraints, JSONConstraintViolation{Provider: cv.Provider, Tag: cv.TagName, Message: cv.Message, Source: cv.Source})
+		}
+		for _, lv := range v.LocationViolations {
+			violation.LocationViolations = append(violation.LocationViolations, JSONLocationViolation{
+				Location:          lv.Location,
+				MissingTags:       lv.MissingTags,
+				PatternViolations: jsonPatternViolations(lv.PatternViolations),
+				ValueViolations:   jsonValueViolations(lv.ValueViolations),
+			})
+		}
+		report.Violations = append(report.Violations, violation)
+	}
+
+	for _, diag := range stats.Diagnostics {
+		location := diag.Range.Filename
+		if diag.Range.StartLine > 0 {
+			location = diag.Range.String()
+		}
+		report.Diagnostics = append(report.Diagnostics, JSONDiagnostic{
+			Severity: string(diag.Severity),
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+			Location: location,
+		})
+	}
+	for _, ignored := range stats.IgnoredRequiredTags {
+		report.IgnoredRequiredTags = append(report.IgnoredRequiredTags, JSONIgnoredTag{
+			Tag:      ignored.Tag,
+			Provider: ignored.Provider,
+			Path:     ignored.Path,
+		})
+	}
+	for _, resource := range stats.UnknownTags {
+		unknown := JSONUnknownTags{
+			Plan:         resource.Plan,
+			ResourceType: resource.ResourceType,
+			ResourceName: resource.ResourceName,
+			ResourcePath: resource.ResourcePath,
+			ModulePath:   resource.ModulePath,
+			InstanceKey:  resource.InstanceKey,
+			Tags:         resource.Tags,
+		}
+		if !resource.Range.IsZero() {
+			unknown.Location = resource.Range.String()
+		}
+		report.UnknownTags = append(report.UnknownTags, unknown)
+	}
+
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// jsonSummary computes the summary of a set of resource counts
+func jsonSummary(total, compliant, fullyExempt, partiallyExempt, excluded int) JSONSummary {
+	summary := JSONSummary{
+		TotalResources:           total,
+		CompliantResources:       compliant,
+		NonCompliantResources:    total - compliant - fullyExempt,
+		FullyExemptResources:     fullyExempt,
+		PartiallyExemptResources: partiallyExempt,
+		ExcludedResources:        excluded,
+	}
+	if total > 0 {
+		summary.CompliancePercentage = float64(compliant) / float64(total) * 100
+	}
+	return summary
+}
+
+// jsonPatternViolations converts pattern violations for the JSON report
+func jsonPatternViolations(violations []PatternViolation) []JSONPatternViolation {
+	var converted []JSONPatternViolation
+	for _, pv := range violations {
+		converted = append(converted, JSONPatternViolation{
+			Tag:     pv.TagName,
+			Value:   pv.ActualValue,
+			Pattern: pv.ExpectedPattern,
+			Message: pv.ErrorMessage,
+		})
+	}
+	return converted
+}
+
+// jsonValueViolations converts allowed value violations for the JSON report
+func jsonValueViolations(violations []AllowedValueViolation) []JSONValueViolation {
+	var converted []JSONValueViolation
+	for _, vv := range violations {
+		converted = append(converted, JSONValueViolation{
+			Tag:           vv.TagName,
+			Value:         vv.ActualValue,
+			AllowedValues: vv.AllowedValues,
+			Suggestion:    vv.Suggestion,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// pendingModule is a local module call waiting to be validated along with the provider
+// configurations of its caller
+type pendingModule struct {
+	module          parser.LocalModule
+	callerProviders []parser.ProviderConfig
+}
+
+// validateLocalModules validates the resources of local modules called from a directory, following
+// the module calls they make in turn. Violations carry the module call chain in ModulePath.
+func validateLocalModules(modules []parser.LocalModule, providers []parser.ProviderConfig, cfg *config.Config) (bool, []TagViolation, TagComplianceStats, []parser.Resource) {
+	valid := true
+	var allViolations []TagViolation
+	var allResources []parser.Resource
+	stats := newTagComplianceStats()
+
+	inheritance := parser.NewModuleTagInheritance()
+	var queue []pendingModule
+	for _, module := range modules {
+		queue = append(queue, pendingModule{module: module, callerProviders: providers})
+	}
+
+	for len(queue) > 0 {
+		pending := queue[0]
+		queue = queue[1:]
+		module := pending.module
+
+		logging.Info("Following %s (source %s)", module.ModulePath, module.Source)
+		inheritance.AddModuleTags(module.ModulePath, module.Tags)
+
+		moduleResources, ownProviders, calls, diagnostics, err := parser.LoadLocalModule(module)
+		if err != nil {
+			valid = false
+			allViolations = append(allViolations, TagViolation{
+				ResourceType: "error",
+				ResourceName: "error",
+				ResourcePath: module.Dir,
+				ModulePath:   module.ModulePath,
+				MissingTags:  []string{fmt.Sprintf("Error loading %s: %s", module.ModulePath, err)},
+			})
+			continue
+		}
+
+		stats.Diagnostics = appendDiagnostics(stats.Diagnostics, diagnostics)
+
+		resources := make([]parser.Resource, 0, len(moduleResources))
+		for i := range moduleResources {
+			inheritance.InheritTags(&moduleResources[i])
+			resources = append(resources, moduleResources[i].Resource)
+		}
+
+		effectiveProviders := moduleProviders(module, ownProviders, pending.callerProviders)
+		logging.Info("Found %d taggable resources in %s", len(resources), module.ModulePath)
+
+		moduleValid, violations, moduleStats, _ := ValidateResources(resources, effectiveProviders, cfg)
+		if !moduleValid {
+			valid = false
+		}
+		for i := range violations {
+			violations[i].ModulePath = module.ModulePath
+		}
+		for i := range moduleStats.UnknownTags {
+			moduleStats.UnknownTags[i].ModulePath = module.ModulePath
+		}
+		allViolations = append(allViolations, violations...)
+		allResources = append(allResources, resources...)
+		mergeStats(&stats, moduleStats)
+
+		// Modules called from here receive their providers from this module
+		for _, call := range calls {
+			queue = append(queue, pendingModule{module: call, callerProviders: effectiveProviders})
+		}
+	}
+
+	return valid, allViolations, stats, allResources
+}
+
+// moduleProviders returns the provider configurations that apply to the resources of a module.
+// A module receives the configurations listed in its call's providers argument, or all default
+// configurations of its caller when there is none, the way Terraform passes provider
+// configurations down to child modules. Configurations declared by the module itself take
+// precedence.
+func moduleProviders(module parser.LocalModule, own, caller []parser.ProviderConfig) []parser.ProviderConfig {
+	var providers []parser.ProviderConfig
+	if module.Providers == nil {
+		for _, provider := range caller {
+			if provider.Alias == "" {
+				providers = append(providers, provider)
+			}
+		}
+	} else {
+		callerByKey := make(map[string]parser.ProviderConfig)
+		for _, provider := range caller {
+			callerByKey[provider.Key()] = provider
+		}
+		for child, parent := range module.Providers {
+			provider, exists := callerByKey[parent]
+			if !exists {
+				continue
+			}
+			provider.Name, provider.Alias, _ = strings.Cut(child, ".")
+			providers = append(providers, provider)
+		}
+	}
+
+	return append(providers, own...)
+}
@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+)
+
+func TestValidateDirectory_FollowsLocalModules(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+locals {
+  tags = {
+    Environment = "dev"
+    Owner       = "platform"
+  }
+}
+
+module "network" {
+  source = "./modules/network"
+  name   = "main"
+  tags   = local.tags
+}
+
+module "registry" {
+  source = "terraform-aws-modules/vpc/aws"
+  tags   = local.tags
+}
+`,
+		"modules/network/main.tf": `
+variable "name" {}
+
+variable "tags" {
+  default = {}
+}
+
+resource "aws_vpc" "this" {
+  tags = merge(var.tags, { Name = var.name })
+}
+
+resource "aws_internet_gateway" "this" {
+  tags = { Name = var.name }
+}
+
+module "subnets" {
+  source = "../subnets"
+  tags   = merge(var.tags, { Name = "subnets" })
+}
+`,
+		"modules/subnets/main.tf": `
+variable "tags" {
+  default = {}
+}
+
+resource "aws_subnet" "private" {
+  tags = var.tags
+}
+
+resource "aws_subnet" "public" {
+  tags = { Name = "public" }
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.Config{Required: []string{"Name", "Environment", "Owner"}}
+	valid, violations, stats, _ := ValidateDirectory(root, cfg, "ERROR")
+	if valid {
+		t.Fatal("Expected validation to fail")
+	}
+
+	var got []string
+	for _, violation := range violations {
+		got = append(got, violation.ModulePath+" "+violation.ResourceType+"."+violation.ResourceName)
+	}
+	sort.Strings(got)
+	expected := []string{
+		" module.network",
+		" module.registry",
+		"module.network aws_internet_gateway.this",
+		"module.network.module.subnets aws_subnet.public",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected violations %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected violation %q, got %q", expected[i], got[i])
+		}
+	}
+
+	// module.network, module.registry, two resources in each local module and module.subnets
+	if stats.TotalResources != 7 {
+		t.Errorf("Expected 7 resources, got %d", stats.TotalResources)
+	}
+	if stats.CompliantResources != 3 {
+		t.Errorf("Expected 3 compliant resources, got %d", stats.CompliantResources)
+	}
+}
+
+func TestValidateDirectoryRecursive_SkipsCalledModules(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"live/main.tf": `
+module "network" {
+  source = "../modules/network"
+  tags   = { Name = "network" }
+}
+`,
+		"modules/network/main.tf": `
+variable "tags" {
+  default = {}
+}
+
+resource "aws_vpc" "this" {
+  tags = var.tags
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.Config{Required: []string{"Name"}}
+	valid, violations, stats, _ := ValidateDirectoryRecursive(root, cfg, "ERROR")
+	if !valid {
+		t.Fatalf("Expected validation to pass, got violations: %v", violations)
+	}
+	if len(stats.Directories) != 1 || stats.Directories[0].Dir != filepath.Join(root, "live") {
+		t.Errorf("Expected only the live directory to be validated, got %v", stats.Directories)
+	}
+	if stats.TotalResources != 2 {
+		t.Errorf("Expected 2 resources, got %d", stats.TotalResources)
+	}
+}
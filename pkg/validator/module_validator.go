@@ -1,8 +1,6 @@
 package validator
 
 import (
-	"strings"
-
 	"github.com/terratags/terratags/pkg/config"
 	"github.com/terratags/terratags/pkg/parser"
 )
@@ -17,17 +15,45 @@ type ValidationSummary struct {
 // ModuleResourceValidation represents validation result for a module resource
 type ModuleResourceValidation struct {
 	ResourceValidation
-	ModulePath   string
-	ModuleName   string
-	ModuleSource string
-	IsExternal   bool
+	ModulePath string
+	ModuleName string
+	IsExternal bool
+}
+
+// ModuleStats represents tag compliance statistics for the resources of one module, across
+// the instances of its module call
+type ModuleStats struct {
+	ModulePath               string // Module call chain without instance keys, e.g., "module.platform.module.vpc"
+	ModuleSource             string
+	TotalResources           int
+	CompliantResources       int
+	FullyExemptResources     int
+	PartiallyExemptResources int
+}
+
+// moduleStats returns the statistics of the module with the given module path and source,
+// adding them if needed, or nil for root module resources
+func (s *TagComplianceStats) moduleStats(modulePath, moduleSource string) *ModuleStats {
+	if modulePath == "" {
+		return nil
+	}
+	modulePath = parser.ConfigAddress(modulePath)
+	for i := range s.Modules {
+		if s.Modules[i].ModulePath == modulePath && s.Modules[i].ModuleSource == moduleSource {
+			return &s.Modules[i]
+		}
+	}
+	s.Modules = append(s.Modules, ModuleStats{ModulePath: modulePath, ModuleSource: moduleSource})
+	return &s.Modules[len(s.Modules)-1]
 }
 
 // ValidationResultWithModules includes both direct and module resource validation
 type ValidationResultWithModules struct {
 	DirectResources []ResourceValidation
 	ModuleResources []ModuleResourceValidation
-	Summary         ValidationSummaryWithModules
+	// ExcludedResources holds the type of each excluded AWSCC resource, which is not validated
+	ExcludedResources []string
+	Summary           ValidationSummaryWithModules
 }
 
 // ValidationSummaryWithModules provides summary including module resources
@@ -40,21 +66,33 @@ type ValidationSummaryWithModules struct {
 	CompliancePercent float64
 }
 
-// ValidateWithModules validates both direct and module resources
+// ValidateWithModules validates both direct and module resources. Their tags are expected to
+// include the provider default tags, as plans and states record them.
 func ValidateWithModules(directResources []parser.Resource, moduleResources []parser.ModuleResource,
-	cfg *config.Config, providerTags map[string]map[string]string) ValidationResultWithModules {
+	cfg *config.Config) ValidationResultWithModules {
 
 	var result ValidationResultWithModules
 
-	// Validate direct resources (existing logic)
+	// Inline terratags:ignore comments are handled as exemptions
+	cfg = withInlineSuppressions(cfg, moduleResourcesWithDirect(directResources, moduleResources))
+
+	// Validate direct resources
 	for _, resource := range directResources {
-		validation := validateResource(resource, cfg, providerTags)
+		if parser.AwsccExcludedResources[resource.Type] {
+			result.ExcludedResources = append(result.ExcludedResources, resource.Type)
+			continue
+		}
+		validation := validateResource(resource, cfg, nil, nil)
 		result.DirectResources = append(result.DirectResources, validation)
 	}
 
 	// Validate module resources
 	for _, moduleResource := range moduleResources {
-		validation := validateModuleResource(moduleResource, cfg, providerTags)
+		if parser.AwsccExcludedResources[moduleResource.Type] {
+			result.ExcludedResources = append(result.ExcludedResources, moduleResource.Type)
+			continue
+		}
+		validation := validateModuleResource(moduleResource, cfg)
 		result.ModuleResources = append(result.ModuleResources, validation)
 	}
 
@@ -65,17 +103,14 @@ func ValidateWithModules(directResources []parser.Resource, moduleResources []pa
 }
 
 // validateModuleResource validates a single module resource
-func validateModuleResource(moduleResource parser.ModuleResource, cfg *config.Config,
-	providerTags map[string]map[string]string) ModuleResourceValidation {
-
-	baseValidation := validateResource(moduleResource.Resource, cfg, providerTags)
+func validateModuleResource(moduleResource parser.ModuleResource, cfg *config.Config) ModuleResourceValidation {
+	baseValidation := validateResource(moduleResource.Resource, cfg, nil, nil)
 
 	return ModuleResourceValidation{
 		ResourceValidation: baseValidation,
-		ModulePath:         moduleResource.ModulePath,
+		ModulePath:         moduleResource.Resource.ModulePath,
 		ModuleName:         moduleResource.ModuleName,
-		ModuleSource:       moduleResource.ModuleSource,
-		IsExternal:         isExternalModule(moduleResource.ModuleSource),
+		IsExternal:         parser.IsExternalModule(moduleResource.Resource.ModuleSource),
 	}
 }
 
@@ -128,11 +163,3 @@ func calculateSummaryWithModules(directResults []ResourceValidation, moduleResul
 		CompliancePercent: compliancePercent,
 	}
 }
-
-// isExternalModule checks if a module is external (not local)
-func isExternalModule(source string) bool {
-	// External modules typically start with registry paths or git URLs
-	return !strings.HasPrefix(source, "./") &&
-		!strings.HasPrefix(source, "../") &&
-		!strings.HasPrefix(source, "/")
-}
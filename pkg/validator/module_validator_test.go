@@ -0,0 +1,228 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateWithModules_DirectoryParity(t *testing.T) {
+	newResource := func(resourceType, name string, tags map[string]string) parser.Resource {
+		return parser.Resource{
+			Type:       resourceType,
+			Name:       name,
+			Tags:       tags,
+			Path:       "main.tf",
+			TagSources: make(map[string]parser.TagSource),
+		}
+	}
+	resources := []parser.Resource{
+		newResource("aws_s3_bucket", "compliant", map[string]string{"name": "logs", "owner": "platform"}),
+		newResource("aws_s3_bucket", "missing", map[string]string{"Name": "data"}),
+		newResource("aws_s3_bucket", "pattern", map[string]string{"Name": "web", "Owner": "Platform Team"}),
+		newResource("aws_instance", "exempt", map[string]string{"Name": "web"}),
+		newResource("awscc_apigatewayv2_api", "excluded", nil),
+	}
+
+	cfg, err := loadTestConfig(t, `required_tags:
+  Name: {}
+  Owner:
+    pattern: "^[a-z]+$"
+exemptions:
+  - resource_type: aws_instance
+    resource_name: exempt
+    exempt_tags: [Owner]
+    reason: Managed by autoscaling
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.IgnoreTagCase = true
+
+	dirValid, dirViolations, dirStats, _ := ValidateResources(resources, nil, cfg)
+	planValid, planViolations, planStats := resultViolations(ValidateWithModules(resources, nil, cfg), cfg)
+
+	if dirValid || planValid {
+		t.Errorf("Expected both modes to fail, got directory %v and plan %v", dirValid, planValid)
+	}
+	if !reflect.DeepEqual(dirViolations, planViolations) {
+		t.Errorf("Expected identical violations\ndirectory: %+v\nplan:      %+v", dirViolations, planViolations)
+	}
+	if !reflect.DeepEqual(dirStats, planStats) {
+		t.Errorf("Expected identical statistics\ndirectory: %+v\nplan:      %+v", dirStats, planStats)
+	}
+
+	expected := TagComplianceStats{
+		TotalResources:         4,
+		CompliantResources:     1,
+		FullyExemptResources:   1,
+		ExcludedAWSCCResources: []string{"awscc_apigatewayv2_api"},
+		ExcludedResourcesCount: 1,
+		ViolationsByTag:        map[string]int{"Owner": 1},
+		PatternViolationsByTag: map[string]int{"Owner": 1},
+	}
+	if planStats.TotalResources != expected.TotalResources || planStats.CompliantResources != expected.CompliantResources ||
+		planStats.FullyExemptResources != expected.FullyExemptResources || planStats.ExcludedResourcesCount != expected.ExcludedResourcesCount ||
+		!reflect.DeepEqual(planStats.ExcludedAWSCCResources, expected.ExcludedAWSCCResources) ||
+		!reflect.DeepEqual(planStats.ViolationsByTag, expected.ViolationsByTag) ||
+		!reflect.DeepEqual(planStats.PatternViolationsByTag, expected.PatternViolationsByTag) {
+		t.Errorf("Expected statistics %+v, got %+v", expected, planStats)
+	}
+
+	for _, v := range planViolations {
+		if v.ResourceName == "exempt" && (!v.IsExempt || v.ExemptReason != "Managed by autoscaling") {
+			t.Errorf("Expected the exempt resource to be reported as exempt, got %+v", v)
+		}
+	}
+}
+
+func TestValidateWithModules_ModuleSuppressions(t *testing.T) {
+	moduleResources := []parser.ModuleResource{{
+		Resource: parser.Resource{
+			Type:         "aws_s3_bucket",
+			Name:         "this",
+			Path:         "modules/bucket/main.tf",
+			Tags:         map[string]string{"Name": "logs"},
+			TagSources:   make(map[string]parser.TagSource),
+			ModulePath:   "module.logs",
+			Suppressions: []parser.Suppression{{Tags: []string{"Owner"}, Path: "modules/bucket/main.tf", Line: 1}},
+		},
+		ModuleName: "logs",
+	}}
+
+	cfg := &config.Config{Required: []string{"Name", "Owner"}}
+	result := ValidateWithModules(nil, moduleResources, cfg)
+	if len(result.ModuleResources) != 1 {
+		t.Fatalf("Expected 1 module resource, got %d", len(result.ModuleResources))
+	}
+	if validation := result.ModuleResources[0]; !validation.IsCompliant || !validation.IsExempt {
+		t.Errorf("Expected the suppressed module resource to be compliant and exempt, got %+v", validation)
+	}
+}
+
+func TestValidateTerraformPlan_Resources(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}
+    },
+    {
+      "address": "module.vpc.aws_vpc.this",
+      "module_address": "module.vpc",
+      "type": "aws_vpc",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "main", "Owner": "platform"}}}
+    }
+  ]
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	cfg := &config.Config{Required: []string{"Name", "Owner"}}
+	valid, violations, stats, resources := ValidateTerraformPlan(planPath, cfg, "ERROR")
+	if valid {
+		t.Fatal("Expected validation to fail")
+	}
+	if len(violations) != 1 || !reflect.DeepEqual(violations[0].MissingTags, []string{"Owner"}) {
+		t.Fatalf("Expected aws_s3_bucket.logs to miss Owner, got %+v", violations)
+	}
+	if stats.ViolationsByTag["Owner"] != 1 {
+		t.Errorf("Expected 1 Owner violation in statistics, got %v", stats.ViolationsByTag)
+	}
+	if len(resources) != 2 || resources[0].Name != "logs" || resources[1].Name != "this" {
+		t.Errorf("Expected direct and module resources for remediation, got %+v", resources)
+	}
+}
+
+func TestValidateTerraformPlan_NestedModules(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "module.platform.aws_s3_bucket.artifacts",
+      "module_address": "module.platform",
+      "type": "aws_s3_bucket",
+      "name": "artifacts",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "artifacts"}}}
+    },
+    {
+      "address": "module.platform.module.vpc[0].aws_vpc.this",
+      "module_address": "module.platform.module.vpc[0]",
+      "type": "aws_vpc",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "a"}}}
+    },
+    {
+      "address": "module.platform.module.vpc[1].aws_vpc.this",
+      "module_address": "module.platform.module.vpc[1]",
+      "type": "aws_vpc",
+      "name": "this",
+      "change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "b", "Owner": "network"}}}
+    }
+  ],
+  "configuration": {
+    "root_module": {
+      "module_calls": {
+        "platform": {
+          "source": "./modules/platform",
+          "module": {
+            "module_calls": {
+              "vpc": {"source": "terraform-aws-modules/vpc/aws", "version": "5.0.0", "module": {}}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	cfg := &config.Config{
+		Required: []string{"Name", "Owner"},
+		Exemptions: []config.ResourceExemption{
+			{ModuleSource: "terraform-aws-modules/vpc/*", ExemptTags: []string{"Owner"}, Reason: "VPC module"},
+		},
+	}
+	_, violations, stats, _ := ValidateTerraformPlan(planPath, cfg, "ERROR")
+
+	exempt := make(map[string]bool)
+	for _, v := range violations {
+		exempt[v.Address] = v.IsExempt
+	}
+	expectedExempt := map[string]bool{
+		"module.platform.aws_s3_bucket.artifacts":    false,
+		"module.platform.module.vpc[0].aws_vpc.this": true,
+	}
+	if !reflect.DeepEqual(exempt, expectedExempt) {
+		t.Errorf("Expected violations %v, got %v", expectedExempt, exempt)
+	}
+
+	expectedModules := []ModuleStats{
+		{ModulePath: "module.platform", ModuleSource: "./modules/platform", TotalResources: 1},
+		{ModulePath: "module.platform.module.vpc", ModuleSource: "terraform-aws-modules/vpc/aws@5.0.0", TotalResources: 2, CompliantResources: 1, FullyExemptResources: 1},
+	}
+	if !reflect.DeepEqual(stats.Modules, expectedModules) {
+		t.Errorf("Expected module statistics %+v, got %+v", expectedModules, stats.Modules)
+	}
+}
+
+// loadTestConfig loads a YAML configuration from a temporary file
+func loadTestConfig(t *testing.T, content string) (*config.Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return config.LoadConfig(path)
+}
@@ -0,0 +1,164 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// PlanInput is a Terraform plan JSON file to validate as part of a batch, with the name it
+// is reported under
+type PlanInput struct {
+	Name string
+	Path string
+}
+
+// PlanStats represents tag compliance statistics for a single plan of a batch
+type PlanStats struct {
+	Name                     string
+	Path                     string
+	Valid                    bool
+	TotalResources           int
+	CompliantResources       int
+	FullyExemptResources     int
+	PartiallyExemptResources int
+	ExcludedResourcesCount   int
+}
+
+// ResolvePlanInputs expands the values given with --plan into the plans to validate. A value
+// is a path, a glob matching any number of paths, or a name=path pair naming the plan. Plans
+// without a name are named after their file without its extension, or after their path when
+// several files share a name.
+func ResolvePlanInputs(values []string) ([]PlanInput, error) {
+	var plans []PlanInput
+	for _, value := range values {
+		name, path := "", value
+		if label, rest, found := strings.Cut(value, "="); found && label != "" && !strings.ContainsAny(label, `/\*?[`) {
+			name, path = label, rest
+		}
+
+		if !strings.ContainsAny(path, "*?[") {
+			plans = append(plans, PlanInput{Name: name, Path: path})
+			continue
+		}
+
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid plan glob %q: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no plan files match %q", path)
+		}
+		for _, match := range matches {
+			plans = append(plans, PlanInput{Name: name, Path: match})
+		}
+	}
+
+	// Plans without a name take the name of their file, unless that name is ambiguous
+	counts := make(map[string]int)
+	for _, plan := range plans {
+		if plan.Name == "" {
+			counts[planFileName(plan.Path)]++
+		}
+	}
+	names := make(map[string]bool)
+	for i := range plans {
+		if plans[i].Name == "" {
+			plans[i].Name = planFileName(plans[i].Path)
+			if counts[plans[i].Name] > 1 {
+				plans[i].Name = filepath.ToSlash(plans[i].Path)
+			}
+		}
+		if names[plans[i].Name] {
+			return nil, fmt.Errorf("plan name %q is used more than once", plans[i].Name)
+		}
+		names[plans[i].Name] = true
+	}
+	return plans, nil
+}
+
+// planFileName returns the name of a plan file without its extension
+func planFileName(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// planResult is the result of validating one plan of a batch
+type planResult struct {
+	valid      bool
+	violations []TagViolation
+	stats      TagComplianceStats
+	resources  []parser.Resource
+}
+
+// ValidateTerraformPlans validates a batch of Terraform plans concurrently and combines their
+// results in the order the plans are given. Each violation and resource is labeled with the
+// name of its plan, and per-plan statistics are returned in TagComplianceStats.Plans alongside the
+// aggregate statistics.
+func ValidateTerraformPlans(plans []PlanInput, cfg *config.Config, logLevel string) (bool, []TagViolation, TagComplianceStats, []parser.Resource) {
+	results := make([]planResult, len(plans))
+
+	workers := min(runtime.GOMAXPROCS(0), len(plans))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				valid, violations, stats, resources := ValidateTerraformPlan(plans[i].Path, cfg, logLevel)
+				results[i] = planResult{valid: valid, violations: violations, stats: stats, resources: resources}
+			}
+		}()
+	}
+	for i := range plans {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	logging.Info("Validated %d plans", len(plans))
+
+	valid := true
+	var allViolations []TagViolation
+	var allResources []parser.Resource
+	stats := newTagComplianceStats()
+
+	for i, plan := range plans {
+		result := results[i]
+		if !result.valid {
+			valid = false
+		}
+		for _, violation := range result.violations {
+			violation.Plan = plan.Name
+			allViolations = append(allViolations, violation)
+		}
+		for j := range result.stats.UnknownTags {
+			result.stats.UnknownTags[j].Plan = plan.Name
+		}
+		for _, resource := range result.resources {
+			resource.Plan = plan.Name
+			allResources = append(allResources, resource)
+		}
+		mergeStats(&stats, result.stats)
+
+		stats.Plans = append(stats.Plans, PlanStats{
+			Name:                     plan.Name,
+			Path:                     plan.Path,
+			Valid:                    result.valid,
+			TotalResources:           result.stats.TotalResources,
+			CompliantResources:       result.stats.CompliantResources,
+			FullyExemptResources:     result.stats.FullyExemptResources,
+			PartiallyExemptResources: result.stats.PartiallyExemptResources,
+			ExcludedResourcesCount:   result.stats.ExcludedResourcesCount,
+		})
+	}
+
+	return valid, allViolations, stats, allResources
+}
@@ -0,0 +1,161 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+)
+
+func TestResolvePlanInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"prod/plan.json", "dev/plan.json", "us-east-1.json", "eu-west-1.json"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatalf("Failed to write plan: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		values      []string
+		expected    []PlanInput
+		expectError bool
+	}{
+		{
+			name:     "Single file",
+			values:   []string{filepath.Join(dir, "us-east-1.json")},
+			expected: []PlanInput{{Name: "us-east-1", Path: filepath.Join(dir, "us-east-1.json")}},
+		},
+		{
+			name:   "Glob",
+			values: []string{filepath.Join(dir, "*.json")},
+			expected: []PlanInput{
+				{Name: "eu-west-1", Path: filepath.Join(dir, "eu-west-1.json")},
+				{Name: "us-east-1", Path: filepath.Join(dir, "us-east-1.json")},
+			},
+		},
+		{
+			name:   "Named plans",
+			values: []string{"prod=" + filepath.Join(dir, "prod/plan.json"), "dev=" + filepath.Join(dir, "dev/plan.json")},
+			expected: []PlanInput{
+				{Name: "prod", Path: filepath.Join(dir, "prod/plan.json")},
+				{Name: "dev", Path: filepath.Join(dir, "dev/plan.json")},
+			},
+		},
+		{
+			name:   "Files sharing a name are named after their path",
+			values: []string{filepath.Join(dir, "*/plan.json")},
+			expected: []PlanInput{
+				{Name: filepath.ToSlash(filepath.Join(dir, "dev/plan.json")), Path: filepath.Join(dir, "dev/plan.json")},
+				{Name: filepath.ToSlash(filepath.Join(dir, "prod/plan.json")), Path: filepath.Join(dir, "prod/plan.json")},
+			},
+		},
+		{
+			name:        "Glob without matches",
+			values:      []string{filepath.Join(dir, "*.tfplan")},
+			expectError: true,
+		},
+		{
+			name:        "Duplicate names",
+			values:      []string{"prod=" + filepath.Join(dir, "us-east-1.json"), "prod=" + filepath.Join(dir, "eu-west-1.json")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plans, err := ResolvePlanInputs(tt.values)
+			if tt.expectError {
+				if err == nil {
+					t.Fatalf("Expected an error, got %+v", plans)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(plans, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, plans)
+			}
+		})
+	}
+}
+
+func TestValidateTerraformPlans(t *testing.T) {
+	dir := t.TempDir()
+	plans := map[string]string{
+		"compliant.json": `{"resource_changes": [{"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "name": "logs",
+			"change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs", "Owner": "platform"}}}}]}`,
+		"failing.json": `{"resource_changes": [{"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "name": "logs",
+			"change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}}]}`,
+		"broken.json": `{"resource_changes": [`,
+	}
+	for name, content := range plans {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write plan: %v", err)
+		}
+	}
+
+	inputs := []PlanInput{
+		{Name: "prod", Path: filepath.Join(dir, "compliant.json")},
+		{Name: "dev", Path: filepath.Join(dir, "failing.json")},
+		{Name: "broken", Path: filepath.Join(dir, "broken.json")},
+	}
+	cfg := &config.Config{Required: []string{"Name", "Owner"}}
+	valid, violations, stats, resources := ValidateTerraformPlans(inputs, cfg, "ERROR")
+
+	if valid {
+		t.Fatal("Expected validation to fail")
+	}
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %+v", violations)
+	}
+	if violations[0].Plan != "dev" || !reflect.DeepEqual(violations[0].MissingTags, []string{"Owner"}) {
+		t.Errorf("Expected dev to miss Owner, got %+v", violations[0])
+	}
+	if violations[1].Plan != "broken" || violations[1].ResourceType != "error" {
+		t.Errorf("Expected an error for the broken plan, got %+v", violations[1])
+	}
+
+	// Both plans contain aws_s3_bucket.logs; each resource is labeled with its own plan
+	var resourcePlans []string
+	for _, resource := range resources {
+		resourcePlans = append(resourcePlans, resource.Plan+":"+resource.Tags["Owner"])
+	}
+	if !reflect.DeepEqual(resourcePlans, []string{"prod:platform", "dev:"}) {
+		t.Errorf("Expected resources labeled [prod:platform dev:], got %v", resourcePlans)
+	}
+
+	if stats.TotalResources != 2 || stats.CompliantResources != 1 {
+		t.Errorf("Expected 1/2 compliant resources, got %d/%d", stats.CompliantResources, stats.TotalResources)
+	}
+	var planValid []bool
+	for _, plan := range stats.Plans {
+		planValid = append(planValid, plan.Valid)
+	}
+	if !reflect.DeepEqual(planValid, []bool{true, false, false}) {
+		t.Errorf("Expected per-plan results [true false false], got %v", planValid)
+	}
+
+	content, err := GenerateJSONReport(valid, violations, stats)
+	if err != nil {
+		t.Fatalf("Failed to generate JSON report: %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Failed to read JSON report: %v", err)
+	}
+	if len(report.Plans) != 3 || report.Plans[1].Name != "dev" || report.Plans[1].Summary.NonCompliantResources != 1 {
+		t.Errorf("Expected per-plan summaries in JSON report, got %+v", report.Plans)
+	}
+	if report.Summary.TotalResources != 2 || report.Violations[0].Plan != "dev" {
+		t.Errorf("Expected combined summary and labeled violations in JSON report, got %+v", report)
+	}
+}
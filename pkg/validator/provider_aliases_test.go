@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+)
+
+func TestValidateDirectory_ProviderAliases(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"providers.tf": `
+provider "aws" {
+  region = "us-east-1"
+  default_tags {
+    tags = {
+      Environment = "prod"
+      Owner       = "platform"
+    }
+  }
+}
+
+provider "aws" {
+  alias  = "west"
+  region = "us-west-2"
+  default_tags {
+    tags = {
+      Environment = "dr"
+    }
+  }
+}
+`,
+		"main.tf": `
+resource "aws_s3_bucket" "default" {
+  tags = { Name = "default" }
+}
+
+resource "aws_s3_bucket" "west" {
+  provider = aws.west
+  tags     = { Name = "west" }
+}
+
+module "replica" {
+  source = "./modules/replica"
+  providers = {
+    aws = aws.west
+  }
+}
+
+module "primary" {
+  source = "./modules/replica"
+}
+`,
+		"modules/replica/main.tf": `
+resource "aws_s3_bucket" "this" {
+  tags = { Name = "replica" }
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.Config{Required: []string{"Name", "Environment", "Owner"}}
+	valid, violations, _, _ := ValidateDirectory(root, cfg, "ERROR")
+	if valid {
+		t.Fatal("Expected validation to fail")
+	}
+
+	var got []string
+	for _, violation := range violations {
+		got = append(got, violation.ModulePath+" "+violation.ResourceType+"."+violation.ResourceName)
+		for _, tag := range violation.MissingTags {
+			if tag != "Owner" {
+				t.Errorf("Expected only Owner to be missing, got %v", violation.MissingTags)
+			}
+		}
+	}
+	sort.Strings(got)
+	expected := []string{
+		" aws_s3_bucket.west",
+		"module.replica aws_s3_bucket.this",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("Expected violations %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected violation %q, got %q", expected[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// ConstraintViolation represents a tag that the provider of a resource would reject, such as
+// a key longer than the provider allows, or a resource with more tags than the provider allows
+type ConstraintViolation struct {
+	Provider string             // Provider family whose constraint is violated: aws, azure, google or datadog
+	TagName  string             // Empty when the constraint is on the number of tags
+	Message  string             // e.g., "key is 140 characters long, aws allows at most 128"
+	Source   string             // "resource" or "provider_default"
+	Range    parser.SourceRange // Range of the tag, when known
+}
+
+// providerTagConstraints are the constraints a provider family enforces on tags
+type providerTagConstraints struct {
+	maxTags int
+	// check returns what is wrong with a tag. Values only known after apply are not checked.
+	check func(key, value string, valueKnown bool) []string
+}
+
+// providerConstraints are the tag constraints of each provider family, as documented by
+// the providers' APIs
+var providerConstraints = map[string]providerTagConstraints{
+	"aws":     {maxTags: 50, check: checkAWSTag},
+	"azure":   {maxTags: 50, check: checkAzureTag},
+	"google":  {maxTags: 64, check: checkGoogleLabel},
+	"datadog": {check: checkDatadogTag},
+}
+
+// azureForbiddenNameCharacters cannot be used in Azure tag names
+const azureForbiddenNameCharacters = `<>%&\?/`
+
+// checkAWSTag checks an AWS tag: keys up to 128 characters and values up to 256 characters of
+// letters, digits, spaces and _.:/=+-@, and keys without the reserved aws: prefix
+func checkAWSTag(key, value string, valueKnown bool) []string {
+	var problems []string
+	problems = appendTooLong(problems, "key", key, 128, "aws")
+	if strings.HasPrefix(strings.ToLower(key), "aws:") {
+		problems = append(problems, "key starts with 'aws:', which aws reserves for its own tags")
+	}
+	problems = appendAWSCharacters(problems, "key", key)
+	if valueKnown {
+		problems = appendTooLong(problems, "value", value, 256, "aws")
+		problems = appendAWSCharacters(problems, "value", value)
+	}
+	return problems
+}
+
+// appendAWSCharacters adds a problem when a tag key or value has a character AWS rejects
+func appendAWSCharacters(problems []string, part, s string) []string {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Z, r) && !strings.ContainsRune("_.:/=+-@", r) {
+			return append(problems, fmt.Sprintf("%s contains '%c', aws only allows letters, digits, spaces and _.:/=+-@", part, r))
+		}
+	}
+	return problems
+}
+
+// checkAzureTag checks an Azure tag: names up to 512 characters without any of <>%&\?/, and
+// values up to 256 characters
+func checkAzureTag(key, value string, valueKnown bool) []string {
+	var problems []string
+	problems = appendTooLong(problems, "name", key, 512, "azure")
+	if i := strings.IndexAny(key, azureForbiddenNameCharacters); i >= 0 {
+		char, _ := utf8.DecodeRuneInString(key[i:])
+		problems = append(problems, fmt.Sprintf("name contains '%c', azure does not allow any of %s in tag names", char, azureForbiddenNameCharacters))
+	}
+	if valueKnown {
+		problems = appendTooLong(problems, "value", value, 256, "azure")
+	}
+	return problems
+}
+
+// checkGoogleLabel checks a Google label: keys and values up to 63 lower case letters, digits,
+// underscores and dashes, with keys starting with a letter
+func checkGoogleLabel(key, value string, valueKnown bool) []string {
+	var problems []string
+	problems = appendTooLong(problems, "key", key, 63, "google")
+	if first, _ := utf8.DecodeRuneInString(key); !unicode.IsLower(first) {
+		problems = append(problems, "key must start with a lower case letter")
+	}
+	if !isGoogleLabelText(key) {
+		problems = append(problems, "key must only contain lower case letters, digits, '_' and '-'")
+	}
+	if valueKnown {
+		problems = appendTooLong(problems, "value", value, 63, "google")
+		if !isGoogleLabelText(value) {
+			problems = append(problems, "value must only contain lower case letters, digits, '_' and '-'")
+		}
+	}
+	return problems
+}
+
+// isGoogleLabelText checks if a label key or value only uses the characters Google allows
+func isGoogleLabelText(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLower(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDatadogTag checks a Datadog "key:value" tag: up to 200 characters, starting with a
+// letter, and only using letters, digits and _-:./ (Datadog converts other characters to
+// underscores)
+func checkDatadogTag(key, value string, valueKnown bool) []string {
+	tag := key
+	if valueKnown && value != "" {
+		tag = key + ":" + value
+	}
+
+	var problems []string
+	problems = appendTooLong(problems, "tag", tag, 200, "datadog")
+	if first, _ := utf8.DecodeRuneInString(tag); !unicode.IsLetter(first) {
+		problems = append(problems, "tag must start with a letter")
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("_-:./", r) {
+			problems = append(problems, fmt.Sprintf("tag contains '%c', which datadog converts to '_'", r))
+			break
+		}
+	}
+	return problems
+}
+
+// appendTooLong adds a problem when a tag part is longer than a provider allows. Lengths are
+// counted in characters, as the providers count them.
+func appendTooLong(problems []string, part, s string, limit int, provider string) []string {
+	if length := utf8.RuneCountInString(s); length > limit {
+		problems = append(problems, fmt.Sprintf("%s is %d characters long, %s allows at most %d", part, length, provider, limit))
+	}
+	return problems
+}
+
+// findConstraintViolations checks every tag of a resource, including the provider default tags
+// it inherits, against the tag constraints of the resource's provider family, when enabled in
+// the configuration. Tags the resource is exempt from are not reported.
+func findConstraintViolations(resource parser.Resource, cfg *config.Config, defaultTags map[string]string, unknownDefaultTags map[string]bool) []ConstraintViolation {
+	provider := parser.ProviderFamily(resource.Type)
+	constraints, found := providerConstraints[provider]
+	if !found || !cfg.ChecksProviderConstraints(provider) {
+		return nil
+	}
+
+	var violations []ConstraintViolation
+	check := func(key, value string, valueKnown bool, source string, r parser.SourceRange) {
+		problems := constraints.check(key, value, valueKnown)
+		if len(problems) == 0 {
+			return
+		}
+		if exempt, _ := cfg.IsExempt(exemptionTarget(resource), key); exempt {
+			return
+		}
+		for _, problem := range problems {
+			violations = append(violations, ConstraintViolation{
+				Provider: provider,
+				TagName:  key,
+				Message:  problem,
+				Source:   source,
+				Range:    r,
+			})
+		}
+	}
+
+	count := len(resource.Tags)
+	for _, key := range slices.Sorted(maps.Keys(resource.Tags)) {
+		check(key, resource.Tags[key], !resource.UnknownTags[key], "resource", tagRange(resource, key))
+	}
+	for _, key := range slices.Sorted(maps.Keys(defaultTags)) {
+		if _, overridden := resource.Tags[key]; overridden {
+			continue
+		}
+		count++
+		check(key, defaultTags[key], !unknownDefaultTags[key], "provider_default", parser.SourceRange{})
+	}
+
+	// Provider default tags count towards the limit, since the provider sets them on the resource
+	if constraints.maxTags > 0 && count > constraints.maxTags {
+		violations = append(violations, ConstraintViolation{
+			Provider: provider,
+			Message:  fmt.Sprintf("resource has %d tags, %s allows at most %d", count, provider, constraints.maxTags),
+			Source:   "resource",
+			Range:    resource.TagsRange,
+		})
+	}
+	return violations
+}
@@ -0,0 +1,180 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateResources_ProviderConstraints(t *testing.T) {
+	cfg, err := loadTestConfig(t, `required_tags:
+  - Name
+
+provider_constraints:
+  aws: true
+  azure: true
+  google: false
+  datadog: true
+
+exemptions:
+  - resource_type: azurerm_resource_group
+    resource_name: exempt
+    exempt_tags: ["cost/center"]
+    reason: Migration in progress
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	manyTags := map[string]string{"Name": "many"}
+	for i := 0; i < 50; i++ {
+		manyTags[fmt.Sprintf("Tag%02d", i)] = "x"
+	}
+
+	tests := []struct {
+		name      string
+		resource  parser.Resource
+		providers []parser.ProviderConfig
+		expected  []ConstraintViolation
+	}{
+		{
+			name:     "AWS tags within limits",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "ok", Tags: map[string]string{"Name": "ok", "Description": strings.Repeat("a", 256)}},
+		},
+		{
+			name:     "AWS key and value too long",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "long", Tags: map[string]string{"Name": strings.Repeat("v", 257), strings.Repeat("k", 129): "x"}},
+			expected: []ConstraintViolation{
+				{Provider: "aws", TagName: "Name", Message: "value is 257 characters long, aws allows at most 256", Source: "resource"},
+				{Provider: "aws", TagName: strings.Repeat("k", 129), Message: "key is 129 characters long, aws allows at most 128", Source: "resource"},
+			},
+		},
+		{
+			name:      "AWS tag count includes provider default tags",
+			resource:  parser.Resource{Type: "awscc_s3_bucket", Name: "many", Tags: manyTags},
+			providers: []parser.ProviderConfig{{Name: "awscc", DefaultTags: map[string]string{"Team": "platform"}}},
+			expected: []ConstraintViolation{
+				{Provider: "aws", Message: "resource has 52 tags, aws allows at most 50", Source: "resource"},
+			},
+		},
+		{
+			name:     "Unknown values are not checked",
+			resource: parser.Resource{Type: "aws_s3_bucket", Name: "unknown", Tags: map[string]string{"Name": ""}, UnknownTags: map[string]bool{"Name": true}},
+		},
+		{
+			name:     "Azure name characters",
+			resource: parser.Resource{Type: "azurerm_storage_account", Name: "chars", Tags: map[string]string{"Name": "chars", "cost%center": "x"}},
+			expected: []ConstraintViolation{
+				{Provider: "azure", TagName: "cost%center", Message: `name contains '%', azure does not allow any of <>%&\?/ in tag names`, Source: "resource"},
+			},
+		},
+		{
+			name:     "Exempt tags",
+			resource: parser.Resource{Type: "azurerm_resource_group", Name: "exempt", Tags: map[string]string{"Name": "exempt", "cost/center": "x"}},
+		},
+		{
+			name:     "Disabled provider",
+			resource: parser.Resource{Type: "google_storage_bucket", Name: "disabled", Tags: map[string]string{"Name": "Upper Case"}},
+		},
+		{
+			name:     "Datadog tags",
+			resource: parser.Resource{Type: "datadog_monitor", Name: "dd", Tags: map[string]string{"Name": "cpu monitor", "team": "platform/core"}},
+			expected: []ConstraintViolation{
+				{Provider: "datadog", TagName: "Name", Message: "tag contains ' ', which datadog converts to '_'", Source: "resource"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.resource.TagSources = make(map[string]parser.TagSource)
+			valid, violations, stats, _ := ValidateResources([]parser.Resource{tt.resource}, tt.providers, cfg)
+			if valid != (len(tt.expected) == 0) {
+				t.Fatalf("Expected valid %v, got %v with %+v", len(tt.expected) == 0, valid, violations)
+			}
+			if len(tt.expected) == 0 {
+				return
+			}
+
+			if len(violations) != 1 {
+				t.Fatalf("Expected 1 violation, got %+v", violations)
+			}
+			if !reflect.DeepEqual(violations[0].ConstraintViolations, tt.expected) {
+				t.Errorf("Expected constraint violations %+v, got %+v", tt.expected, violations[0].ConstraintViolations)
+			}
+			provider := tt.expected[0].Provider
+			if stats.ConstraintViolationsByProvider[provider] != len(tt.expected) {
+				t.Errorf("Expected %d %s constraint violations to be counted, got %d", len(tt.expected), provider, stats.ConstraintViolationsByProvider[provider])
+			}
+		})
+	}
+}
+
+func TestCheckGoogleLabel(t *testing.T) {
+	tests := []struct {
+		key      string
+		value    string
+		expected []string
+	}{
+		{key: "environment", value: "prod-1"},
+		{key: "env", value: ""},
+		{key: "Environment", value: "prod", expected: []string{
+			"key must start with a lower case letter",
+			"key must only contain lower case letters, digits, '_' and '-'",
+		}},
+		{key: "1env", value: "Prod", expected: []string{
+			"key must start with a lower case letter",
+			"value must only contain lower case letters, digits, '_' and '-'",
+		}},
+		{key: "owner", value: strings.Repeat("a", 64), expected: []string{
+			"value is 64 characters long, google allows at most 63",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			if got := checkGoogleLabel(tt.key, tt.value, true); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckAWSTag(t *testing.T) {
+	tests := []struct {
+		key        string
+		value      string
+		valueKnown bool
+		expected   []string
+	}{
+		{key: "Cost Center", value: "CC-1001 / team@example.com", valueKnown: true},
+		{key: "aws:owner", value: "x", valueKnown: true, expected: []string{
+			"key starts with 'aws:', which aws reserves for its own tags",
+		}},
+		{key: "AWS:Owner", value: "x", valueKnown: true, expected: []string{
+			"key starts with 'aws:', which aws reserves for its own tags",
+		}},
+		{key: "Owner#1", value: "a&b", valueKnown: true, expected: []string{
+			"key contains '#', aws only allows letters, digits, spaces and _.:/=+-@",
+			"value contains '&', aws only allows letters, digits, spaces and _.:/=+-@",
+		}},
+		{key: "Owner", value: "a&b", valueKnown: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			if got := checkAWSTag(tt.key, tt.value, tt.valueKnown); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidProviderConstraints(t *testing.T) {
+	if _, err := loadTestConfig(t, "provider_constraints:\n  gcp: true\n"); err == nil {
+		t.Error("Expected an error loading the config")
+	}
+}
@@ -0,0 +1,302 @@
+package validator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// defaultExcludedDirs are directory names that are never scanned in recursive mode
+var defaultExcludedDirs = map[string]bool{
+	".terraform": true,
+	".git":       true,
+}
+
+// DirectoryStats represents tag compliance statistics for a single Terraform module directory
+type DirectoryStats struct {
+	Dir                      string
+	TotalResources           int
+	CompliantResources       int
+	FullyExemptResources     int
+	PartiallyExemptResources int
+	ExcludedResourcesCount   int
+}
+
+// ValidateDirectoryRecursive walks a directory tree and validates every directory containing
+// Terraform files as its own Terraform module. Per-directory statistics are returned in
+// TagComplianceStats.Directories alongside the aggregate statistics.
+func ValidateDirectoryRecursive(root string, cfg *config.Config, logLevel string) (bool, []TagViolation, TagComplianceStats, []parser.Resource) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return false, []TagViolation{{
+			ResourceType: "error",
+			ResourceName: "error",
+			ResourcePath: root,
+			MissingTags:  []string{fmt.Sprintf("Directory does not exist: %s", root)},
+		}}, TagComplianceStats{}, nil
+	}
+
+	dirs, err := findTerraformDirectories(root, cfg.Exclude)
+	if err != nil {
+		return false, []TagViolation{{
+			ResourceType: "error",
+			ResourceName: "error",
+			ResourcePath: root,
+			MissingTags:  []string{fmt.Sprintf("Error scanning directory tree: %s", err)},
+		}}, TagComplianceStats{}, nil
+	}
+
+	if len(dirs) == 0 {
+		return false, []TagViolation{{
+			ResourceType: "error",
+			ResourceName: "error",
+			ResourcePath: root,
+			MissingTags:  []string{fmt.Sprintf("No Terraform files (*.tf, *.tf.json) found under directory: %s", root)},
+		}}, TagComplianceStats{}, nil
+	}
+
+	logging.Info("Found %d Terraform directories to analyze under %s", len(dirs), root)
+
+	valid := true
+	var allViolations []TagViolation
+	var allResources []parser.Resource
+	stats := newTagComplianceStats()
+
+	// Directories reached through local module calls are validated with the inputs of those
+	// calls as part of their callers rather than on their own
+	calledDirs := reachableModuleDirs(dirs)
+
+	for _, dir := range dirs {
+		if calledDirs[dir] {
+			logging.Info("Skipping %s: validated through a module call", dir)
+			continue
+		}
+
+		logging.Info("Validating Terraform directory: %s", dir)
+		dirValid, violations, dirStats, resources := ValidateDirectory(dir, cfg, logLevel)
+		if !dirValid {
+			valid = false
+		}
+		allViolations = append(allViolations, violations...)
+		allResources = append(allResources, resources...)
+		mergeStats(&stats, dirStats)
+
+		stats.Directories = append(stats.Directories, DirectoryStats{
+			Dir:                      dir,
+			TotalResources:           dirStats.TotalResources,
+			CompliantResources:       dirStats.CompliantResources,
+			FullyExemptResources:     dirStats.FullyExemptResources,
+			PartiallyExemptResources: dirStats.PartiallyExemptResources,
+			ExcludedResourcesCount:   dirStats.ExcludedResourcesCount,
+		})
+	}
+
+	return valid, allViolations, stats, allResources
+}
+
+// mergeStats adds the statistics of one validation run to an aggregate
+func mergeStats(total *TagComplianceStats, stats TagComplianceStats) {
+	total.TotalResources += stats.TotalResources
+	total.CompliantResources += stats.CompliantResources
+	total.FullyExemptResources += stats.FullyExemptResources
+	total.PartiallyExemptResources += stats.PartiallyExemptResources
+	total.ExcludedResourcesCount += stats.ExcludedResourcesCount
+
+	for _, resourceType := range stats.ExcludedAWSCCResources {
+		found := false
+		for _, existing := range total.ExcludedAWSCCResources {
+			if existing == resourceType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			total.ExcludedAWSCCResources = append(total.ExcludedAWSCCResources, resourceType)
+		}
+	}
+
+	for tag, count := range stats.ViolationsByTag {
+		total.ViolationsByTag[tag] += count
+	}
+	for tag, count := range stats.PatternViolationsByTag {
+		total.PatternViolationsByTag[tag] += count
+	}
+	for tag, count := range stats.ValueViolationsByTag {
+		total.ValueViolationsByTag[tag] += count
+	}
+	for tag, count := range stats.ForbiddenTagsByTag {
+		total.ForbiddenTagsByTag[tag] += count
+	}
+	for provider, count := range stats.ConstraintViolationsByProvider {
+		total.ConstraintViolationsByProvider[provider] += count
+	}
+	for tag, count := range stats.PropagationViolationsByTag {
+		total.PropagationViolationsByTag[tag] += count
+	}
+
+	// Local modules inherit their caller's provider configurations, so the same
+	// ignored tag may be reported more than once
+	for _, ignored := range stats.IgnoredRequiredTags {
+		if !slices.Contains(total.IgnoredRequiredTags, ignored) {
+			total.IgnoredRequiredTags = append(total.IgnoredRequiredTags, ignored)
+		}
+	}
+
+	for _, module := range stats.Modules {
+		totalModule := total.moduleStats(module.ModulePath, module.ModuleSource)
+		totalModule.TotalResources += module.TotalResources
+		totalModule.CompliantResources += module.CompliantResources
+		totalModule.FullyExemptResources += module.FullyExemptResources
+		totalModule.PartiallyExemptResources += module.PartiallyExemptResources
+	}
+
+	total.Diagnostics = appendDiagnostics(total.Diagnostics, stats.Diagnostics)
+	total.UnknownTags = append(total.UnknownTags, stats.UnknownTags...)
+}
+
+// reachableModuleDirs returns the directories that are followed as local modules when validating
+// the directories no other directory calls. Directories that only call each other in a cycle are
+// not reachable and are validated on their own.
+func reachableModuleDirs(dirs []string) map[string]bool {
+	scanned := make(map[string]bool)
+	for _, dir := range dirs {
+		scanned[dir] = true
+	}
+
+	calls := make(map[string][]string)
+	called := make(map[string]bool)
+	for _, dir := range dirs {
+		files, err := parser.FindTerraformFiles(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			modules, err := parser.ParseLocalModulesWithContext(file, nil)
+			if err != nil {
+				continue
+			}
+			for _, module := range modules {
+				moduleDir := filepath.Clean(module.Dir)
+				if scanned[moduleDir] && moduleDir != dir {
+					calls[dir] = append(calls[dir], moduleDir)
+					called[moduleDir] = true
+				}
+			}
+		}
+	}
+
+	reachable := make(map[string]bool)
+	var queue []string
+	for _, dir := range dirs {
+		if !called[dir] {
+			queue = append(queue, dir)
+		}
+	}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		for _, moduleDir := range calls[dir] {
+			if !reachable[moduleDir] {
+				reachable[moduleDir] = true
+				queue = append(queue, moduleDir)
+			}
+		}
+	}
+	return reachable
+}
+
+// findTerraformDirectories returns every directory under root that contains Terraform files,
+// skipping .terraform directories and any directory matching an exclude glob
+func findTerraformDirectories(root string, excludes []string) ([]string, error) {
+	dirSet := make(map[string]bool)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if path != root && defaultExcludedDirs[d.Name()] {
+				logging.Debug("Skipping directory %s", path)
+				return filepath.SkipDir
+			}
+			if rel != "." && isExcludedPath(rel, excludes) {
+				logging.Info("Excluding directory %s", path)
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if parser.IsTerraformFile(d.Name()) {
+			dirSet[filepath.Dir(path)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(dirSet))
+	for dir := range dirSet {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// isExcludedPath reports whether a slash-separated path relative to the scan root matches
+// any exclude glob. Patterns without a slash also match the directory's base name.
+func isExcludedPath(rel string, excludes []string) bool {
+	for _, pattern := range excludes {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if matchPathGlob(pattern, rel) {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if matched, _ := filepath.Match(pattern, filepath.Base(rel)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPathGlob matches a slash-separated path against a glob where "*" matches within a
+// path segment and "**" matches any number of segments, including none
+func matchPathGlob(pattern, path string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if matched, _ := filepath.Match(pattern[0], path[0]); !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
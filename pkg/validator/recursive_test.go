@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindTerraformDirectories(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{
+		"main.tf",
+		"envs/dev/main.tf",
+		"envs/prod/main.tf",
+		"modules/network/main.tf",
+		"stacks/app/eu/main.tf",
+		"envs/dev/.terraform/modules/vpc/main.tf",
+		"docs/README.md",
+	} {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(""), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		excludes []string
+		expected []string
+	}{
+		{
+			name:     "Skips .terraform by default",
+			excludes: nil,
+			expected: []string{".", "envs/dev", "envs/prod", "modules/network", "stacks/app/eu"},
+		},
+		{
+			name:     "Double star glob",
+			excludes: []string{"modules/**"},
+			expected: []string{".", "envs/dev", "envs/prod", "stacks/app/eu"},
+		},
+		{
+			name:     "Single segment glob",
+			excludes: []string{"envs/p*"},
+			expected: []string{".", "envs/dev", "modules/network", "stacks/app/eu"},
+		},
+		{
+			name:     "Base name match",
+			excludes: []string{"eu", "dev"},
+			expected: []string{".", "envs/prod", "modules/network"},
+		},
+		{
+			name:     "Leading double star",
+			excludes: []string{"**/app/**"},
+			expected: []string{".", "envs/dev", "envs/prod", "modules/network"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirs, err := findTerraformDirectories(root, tt.excludes)
+			if err != nil {
+				t.Fatalf("findTerraformDirectories failed: %v", err)
+			}
+
+			var relDirs []string
+			for _, dir := range dirs {
+				rel, _ := filepath.Rel(root, dir)
+				relDirs = append(relDirs, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(relDirs, tt.expected) {
+				t.Errorf("Expected directories %v, got %v", tt.expected, relDirs)
+			}
+		})
+	}
+}
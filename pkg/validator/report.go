@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
 )
 
 // UnifiedReportData combines both direct and module resource data
@@ -19,11 +20,93 @@ type UnifiedReportData struct {
 	CompliancePercentage float64
 	RequiredTags         []string
 	Violations           []TagViolation
+	ViolationGroups      []ViolationGroup
 	HasExcludedResources bool
 	// Module-specific fields
-	ModuleResources    []ModuleResourceValidation
-	ModuleViolations   []TagViolation
-	HasModuleResources bool
+	ModuleResources       []ModuleResourceValidation
+	ModuleViolations      []TagViolation
+	ModuleViolationGroups []ViolationGroup
+	HasModuleResources    bool
+	// SectionID distinguishes the resource sections of the report; empty unless there are plan sections
+	SectionID string
+	// PlanSections holds a section per plan when a batch of plans is validated
+	PlanSections []PlanSection
+}
+
+// PlanSection holds the violations of one plan of a batch in the unified report
+type PlanSection struct {
+	SectionID             string
+	Name                  string
+	Path                  string
+	ViolationGroups       []ViolationGroup
+	ModuleViolationGroups []ViolationGroup
+	ModuleResources       []ModuleResourceValidation // Not collected per plan; kept for the shared template
+	HasModuleResources    bool
+}
+
+// ViolationGroup holds the violations of the instances of one configuration resource, so that
+// reports show which count indexes or for_each keys of a resource fail
+type ViolationGroup struct {
+	ConfigAddress string // Address of the resource without instance keys
+	ResourceType  string
+	ResourceName  string
+	ModulePath    string // Module call chain without instance keys
+	Violations    []TagViolation
+}
+
+// HasInstances reports whether the group holds resource instances rather than a single
+// resource without count or for_each
+func (g ViolationGroup) HasInstances() bool {
+	return len(g.Violations) > 1 || g.Violations[0].Address != g.ConfigAddress
+}
+
+// IsExempt reports whether every instance in the group is exempt
+func (g ViolationGroup) IsExempt() bool {
+	for _, v := range g.Violations {
+		if !v.IsExempt {
+			return false
+		}
+	}
+	return true
+}
+
+// InstanceAddresses returns the addresses of the failing instances
+func (g ViolationGroup) InstanceAddresses() []string {
+	addresses := make([]string, 0, len(g.Violations))
+	for _, v := range g.Violations {
+		addresses = append(addresses, v.Address)
+	}
+	return addresses
+}
+
+// GroupViolations groups violations by the plan and configuration resource they belong to,
+// keeping the order in which each resource first appears
+func GroupViolations(violations []TagViolation) []ViolationGroup {
+	var groups []ViolationGroup
+	index := make(map[string]int)
+	for _, v := range violations {
+		configAddress := parser.ConfigAddress(v.Address)
+		key := configAddress
+		if key == "" {
+			// Violations without an address, such as errors, are never grouped
+			key = fmt.Sprintf("%d", len(groups))
+		}
+		// Resources of module instances are reported with the instance's module path
+		key = v.Plan + "|" + parser.ConfigAddress(v.ResourcePath) + "|" + key
+		if i, ok := index[key]; ok {
+			groups[i].Violations = append(groups[i].Violations, v)
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, ViolationGroup{
+			ConfigAddress: configAddress,
+			ResourceType:  v.ResourceType,
+			ResourceName:  v.ResourceName,
+			ModulePath:    parser.ConfigAddress(v.ModulePath),
+			Violations:    []TagViolation{v},
+		})
+	}
+	return groups
 }
 
 // GenerateUnifiedHTMLReport generates a single report that handles both direct and module resources
@@ -40,29 +123,23 @@ func GenerateUnifiedHTMLReport(violations []TagViolation, stats TagComplianceSta
 		moduleRes = moduleResources[0]
 	}
 
-	// Separate direct and module violations
-	var directViolations, moduleViolations []TagViolation
-	for _, v := range violations {
-		// Check if this is a module resource by looking for module path indicators
-		if strings.Contains(v.ResourcePath, "module.") {
-			moduleViolations = append(moduleViolations, v)
-		} else {
-			directViolations = append(directViolations, v)
-		}
-	}
+	directViolations, moduleViolations := splitViolations(violations)
 
 	data := UnifiedReportData{
-		GeneratedTime:        time.Now().Format("2006-01-02 15:04:05"),
-		Stats:                stats,
-		NonCompliantCount:    stats.TotalResources - stats.CompliantResources - stats.FullyExemptResources - stats.PartiallyExemptResources,
-		TotalExemptResources: stats.FullyExemptResources + stats.PartiallyExemptResources,
-		CompliancePercentage: compliancePercentage,
-		RequiredTags:         cfg.Required,
-		Violations:           directViolations,
-		HasExcludedResources: len(stats.ExcludedAWSCCResources) > 0,
-		ModuleResources:      moduleRes,
-		HasModuleResources:   len(moduleRes) > 0 || len(moduleViolations) > 0,
-		ModuleViolations:     moduleViolations,
+		GeneratedTime:         time.Now().Format("2006-01-02 15:04:05"),
+		Stats:                 stats,
+		NonCompliantCount:     stats.TotalResources - stats.CompliantResources - stats.FullyExemptResources - stats.PartiallyExemptResources,
+		TotalExemptResources:  stats.FullyExemptResources + stats.PartiallyExemptResources,
+		CompliancePercentage:  compliancePercentage,
+		RequiredTags:          cfg.Required,
+		Violations:            directViolations,
+		ViolationGroups:       GroupViolations(directViolations),
+		HasExcludedResources:  len(stats.ExcludedAWSCCResources) > 0,
+		ModuleResources:       moduleRes,
+		HasModuleResources:    len(moduleRes) > 0 || len(moduleViolations) > 0,
+		ModuleViolations:      moduleViolations,
+		ModuleViolationGroups: GroupViolations(moduleViolations),
+		PlanSections:          planSections(violations, stats.Plans),
 	}
 
 	tmpl, err := template.New("report").Funcs(template.FuncMap{
@@ -70,7 +147,7 @@ func GenerateUnifiedHTMLReport(violations []TagViolation, stats TagComplianceSta
 		"add": func(a, b int) int {
 			return a + b
 		},
-	}).Parse(getUnifiedTemplate())
+	}).Parse(getUnifiedTemplate() + resourceSectionsTemplate + violationDetailsTemplate)
 
 	if err != nil {
 		return fmt.Sprintf("Error parsing template: %v", err)
@@ -84,6 +161,188 @@ func GenerateUnifiedHTMLReport(violations []TagViolation, stats TagComplianceSta
 	return buf.String()
 }
 
+// splitViolations separates the violations of direct resources from those of module resources
+func splitViolations(violations []TagViolation) ([]TagViolation, []TagViolation) {
+	var directViolations, moduleViolations []TagViolation
+	for _, v := range violations {
+		// Check if this is a module resource by looking for module path indicators
+		if v.ModulePath != "" || strings.Contains(v.ResourcePath, "module.") {
+			moduleViolations = append(moduleViolations, v)
+		} else {
+			directViolations = append(directViolations, v)
+		}
+	}
+	return directViolations, moduleViolations
+}
+
+// planSections returns a report section per plan of a batch, with the violations of the plan
+func planSections(violations []TagViolation, plans []PlanStats) []PlanSection {
+	sections := make([]PlanSection, 0, len(plans))
+	for i, plan := range plans {
+		var planViolations []TagViolation
+		for _, v := range violations {
+			if v.Plan == plan.Name {
+				planViolations = append(planViolations, v)
+			}
+		}
+		directViolations, moduleViolations := splitViolations(planViolations)
+		sections = append(sections, PlanSection{
+			SectionID:             fmt.Sprintf("plan%d", i),
+			Name:                  plan.Name,
+			Path:                  plan.Path,
+			ViolationGroups:       GroupViolations(directViolations),
+			ModuleViolationGroups: GroupViolations(moduleViolations),
+			HasModuleResources:    len(moduleViolations) > 0,
+		})
+	}
+	return sections
+}
+
+// violationDetailsTemplate renders the details of a single violation, shared by the direct
+// and module resource sections of the unified report
+const violationDetailsTemplate = `{{define "violationDetails"}}
+{{if .ModuleChain}}<p><strong>Module Calls:</strong> {{range $i, $c := .ModuleChain}}{{if $i}} &rarr; {{end}}<code>{{$c.Name}}</code> ({{$c.SourceWithVersion}}){{end}}</p>
+{{else if .ModuleSource}}<p><strong>Module Source:</strong> {{.ModuleSource}}</p>{{end}}
+{{if not .Range.IsZero}}<p><strong>Location:</strong> <code>{{.Range.Span}}</code></p>{{end}}
+{{if .IsExempt}}<p><strong>Exempt:</strong> {{.ExemptReason}}</p>{{end}}
+{{if .MissingTags}}<p><strong>Missing:</strong> {{range $i, $t := .MissingTags}}{{if $i}}, {{end}}{{$t}}{{with $.RuleFor $t}} <span class="badge bg-secondary">rule {{.}}</span>{{end}}{{with $.ConditionFor $t}} <small class="text-muted">(required because {{.}})</small>{{end}}{{end}}</p>{{end}}
+{{if .PatternViolations}}
+<p><strong>Pattern Violations:</strong></p>
+<ul>{{range .PatternViolations}}<li>{{.TagName}}: {{.ErrorMessage}}{{with $.RuleFor .TagName}} <span class="badge bg-secondary">rule {{.}}</span>{{end}}{{with $.ConditionFor .TagName}} <small class="text-muted">(required because {{.}})</small>{{end}}{{if not .Range.IsZero}} <small class="text-muted">({{.Range}})</small>{{end}}</li>{{end}}</ul>
+{{end}}
+{{if .ValueViolations}}
+<p><strong>Values Not Allowed:</strong></p>
+<ul>{{range .ValueViolations}}<li>{{.TagName}}: '{{.ActualValue}}' is not one of {{join .AllowedValues ", "}}{{if .Suggestion}} (did you mean '{{.Suggestion}}'?){{end}}{{with $.RuleFor .TagName}} <span class="badge bg-secondary">rule {{.}}</span>{{end}}{{with $.ConditionFor .TagName}} <small class="text-muted">(required because {{.}})</small>{{end}}{{if not .Range.IsZero}} <small class="text-muted">({{.Range}})</small>{{end}}</li>{{end}}</ul>
+{{end}}
+{{if .ForbiddenTags}}
+<p><strong>Forbidden Tags:</strong></p>
+<ul>{{range .ForbiddenTags}}<li>{{.TagName}}: {{.Reason}}{{if eq .Source "provider_default"}} (from provider default_tags){{end}}{{if not .Range.IsZero}} <small class="text-muted">({{.Range}})</small>{{end}}</li>{{end}}</ul>
+{{end}}
+{{if .ConstraintViolations}}
+<p><strong>Rejected by Provider:</strong></p>
+<ul>{{range .ConstraintViolations}}<li>{{with .TagName}}{{.}}: {{end}}{{.Message}}{{if eq .Source "provider_default"}} (from provider default_tags){{end}}{{if not .Range.IsZero}} <small class="text-muted">({{.Range}})</small>{{end}}</li>{{end}}</ul>
+{{end}}
+{{range .LocationViolations}}<p><strong>{{.Location}}:</strong>{{if .MissingTags}} missing {{join .MissingTags ", "}}{{end}}{{range .PatternViolations}}; {{.TagName}}: {{.ErrorMessage}}{{end}}{{range .ValueViolations}}; {{.TagName}}: '{{.ActualValue}}' is not allowed{{end}}</p>{{end}}
+{{if .NotPropagatedTags}}<p><strong>Not Propagated at Launch:</strong> {{join .NotPropagatedTags ", "}}</p>{{end}}
+{{if .UnknownTags}}<p><strong>Known Only After Apply:</strong> {{join .UnknownTags ", "}}</p>{{end}}
+{{if .RemovedTags}}<p><strong>Required Tags Removed by Update:</strong></p>
+<ul>{{range .RemovedTags}}<li><code>{{.TagName}}</code> (was <code>{{.OldValue}}</code>)</li>{{end}}</ul>{{end}}
+{{if .ImmutableTagChanges}}<p><strong>Immutable Tags Changed by Update:</strong></p>
+<ul>{{range .ImmutableTagChanges}}<li><code>{{.TagName}}</code>: <code>{{.OldValue}}</code> &rarr; {{if eq .Kind "removed"}}removed{{else}}<code>{{.NewValue}}</code>{{end}}</li>{{end}}</ul>{{end}}
+{{if .TagChanges}}<p><strong>Required Tag Changes:</strong> {{range $i, $c := .TagChanges}}{{if $i}}, {{end}}<code>{{$c.TagName}}</code> {{$c.Kind}}{{end}}</p>{{end}}
+{{if .TagErrors}}<p><strong>Unevaluated Tag Expressions:</strong></p>
+<ul>{{range .TagErrors}}<li><code>{{.}}</code></li>{{end}}</ul>{{end}}
+{{end}}`
+
+// resourceSectionsTemplate renders the direct and module resource sections of the unified
+// report, once for a single plan or state and once per plan of a batch
+const resourceSectionsTemplate = `{{define "resourceSections"}}
+<!-- Direct Resources -->
+{{if .ViolationGroups}}
+<div class="card mb-4">
+    <div class="card-header bg-secondary text-white">
+        <h2 class="card-title h5 mb-0">Direct Resources</h2>
+    </div>
+    <div class="card-body">
+        <div class="accordion" id="directResourceAccordion{{$.SectionID}}">
+            {{range $index, $g := .ViolationGroups}}
+            <div class="accordion-item">
+                <h2 class="accordion-header">
+                    <button class="accordion-button collapsed" type="button" 
+                            data-bs-toggle="collapse" data-bs-target="#direct{{$.SectionID}}-{{$index}}">
+                        {{$g.ResourceType}} "{{$g.ResourceName}}"
+                        {{if $g.HasInstances}}<span class="badge bg-danger ms-2">{{len $g.Violations}} failing instance(s)</span>{{end}}
+                        {{if $g.IsExempt}}<span class="badge bg-warning ms-2">EXEMPT</span>{{end}}
+                    </button>
+                </h2>
+                <div id="direct{{$.SectionID}}-{{$index}}" class="accordion-collapse collapse">
+                    <div class="accordion-body">
+                        {{if $g.HasInstances}}
+                        <p><strong>Failing Instances:</strong> {{range $i, $a := $g.InstanceAddresses}}{{if $i}}, {{end}}<code>{{$a}}</code>{{end}}</p>
+                        {{range $v := $g.Violations}}
+                        <h3 class="h6 mt-3"><code>{{$v.Address}}</code>{{if $v.IsExempt}} <span class="badge bg-warning">EXEMPT</span>{{end}}</h3>
+                        <p><strong>Path:</strong> {{$v.ResourcePath}}</p>
+                        {{template "violationDetails" $v}}
+                        {{end}}
+                        {{else}}{{with index $g.Violations 0}}
+                        <p><strong>Path:</strong> {{.ResourcePath}}</p>
+                        {{template "violationDetails" .}}
+                        {{end}}{{end}}
+                    </div>
+                </div>
+            </div>
+            {{end}}
+        </div>
+    </div>
+</div>
+{{end}}
+
+<!-- Module Resources -->
+{{if .HasModuleResources}}
+<div class="card mb-4 module-section">
+    <div class="card-header bg-info text-white">
+        <h2 class="card-title h5 mb-0">Module Resources</h2>
+    </div>
+    <div class="card-body">
+        <div class="accordion" id="moduleResourceAccordion{{$.SectionID}}">
+            {{range $index, $m := .ModuleResources}}
+            <div class="accordion-item">
+                <h2 class="accordion-header">
+                    <button class="accordion-button collapsed" type="button" 
+                            data-bs-toggle="collapse" data-bs-target="#module{{$.SectionID}}-{{$index}}">
+                        {{$m.Type}} "{{$m.Name}}"
+                        <span class="module-path ms-2">({{$m.ModulePath}})</span>
+                    </button>
+                </h2>
+                <div id="module{{$.SectionID}}-{{$index}}" class="accordion-collapse collapse">
+                    <div class="accordion-body">
+                        <p><strong>Module:</strong> {{$m.ModulePath}} ({{$m.ModuleSource}})</p>
+                        {{if $m.MissingTags}}<p><strong>Missing:</strong> {{join $m.MissingTags ", "}}</p>{{end}}
+                        {{if $m.PatternViolations}}
+                        <p><strong>Pattern Violations:</strong></p>
+                        <ul>{{range $m.PatternViolations}}<li>{{.TagName}}: {{.ErrorMessage}}</li>{{end}}</ul>
+                        {{end}}
+                        {{if $m.ValueViolations}}
+                        <p><strong>Values Not Allowed:</strong></p>
+                        <ul>{{range $m.ValueViolations}}<li>{{.TagName}}: '{{.ActualValue}}' is not one of {{join .AllowedValues ", "}}{{if .Suggestion}} (did you mean '{{.Suggestion}}'?){{end}}</li>{{end}}</ul>
+                        {{end}}
+                    </div>
+                </div>
+            </div>
+            {{end}}
+            {{range $index, $g := .ModuleViolationGroups}}
+            <div class="accordion-item">
+                <h2 class="accordion-header">
+                    <button class="accordion-button collapsed" type="button" 
+                            data-bs-toggle="collapse" data-bs-target="#moduleViol{{$.SectionID}}-{{$index}}">
+                        {{$g.ResourceType}} "{{$g.ResourceName}}"
+                        <span class="module-path ms-2">({{$g.ModulePath}})</span>
+                        {{if $g.HasInstances}}<span class="badge bg-danger ms-2">{{len $g.Violations}} failing instance(s)</span>{{end}}
+                    </button>
+                </h2>
+                <div id="moduleViol{{$.SectionID}}-{{$index}}" class="accordion-collapse collapse">
+                    <div class="accordion-body">
+                        {{if $g.HasInstances}}
+                        <p><strong>Failing Instances:</strong> {{range $i, $a := $g.InstanceAddresses}}{{if $i}}, {{end}}<code>{{$a}}</code>{{end}}</p>
+                        {{range $v := $g.Violations}}
+                        <h3 class="h6 mt-3"><code>{{$v.Address}}</code>{{if $v.IsExempt}} <span class="badge bg-warning">EXEMPT</span>{{end}}</h3>
+                        <p><strong>Module Path:</strong> {{$v.ModulePath}}</p>
+                        {{template "violationDetails" $v}}
+                        {{end}}
+                        {{else}}{{with index $g.Violations 0}}
+                        <p><strong>Module Path:</strong> {{.ModulePath}}</p>
+                        {{template "violationDetails" .}}
+                        {{end}}{{end}}
+                    </div>
+                </div>
+            </div>
+            {{end}}
+        </div>
+    </div>
+</div>
+{{end}}
+{{end}}`
+
 func getUnifiedTemplate() string {
 	return `<!DOCTYPE html>
 <html>
@@ -184,91 +443,131 @@ func getUnifiedTemplate() string {
             </div>
         </div>
         
-        <!-- Direct Resources -->
-        {{if .Violations}}
+        <!-- Per-module Statistics -->
+        {{if .Stats.Modules}}
         <div class="card mb-4">
             <div class="card-header bg-secondary text-white">
-                <h2 class="card-title h5 mb-0">Direct Resources</h2>
+                <h2 class="card-title h5 mb-0">Modules</h2>
             </div>
             <div class="card-body">
-                <div class="accordion" id="directResourceAccordion">
-                    {{range $index, $v := .Violations}}
-                    <div class="accordion-item">
-                        <h2 class="accordion-header">
-                            <button class="accordion-button collapsed" type="button" 
-                                    data-bs-toggle="collapse" data-bs-target="#direct{{$index}}">
-                                {{$v.ResourceType}} "{{$v.ResourceName}}"
-                                {{if $v.IsExempt}}<span class="badge bg-warning ms-2">EXEMPT</span>{{end}}
-                            </button>
-                        </h2>
-                        <div id="direct{{$index}}" class="accordion-collapse collapse">
-                            <div class="accordion-body">
-                                <p><strong>Path:</strong> {{$v.ResourcePath}}</p>
-                                {{if $v.MissingTags}}<p><strong>Missing:</strong> {{join $v.MissingTags ", "}}</p>{{end}}
-                                {{if $v.PatternViolations}}
-                                <p><strong>Pattern Violations:</strong></p>
-                                <ul>{{range $v.PatternViolations}}<li>{{.TagName}}: {{.ErrorMessage}}</li>{{end}}</ul>
-                                {{end}}
-                            </div>
-                        </div>
-                    </div>
+                <table class="table table-striped">
+                    <thead>
+                        <tr>
+                            <th>Module</th>
+                            <th>Source</th>
+                            <th>Resources</th>
+                            <th>Compliant</th>
+                            <th>Exempt</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Stats.Modules}}
+                        <tr>
+                            <td><code>{{.ModulePath}}</code></td>
+                            <td><code>{{.ModuleSource}}</code></td>
+                            <td>{{.TotalResources}}</td>
+                            <td>{{.CompliantResources}}</td>
+                            <td>{{add .FullyExemptResources .PartiallyExemptResources}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+        </div>
+        {{end}}
+        
+        <!-- Per-plan Statistics -->
+        {{if .Stats.Plans}}
+        <div class="card mb-4">
+            <div class="card-header bg-secondary text-white">
+                <h2 class="card-title h5 mb-0">Plans</h2>
+            </div>
+            <div class="card-body">
+                <table class="table table-striped">
+                    <thead>
+                        <tr>
+                            <th>Plan</th>
+                            <th>File</th>
+                            <th>Resources</th>
+                            <th>Compliant</th>
+                            <th>Exempt</th>
+                            <th>Excluded</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range $i, $p := .Stats.Plans}}
+                        <tr>
+                            <td><a href="#plan{{$i}}">{{.Name}}</a></td>
+                            <td><code>{{.Path}}</code></td>
+                            <td>{{.TotalResources}}</td>
+                            <td>{{.CompliantResources}}</td>
+                            <td>{{add .FullyExemptResources .PartiallyExemptResources}}</td>
+                            <td>{{.ExcludedResourcesCount}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+        </div>
+        {{end}}
+        
+        {{if .PlanSections}}
+        {{range .PlanSections}}
+        <h2 class="h4 mt-4 mb-3" id="{{.SectionID}}">Plan {{.Name}} <small class="text-muted fs-6">{{.Path}}</small></h2>
+        {{if not (or .ViolationGroups .ModuleViolationGroups)}}<p class="text-success">All resources have the required tags.</p>{{end}}
+        {{template "resourceSections" .}}
+        {{end}}
+        {{else}}
+        {{template "resourceSections" .}}
+        {{end}}
+        
+        <!-- Required Tags Ignored by Providers -->
+        {{if .Stats.IgnoredRequiredTags}}
+        <div class="card mt-4">
+            <div class="card-header bg-warning">
+                <h2 class="card-title h5 mb-0">Required Tags Ignored by Providers</h2>
+            </div>
+            <div class="card-body">
+                <p>These required tags are covered by a provider's <code>ignore_tags</code>. Terraform does not manage them, so they cannot be enforced and were not checked:</p>
+                <ul>
+                    {{range .Stats.IgnoredRequiredTags}}
+                    <li><code>{{.Tag}}</code> ignored by provider <code>{{.Provider}}</code> in {{.Path}}</li>
                     {{end}}
-                </div>
+                </ul>
             </div>
         </div>
         {{end}}
         
-        <!-- Module Resources -->
-        {{if .HasModuleResources}}
-        <div class="card mb-4 module-section">
-            <div class="card-header bg-info text-white">
-                <h2 class="card-title h5 mb-0">Module Resources</h2>
+        <!-- Files Not Fully Analyzed -->
+        {{if .Stats.Diagnostics}}
+        <div class="card mt-4">
+            <div class="card-header bg-warning">
+                <h2 class="card-title h5 mb-0">Files Not Fully Analyzed ({{.Stats.UnanalyzedFiles}})</h2>
             </div>
             <div class="card-body">
-                <div class="accordion" id="moduleResourceAccordion">
-                    {{range $index, $m := .ModuleResources}}
-                    <div class="accordion-item">
-                        <h2 class="accordion-header">
-                            <button class="accordion-button collapsed" type="button" 
-                                    data-bs-toggle="collapse" data-bs-target="#module{{$index}}">
-                                {{$m.Type}} "{{$m.Name}}"
-                                <span class="module-path ms-2">({{$m.ModulePath}})</span>
-                            </button>
-                        </h2>
-                        <div id="module{{$index}}" class="accordion-collapse collapse">
-                            <div class="accordion-body">
-                                <p><strong>Module:</strong> {{$m.ModulePath}} ({{$m.ModuleSource}})</p>
-                                {{if $m.MissingTags}}<p><strong>Missing:</strong> {{join $m.MissingTags ", "}}</p>{{end}}
-                                {{if $m.PatternViolations}}
-                                <p><strong>Pattern Violations:</strong></p>
-                                <ul>{{range $m.PatternViolations}}<li>{{.TagName}}: {{.Error}}</li>{{end}}</ul>
-                                {{end}}
-                            </div>
-                        </div>
-                    </div>
+                <p>These parts of Terraform files could not be analyzed, so resources in them may not have been checked:</p>
+                <ul>
+                    {{range .Stats.Diagnostics}}
+                    <li><code>{{if .Range.StartLine}}{{.Range}}{{else}}{{.Range.Filename}}{{end}}</code> <span class="badge {{if eq .Severity "error"}}bg-danger{{else}}bg-warning text-dark{{end}}">{{.Severity}}</span> {{.Summary}}{{if .Detail}}: {{.Detail}}{{end}}</li>
                     {{end}}
-                    {{range $index, $v := .ModuleViolations}}
-                    <div class="accordion-item">
-                        <h2 class="accordion-header">
-                            <button class="accordion-button collapsed" type="button" 
-                                    data-bs-toggle="collapse" data-bs-target="#moduleViol{{$index}}">
-                                {{$v.ResourceType}} "{{$v.ResourceName}}"
-                                <span class="module-path ms-2">({{$v.ResourcePath}})</span>
-                            </button>
-                        </h2>
-                        <div id="moduleViol{{$index}}" class="accordion-collapse collapse">
-                            <div class="accordion-body">
-                                <p><strong>Module Path:</strong> {{$v.ResourcePath}}</p>
-                                {{if $v.MissingTags}}<p><strong>Missing:</strong> {{join $v.MissingTags ", "}}</p>{{end}}
-                                {{if $v.PatternViolations}}
-                                <p><strong>Pattern Violations:</strong></p>
-                                <ul>{{range $v.PatternViolations}}<li>{{.TagName}}: {{.ErrorMessage}}</li>{{end}}</ul>
-                                {{end}}
-                            </div>
-                        </div>
-                    </div>
+                </ul>
+            </div>
+        </div>
+        {{end}}
+        
+        <!-- Tags Known Only After Apply -->
+        {{if .Stats.UnknownTags}}
+        <div class="card mt-4">
+            <div class="card-header bg-info">
+                <h2 class="card-title h5 mb-0">Tags Known Only After Apply</h2>
+            </div>
+            <div class="card-body">
+                <p>The values of these required tags are only known after apply, so they could not be checked:</p>
+                <ul>
+                    {{range .Stats.UnknownTags}}
+                    <li><code>{{.ResourceType}}.{{.ResourceName}}{{.InstanceKey}}</code>{{if .ModulePath}} in <code>{{.ModulePath}}</code>{{end}}: {{join .Tags ", "}}</li>
                     {{end}}
-                </div>
+                </ul>
             </div>
         </div>
         {{end}}
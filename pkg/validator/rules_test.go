@@ -0,0 +1,269 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateResources_Rules(t *testing.T) {
+	cfg, err := loadTestConfig(t, `required_tags:
+  - Name
+  - CostCenter
+
+rules:
+  - name: storage
+    resource_types: [aws_s3_bucket, azurerm_storage_account, google_storage_bucket]
+    required_tags:
+      DataClassification:
+        allowed_values: [public, internal, confidential]
+  - name: azure-owner
+    providers: [azure]
+    required_tags: [Owner]
+  - name: datadog
+    providers: [datadog]
+    required_tags: [team, service]
+    skip_tags: [CostCenter]
+  - name: beta
+    providers: [google-beta]
+    required_tags: [BetaOwner]
+  - name: vendored-modules
+    module_sources: ["terraform-aws-modules/*"]
+    required_tags: [ManagedBy]
+  - name: production
+    paths: ["envs/prod*"]
+    required_tags:
+      CostCenter:
+        pattern: "^CC-[0-9]{4}$"
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	base := map[string]string{"Name": "n", "CostCenter": "CC-1001"}
+	tests := []struct {
+		name            string
+		resource        parser.Resource
+		expectedMissing []string
+		expectedRules   map[string]string
+		expectedValues  int
+		expectedPattern bool
+	}{
+		{
+			name:            "Storage resource needs DataClassification",
+			resource:        parser.Resource{Type: "azurerm_storage_account", Name: "archive", Path: "main.tf", Tags: base},
+			expectedMissing: []string{"DataClassification", "Owner"},
+			expectedRules:   map[string]string{"DataClassification": "storage", "Owner": "azure-owner"},
+		},
+		{
+			name:           "Storage resource with a value that is not allowed",
+			resource:       parser.Resource{Type: "aws_s3_bucket", Name: "logs", Path: "main.tf", Tags: map[string]string{"Name": "n", "CostCenter": "CC-1001", "DataClassification": "secret"}},
+			expectedRules:  map[string]string{"DataClassification": "storage"},
+			expectedValues: 1,
+		},
+		{
+			name:     "IAM role only needs the global tags",
+			resource: parser.Resource{Type: "aws_iam_role", Name: "deploy", Path: "main.tf", Tags: base},
+		},
+		{
+			name:            "Provider family matches azapi",
+			resource:        parser.Resource{Type: "azapi_resource", Name: "vnet", Path: "main.tf", Tags: base},
+			expectedMissing: []string{"Owner"},
+			expectedRules:   map[string]string{"Owner": "azure-owner"},
+		},
+		{
+			name:            "Datadog resource skips CostCenter",
+			resource:        parser.Resource{Type: "datadog_monitor", Name: "cpu", Path: "main.tf", Tags: map[string]string{"Name": "cpu", "team": "platform"}},
+			expectedMissing: []string{"service"},
+			expectedRules:   map[string]string{"team": "datadog", "service": "datadog"},
+		},
+		{
+			name:            "Provider meta-argument selects the provider",
+			resource:        parser.Resource{Type: "google_compute_instance", Name: "beta", Path: "main.tf", Tags: base, Provider: "google-beta"},
+			expectedMissing: []string{"BetaOwner"},
+			expectedRules:   map[string]string{"BetaOwner": "beta"},
+		},
+		{
+			name:            "Provider meta-argument with an alias",
+			resource:        parser.Resource{Type: "google_compute_instance", Name: "beta_west", Path: "main.tf", Tags: base, Provider: "google-beta.west"},
+			expectedMissing: []string{"BetaOwner"},
+			expectedRules:   map[string]string{"BetaOwner": "beta"},
+		},
+		{
+			name:     "Implied provider without the meta-argument",
+			resource: parser.Resource{Type: "google_compute_instance", Name: "ga", Path: "main.tf", Tags: base},
+		},
+		{
+			name:            "Module source selects module resources",
+			resource:        parser.Resource{Type: "aws_iam_role", Name: "this", Path: "main.tf", Tags: base, ModulePath: "module.iam", ModuleSource: "terraform-aws-modules/iam/aws"},
+			expectedMissing: []string{"ManagedBy"},
+			expectedRules:   map[string]string{"ManagedBy": "vendored-modules"},
+		},
+		{
+			name:            "Path selects the directory of the resource",
+			resource:        parser.Resource{Type: "aws_iam_role", Name: "prod", Path: "./envs/prod-us/main.tf", Tags: map[string]string{"Name": "n", "CostCenter": "1001"}},
+			expectedRules:   map[string]string{"CostCenter": "production"},
+			expectedPattern: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.resource.TagSources = make(map[string]parser.TagSource)
+			valid, violations, _, _ := ValidateResources([]parser.Resource{tt.resource}, nil, cfg)
+
+			compliant := len(tt.expectedMissing) == 0 && tt.expectedValues == 0 && !tt.expectedPattern
+			if valid != compliant {
+				t.Fatalf("Expected valid %v, got %v with %+v", compliant, valid, violations)
+			}
+			if compliant {
+				return
+			}
+
+			violation := violations[0]
+			if !reflect.DeepEqual(violation.MissingTags, tt.expectedMissing) {
+				t.Errorf("Expected missing tags %v, got %v", tt.expectedMissing, violation.MissingTags)
+			}
+			if !reflect.DeepEqual(violation.TagRules, tt.expectedRules) {
+				t.Errorf("Expected tag rules %v, got %v", tt.expectedRules, violation.TagRules)
+			}
+			if (len(violation.PatternViolations) > 0) != tt.expectedPattern {
+				t.Errorf("Expected pattern violations %v, got %+v", tt.expectedPattern, violation.PatternViolations)
+			}
+			if len(violation.ValueViolations) != tt.expectedValues {
+				t.Errorf("Expected %d allowed value violations, got %+v", tt.expectedValues, violation.ValueViolations)
+			}
+			for tag, rule := range tt.expectedRules {
+				if got := violation.RuleFor(tag); got != rule {
+					t.Errorf("Expected tag %s to be required by rule %s, got %q", tag, rule, got)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateResources_RulesIgnoreTagCase(t *testing.T) {
+	cfg, err := loadTestConfig(t, `required_tags: [Name, Owner]
+
+rules:
+  - name: storage-owner
+    resource_types: [aws_s3_bucket]
+    required_tags: [owner]
+  - name: storage-name
+    resource_types: [aws_s3_bucket]
+    required_tags:
+      name:
+        pattern: "^[a-z]+$"
+`)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.IgnoreTagCase = true
+
+	resource := parser.Resource{
+		Type:       "aws_s3_bucket",
+		Name:       "logs",
+		Path:       "main.tf",
+		Tags:       map[string]string{"Name": "Logs"},
+		TagSources: make(map[string]parser.TagSource),
+	}
+	valid, violations, _, _ := ValidateResources([]parser.Resource{resource}, nil, cfg)
+	if valid || len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got valid %v and %+v", valid, violations)
+	}
+	if !reflect.DeepEqual(violations[0].MissingTags, []string{"Owner"}) {
+		t.Errorf("Expected missing tags [Owner], got %v", violations[0].MissingTags)
+	}
+	if len(violations[0].PatternViolations) != 1 {
+		t.Errorf("Expected the rule pattern to replace the Name requirement, got %+v", violations[0].PatternViolations)
+	}
+	expectedRules := map[string]string{"Name": "storage-name"}
+	if !reflect.DeepEqual(violations[0].TagRules, expectedRules) {
+		t.Errorf("Expected tag rules %v, got %v", expectedRules, violations[0].TagRules)
+	}
+}
+
+func TestValidateTerraformPlan_RulePaths(t *testing.T) {
+	root := t.TempDir()
+	configDir := filepath.Join(root, "envs", "prod")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	configuration := `
+resource "aws_s3_bucket" "logs" {
+  tags = { Name = "logs" }
+}
+`
+	if err := os.WriteFile(filepath.Join(configDir, "main.tf"), []byte(configuration), 0644); err != nil {
+		t.Fatalf("Failed to write configuration: %v", err)
+	}
+	plan := `{"resource_changes": [{"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "name": "logs",
+		"change": {"actions": ["create"], "before": null, "after": {"tags": {"Name": "logs"}}}}]}`
+	planPath := filepath.Join(root, "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		configDir   string
+		expectValid bool
+	}{
+		{
+			name:        "Configuration directory locates the resource",
+			configDir:   configDir,
+			expectValid: false,
+		},
+		{
+			name:        "Plan directory does not match the rule",
+			expectValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTestConfig(t, `required_tags: [Name]
+
+rules:
+  - name: production
+    paths: ["*/envs/prod"]
+    required_tags: [CostCenter]
+`)
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			cfg.ConfigDir = tt.configDir
+
+			valid, violations, _, _ := ValidateTerraformPlan(planPath, cfg, "ERROR")
+			if valid != tt.expectValid {
+				t.Fatalf("Expected valid %v, got %v with %+v", tt.expectValid, valid, violations)
+			}
+			if !tt.expectValid && !reflect.DeepEqual(violations[0].MissingTags, []string{"CostCenter"}) {
+				t.Errorf("Expected CostCenter to be missing, got %v", violations[0].MissingTags)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "No selectors", content: "rules:\n  - name: all\n    required_tags: [Owner]\n"},
+		{name: "No required tags", content: "rules:\n  - name: s3\n    resource_types: [aws_s3_bucket]\n"},
+		{name: "Duplicate names", content: "rules:\n  - name: s3\n    resource_types: [aws_s3_bucket]\n    required_tags: [Owner]\n  - name: s3\n    providers: [aws]\n    required_tags: [Team]\n"},
+		{name: "Invalid pattern", content: "rules:\n  - name: s3\n    resource_types: [aws_s3_bucket]\n    required_tags:\n      Owner:\n        pattern: \"[\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadTestConfig(t, tt.content); err == nil {
+				t.Error("Expected an error loading the config")
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// withInlineSuppressions returns the configuration with the terratags:ignore comments of the
+// resources added to its exemptions, so they are handled like exemptions from a file. Each
+// exemption selects the exact address of its resource, since resource names are only unique
+// within a module, and the configuration is copied.
+func withInlineSuppressions(cfg *config.Config, resources []parser.Resource) *config.Config {
+	var exemptions []config.ResourceExemption
+	for _, resource := range resources {
+		for _, suppression := range resource.Suppressions {
+			if cfg.DisableInlineSuppressions {
+				logging.Debug("Inline suppressions are disabled, ignoring %s:%d", suppression.Path, suppression.Line)
+				continue
+			}
+			exemptions = append(exemptions, config.ResourceExemption{
+				Address:    regexp.QuoteMeta(exemptionTarget(resource).Address),
+				Match:      "regex",
+				ExemptTags: suppression.Tags,
+				Reason:     suppressionReason(suppression),
+			})
+		}
+	}
+	if len(exemptions) == 0 {
+		return cfg
+	}
+
+	scoped := *cfg
+	scoped.Exemptions = append(slices.Clone(cfg.Exemptions), exemptions...)
+	return &scoped
+}
+
+// suppressionReason describes an inline suppression for reports, naming where it was declared
+func suppressionReason(suppression parser.Suppression) string {
+	location := fmt.Sprintf("terratags:ignore at %s:%d", suppression.Path, suppression.Line)
+	if suppression.Reason == "" {
+		return location
+	}
+	return fmt.Sprintf("%s (%s)", suppression.Reason, location)
+}
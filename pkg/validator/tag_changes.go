@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"sort"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// TagChangeKind classifies how a planned update changes a tag
+type TagChangeKind string
+
+const (
+	TagAdded     TagChangeKind = "added"
+	TagRemoved   TagChangeKind = "removed"
+	TagChanged   TagChangeKind = "changed"
+	TagUnchanged TagChangeKind = "unchanged"
+)
+
+// TagChange describes how a planned update changes a tag. OldValue is empty for added tags
+// and NewValue for removed tags.
+type TagChange struct {
+	TagName  string
+	Kind     TagChangeKind
+	OldValue string
+	NewValue string
+}
+
+// classifyTagChange compares a tag before and after a planned update
+func classifyTagChange(tagName string, oldValue string, hadTag bool, newValue string, hasTag bool) TagChange {
+	change := TagChange{TagName: tagName, OldValue: oldValue, NewValue: newValue}
+	switch {
+	case !hadTag && hasTag:
+		change.Kind = TagAdded
+	case hadTag && !hasTag:
+		change.Kind = TagRemoved
+	case oldValue != newValue:
+		change.Kind = TagChanged
+	default:
+		change.Kind = TagUnchanged
+	}
+	return change
+}
+
+// immutableTagChanges returns the immutable tags a planned update changes or removes. Tags
+// that are only known after apply are not compared.
+func immutableTagChanges(resource parser.Resource, cfg *config.Config) []TagChange {
+	if resource.PreviousTags == nil || len(cfg.ImmutableTags) == 0 {
+		return nil
+	}
+
+	var changes []TagChange
+	for key, oldValue := range resource.PreviousTags {
+		if !cfg.IsImmutableTag(key) || resource.TagsUnknown || resource.UnknownTags[key] {
+			continue
+		}
+		if exempt, _ := cfg.IsExempt(exemptionTarget(resource), key); exempt {
+			continue
+		}
+		_, newValue, hasTag := findTag(resource.Tags, key, cfg.IgnoreTagCase)
+		change := classifyTagChange(key, oldValue, true, newValue, hasTag)
+		if change.Kind == TagChanged || change.Kind == TagRemoved {
+			changes = append(changes, change)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].TagName < changes[j].TagName
+	})
+	return changes
+}
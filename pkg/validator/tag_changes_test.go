@@ -0,0 +1,141 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateTerraformPlan_TagChanges(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {
+        "actions": ["update"],
+        "before": {"tags": {"Name": "logs", "Owner": "platform", "CostCenter": "CC-1"}},
+        "after": {"tags": {"Name": "logs-v2", "CostCenter": "CC-2"}}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.data",
+      "type": "aws_s3_bucket",
+      "name": "data",
+      "change": {
+        "actions": ["update"],
+        "before": {"tags": {"Name": "data"}},
+        "after": {"tags": {"Name": "data", "Owner": "platform"}}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.new",
+      "type": "aws_s3_bucket",
+      "name": "new",
+      "change": {
+        "actions": ["create"],
+        "before": null,
+        "after": {"tags": {"Name": "new", "Owner": "platform", "CostCenter": "CC-3"}}
+      }
+    }
+  ]
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	cfg := &config.Config{
+		RequiredTags:  map[string]config.TagRequirement{"Name": {}, "Owner": {}},
+		Required:      []string{"Name", "Owner"},
+		ImmutableTags: []string{"CostCenter"},
+	}
+	valid, violations, stats, _ := ValidateTerraformPlan(planPath, cfg, "ERROR")
+	if valid {
+		t.Fatal("Expected validation to fail")
+	}
+	if stats.CompliantResources != 2 {
+		t.Errorf("Expected 2 compliant resources, got %d", stats.CompliantResources)
+	}
+	if len(violations) != 1 || violations[0].ResourceName != "logs" {
+		t.Fatalf("Expected only aws_s3_bucket.logs to violate, got %+v", violations)
+	}
+
+	v := violations[0]
+	if len(v.MissingTags) != 0 {
+		t.Errorf("Expected the removed tag not to be reported as missing, got %v", v.MissingTags)
+	}
+	expectedRemoved := []TagChange{{TagName: "Owner", Kind: TagRemoved, OldValue: "platform"}}
+	if !reflect.DeepEqual(v.RemovedTags, expectedRemoved) {
+		t.Errorf("Expected removed tags %+v, got %+v", expectedRemoved, v.RemovedTags)
+	}
+	expectedImmutable := []TagChange{{TagName: "CostCenter", Kind: TagChanged, OldValue: "CC-1", NewValue: "CC-2"}}
+	if !reflect.DeepEqual(v.ImmutableTagChanges, expectedImmutable) {
+		t.Errorf("Expected immutable tag changes %+v, got %+v", expectedImmutable, v.ImmutableTagChanges)
+	}
+	expectedChanges := []TagChange{
+		{TagName: "Name", Kind: TagChanged, OldValue: "logs", NewValue: "logs-v2"},
+		{TagName: "Owner", Kind: TagRemoved, OldValue: "platform"},
+	}
+	if !reflect.DeepEqual(v.TagChanges, expectedChanges) {
+		t.Errorf("Expected tag changes %+v, got %+v", expectedChanges, v.TagChanges)
+	}
+}
+
+func TestValidateResources_RemovedImmutableTagIgnoreCase(t *testing.T) {
+	resource := parser.Resource{
+		Type:         "aws_s3_bucket",
+		Name:         "logs",
+		Tags:         map[string]string{"Name": "logs"},
+		PreviousTags: map[string]string{"Name": "logs", "owner": "platform"},
+		TagSources:   make(map[string]parser.TagSource),
+	}
+	cfg := &config.Config{
+		Required:      []string{"Name", "Owner"},
+		ImmutableTags: []string{"Owner"},
+		IgnoreTagCase: true,
+	}
+
+	valid, violations, _, _ := ValidateResources([]parser.Resource{resource}, nil, cfg)
+	if valid || len(violations) != 1 {
+		t.Fatalf("Expected 1 violation, got %+v", violations)
+	}
+	expectedRemoved := []TagChange{{TagName: "Owner", Kind: TagRemoved, OldValue: "platform"}}
+	if !reflect.DeepEqual(violations[0].RemovedTags, expectedRemoved) {
+		t.Errorf("Expected removed tags %+v, got %+v", expectedRemoved, violations[0].RemovedTags)
+	}
+	if len(violations[0].ImmutableTagChanges) != 0 {
+		t.Errorf("Expected the removal not to be reported again as an immutable tag change, got %+v", violations[0].ImmutableTagChanges)
+	}
+}
+
+func TestClassifyTagChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldValue string
+		hadTag   bool
+		newValue string
+		hasTag   bool
+		expected TagChangeKind
+	}{
+		{name: "Added", newValue: "a", hasTag: true, expected: TagAdded},
+		{name: "Removed", oldValue: "a", hadTag: true, expected: TagRemoved},
+		{name: "Changed", oldValue: "a", hadTag: true, newValue: "b", hasTag: true, expected: TagChanged},
+		{name: "Unchanged", oldValue: "a", hadTag: true, newValue: "a", hasTag: true, expected: TagUnchanged},
+		{name: "Set to empty", oldValue: "a", hadTag: true, hasTag: true, expected: TagChanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := classifyTagChange("Owner", tt.oldValue, tt.hadTag, tt.newValue, tt.hasTag)
+			if change.Kind != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, change.Kind)
+			}
+		})
+	}
+}
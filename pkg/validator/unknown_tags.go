@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/logging"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+// UnknownTagResource is a resource with required tags whose values, or whose tag keys, are
+// only known after apply. Depending on the unknown_tags policy they pass, are warned about
+// or are violations.
+type UnknownTagResource struct {
+	ResourceType string
+	ResourceName string
+	ResourcePath string
+	ModulePath   string
+	Plan         string // Name of the plan when a batch of plans is validated
+	InstanceKey  string
+	Tags         []string
+	Range        parser.SourceRange
+}
+
+// isUnknownTag checks if a required tag of a resource is only known after apply, either
+// because its value is unknown or because the resource's tag keys are unknown and the tag
+// is not among the known ones
+func isUnknownTag(resource parser.Resource, tagKey string, hasTag bool) bool {
+	if !hasTag {
+		return resource.TagsUnknown
+	}
+	return resource.UnknownTags[tagKey]
+}
+
+// failsOnUnknownTags checks if required tags only known after apply are violations
+func failsOnUnknownTags(cfg *config.Config) bool {
+	return cfg.UnknownTagPolicy() == config.UnknownTagsFail
+}
+
+// logUnknownTags logs the required tags of a resource that are only known after apply
+func logUnknownTags(resource parser.Resource, tags []string) {
+	for _, tag := range tags {
+		logging.Debug("Resource %s '%s' tag '%s' cannot be checked until apply", resource.Type, resource.Name, tag)
+	}
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+)
+
+func TestValidateTerraformPlan_UnknownTags(t *testing.T) {
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "aws_s3_bucket.logs",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {
+        "actions": ["create"],
+        "before": null,
+        "after": {"tags": {"Name": "logs"}},
+        "after_unknown": {"tags": {"Owner": true}, "tags_all": true}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.data",
+      "type": "aws_s3_bucket",
+      "name": "data",
+      "change": {
+        "actions": ["create"],
+        "before": null,
+        "after": {"tags": {"Name": "data"}, "tags_all": {"Name": "data"}},
+        "after_unknown": {"tags": {"Owner": true}, "tags_all": {"Owner": true}}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.known",
+      "type": "aws_s3_bucket",
+      "name": "known",
+      "change": {
+        "actions": ["create"],
+        "before": null,
+        "after": {"tags": {"Name": "known", "Owner": "platform"}},
+        "after_unknown": {}
+      }
+    }
+  ]
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		policy             config.UnknownTagPolicy
+		expectValid        bool
+		expectedViolations int
+	}{
+		{name: "Default passes", policy: "", expectValid: true},
+		{name: "Pass", policy: config.UnknownTagsPass, expectValid: true},
+		{name: "Warn", policy: config.UnknownTagsWarn, expectValid: true},
+		{name: "Fail", policy: config.UnknownTagsFail, expectValid: false, expectedViolations: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				RequiredTags: map[string]config.TagRequirement{"Name": {}, "Owner": {Pattern: "^[a-z]+$"}},
+				Required:     []string{"Name", "Owner"},
+				UnknownTags:  tt.policy,
+			}
+			valid, violations, stats, _ := ValidateTerraformPlan(planPath, cfg, "ERROR")
+			if valid != tt.expectValid {
+				t.Errorf("Expected valid %v, got %v with %+v", tt.expectValid, valid, violations)
+			}
+			if len(violations) != tt.expectedViolations {
+				t.Fatalf("Expected %d violations, got %d", tt.expectedViolations, len(violations))
+			}
+			for _, v := range violations {
+				if len(v.MissingTags) != 0 || len(v.PatternViolations) != 0 {
+					t.Errorf("Expected unknown tags not to be missing or checked against a pattern, got %+v", v)
+				}
+			}
+
+			// Unknown tags are reported under every policy
+			unknown := make(map[string][]string)
+			for _, resource := range stats.UnknownTags {
+				unknown[resource.ResourceName] = resource.Tags
+			}
+			expected := map[string][]string{"logs": {"Owner"}, "data": {"Owner"}}
+			if !reflect.DeepEqual(unknown, expected) {
+				t.Errorf("Expected unknown tags %v, got %v", expected, unknown)
+			}
+		})
+	}
+}
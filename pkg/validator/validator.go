@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,25 +19,75 @@ import (
 
 // ResourceValidation represents validation result for a single resource
 type ResourceValidation struct {
-	Type              string
-	Name              string
-	Path              string
-	IsCompliant       bool
-	MissingTags       []string
-	PatternViolations []PatternViolation
-	IsExempt          bool
-	ExemptReason      string
+	Type                 string
+	Name                 string
+	Path                 string
+	Address              string              // Terraform address of the resource, with its instance key in plans and state
+	InstanceKey          string              // Count index or for_each key of the instance, such as [0] or ["logs"]
+	ModuleSource         string              // Source of the module that creates the resource; empty for root module resources
+	ModuleChain          []parser.ModuleCall // Module calls leading to the resource, outermost first; plan mode only
+	IsCompliant          bool
+	MissingTags          []string
+	PatternViolations    []PatternViolation
+	ValueViolations      []AllowedValueViolation
+	ForbiddenTags        []ForbiddenTagViolation
+	ConstraintViolations []ConstraintViolation // Tags the resource's provider would reject
+	IsExempt             bool
+	ExemptReason         string
+	ExemptTags           []string // Missing tags the resource is exempt from, also listed in MissingTags
+	TagErrors            []string
+	NotPropagatedTags    []string // Tags whose tag block sets propagate_at_launch = false
+	LocationViolations   []LocationViolation
+	Range                parser.SourceRange // Range of the resource block, when known
+	TagsRange            parser.SourceRange // Range of the resource's tags, when known
+	TagChanges           []TagChange        // How a planned update changes the required tags
+	RemovedTags          []TagChange        // Required tags a planned update removes
+	ImmutableTagChanges  []TagChange        // Immutable tags a planned update changes or removes
+	UnknownTags          []string           // Required tags only known after apply
+	TagRules             map[string]string  // Rule requiring each tag, for tags required by a rule
+	TagConditions        map[string]string  // Condition requiring each tag, for tags required by a conditional requirement
 }
 
 // TagViolation represents a tag validation violation
 type TagViolation struct {
-	ResourceType      string
-	ResourceName      string
-	ResourcePath      string
+	ResourceType         string
+	ResourceName         string
+	ResourcePath         string
+	ModulePath           string              // e.g., "module.network.module.subnets"; empty for root module resources
+	Plan                 string              // Name of the plan the resource is in when a batch of plans is validated
+	Address              string              // Terraform address of the resource, with its instance key in plans and state
+	InstanceKey          string              // Count index or for_each key of the instance, such as [0] or ["logs"]
+	ModuleSource         string              // Source of the module that creates the resource; empty for root module resources
+	ModuleChain          []parser.ModuleCall // Module calls leading to the resource, outermost first; plan mode only
+	MissingTags          []string
+	PatternViolations    []PatternViolation
+	ValueViolations      []AllowedValueViolation
+	ForbiddenTags        []ForbiddenTagViolation
+	ConstraintViolations []ConstraintViolation // Tags the resource's provider would reject
+	IsExempt             bool
+	ExemptReason         string
+	TagErrors            []string
+	NotPropagatedTags    []string // Tags whose tag block sets propagate_at_launch = false
+	LocationViolations   []LocationViolation
+	Range                parser.SourceRange // Range of the resource block, when known
+	TagsRange            parser.SourceRange // Range of the resource's tags, when known
+	TagChanges           []TagChange        // How a planned update changes the required tags
+	RemovedTags          []TagChange        // Required tags a planned update removes
+	ImmutableTagChanges  []TagChange        // Immutable tags a planned update changes or removes
+	UnknownTags          []string           // Required tags only known after apply
+	TagRules             map[string]string  // Rule requiring each tag, for tags required by a rule
+	TagConditions        map[string]string  // Condition requiring each tag, for tags required by a conditional requirement
+}
+
+// LocationViolation represents required tag violations at a secondary tag location of a
+// resource, such as volume_tags or tag_specifications[instance]
+type LocationViolation struct {
+	Location          string
 	MissingTags       []string
 	PatternViolations []PatternViolation
-	IsExempt          bool
-	ExemptReason      string
+	ValueViolations   []AllowedValueViolation
+	TagErrors         []string
+	Range             parser.SourceRange // Range of the location's tags, when known
 }
 
 // PatternViolation represents a tag value that doesn't match its required pattern
@@ -43,6 +96,16 @@ type PatternViolation struct {
 	ActualValue     string
 	ExpectedPattern string
 	ErrorMessage    string
+	Range           parser.SourceRange // Range of the tag, when known
+}
+
+// AllowedValueViolation represents a tag value that is not one of the tag's allowed values
+type AllowedValueViolation struct {
+	TagName       string
+	ActualValue   string
+	AllowedValues []string
+	Suggestion    string             // Closest allowed value, if any is close enough
+	Range         parser.SourceRange // Range of the tag, when known
 }
 
 // TagComplianceStats represents statistics about tag compliance
@@ -55,56 +118,86 @@ type TagComplianceStats struct {
 	ExcludedResourcesCount   int
 	ViolationsByTag          map[string]int
 	PatternViolationsByTag   map[string]int
+	// ValueViolationsByTag counts tag values that are not among the tag's allowed values
+	ValueViolationsByTag map[string]int
+	// ForbiddenTagsByTag counts forbidden tags found on resources
+	ForbiddenTagsByTag map[string]int
+	// ConstraintViolationsByProvider counts tags that providers would reject, by provider family
+	ConstraintViolationsByProvider map[string]int
+	// PropagationViolationsByTag counts tags not propagated at launch where required
+	PropagationViolationsByTag map[string]int
+	// Directories holds per-directory statistics for recursive scans
+	Directories []DirectoryStats
+	// Modules holds per-module statistics for module resources
+	Modules []ModuleStats
+	// Plans holds per-plan statistics when a batch of plans is validated
+	Plans []PlanStats
+	// IgnoredRequiredTags lists required tags covered by a provider's ignore_tags
+	IgnoredRequiredTags []IgnoredRequiredTag
+	// Diagnostics lists the parts of Terraform files that could not be analyzed
+	Diagnostics []parser.Diagnostic
+	// UnknownTags lists the resources with required tags only known after apply
+	UnknownTags []UnknownTagResource
+}
+
+// UnanalyzedFiles returns the number of files that could not be fully analyzed
+func (s TagComplianceStats) UnanalyzedFiles() int {
+	files := make(map[string]bool)
+	for _, diag := range s.Diagnostics {
+		files[diag.Range.Filename] = true
+	}
+	return len(files)
+}
+
+// IgnoredRequiredTag is a required tag covered by the ignore_tags of a provider configuration.
+// Terraform does not manage the tag for that provider's resources, so the requirement cannot
+// be enforced and is not checked.
+type IgnoredRequiredTag struct {
+	Tag      string
+	Provider string
+	Path     string
 }
 
 // ValidateResources validates that all resources have the required tags
 func ValidateResources(resources []parser.Resource, providers []parser.ProviderConfig, cfg *config.Config) (bool, []TagViolation, TagComplianceStats, []parser.Resource) {
 	var violations []TagViolation
-	stats := TagComplianceStats{
-		ViolationsByTag:        make(map[string]int),
-		PatternViolationsByTag: make(map[string]int),
-	}
+	stats := newTagComplianceStats()
 	valid := true
 
-	// Count resources that are not excluded
-	var nonExcludedResources int
-
-	// We'll track excluded resources later during resource processing
+	// Inline terratags:ignore comments are handled as exemptions
+	cfg = withInlineSuppressions(cfg, resources)
 
-	// Create a map of provider default tags by path
-	defaultTagsByPath := make(map[string]map[string]string)
+	// Create a map of provider configurations by name and alias
+	providersByKey := make(map[string]parser.ProviderConfig)
 	for _, provider := range providers {
-		defaultTagsByPath[provider.Path] = provider.DefaultTags
+		providersByKey[provider.Key()] = provider
 	}
 
 	for _, resource := range resources {
-		// Check if this is an excluded AWSCC resource
+		// Excluded AWSCC resources are counted, but not validated
 		if parser.AwsccExcludedResources[resource.Type] {
-			// Add to excluded resources list if not already there
-			found := false
-			for _, excludedType := range stats.ExcludedAWSCCResources {
-				if excludedType == resource.Type {
-					found = true
-					break
-				}
-			}
-			if !found {
-				stats.ExcludedAWSCCResources = append(stats.ExcludedAWSCCResources, resource.Type)
-			}
-			// Increment the excluded resources count
-			stats.ExcludedResourcesCount++
-			// Skip validation for this resource
+			recordExcluded(&stats, resource.Type)
 			continue
 		}
 
-		// Count this as a non-excluded resource
-		nonExcludedResources++
+		// Get default tags of the provider configuration this resource uses. Provider
+		// default_tags are not applied to resources that declare tags as blocks.
+		defaultTags, unknownDefaultTags := providerDefaultTags(resource, providersByKey)
+		if parser.UsesTagBlocks(resource.Type) {
+			defaultTags = nil
+			unknownDefaultTags = nil
+		}
 
-		// Get default tags for this resource's path
-		defaultTags := defaultTagsByPath[resource.Path]
+		// Required tags covered by the provider's ignore_tags are not checked
+		if resource.IgnoreTags.IsEmpty() && resource.Type != "module" {
+			resource.IgnoreTags = providersByKey[resource.ProviderKey()].IgnoreTags
+		}
 
-		// Track tag sources
+		// Track tag sources, keeping tags already attributed to a module call
 		for k, v := range resource.Tags {
+			if _, exists := resource.TagSources[k]; exists {
+				continue
+			}
 			resource.TagSources[k] = parser.TagSource{
 				Source: "resource",
 				Value:  v,
@@ -121,145 +214,431 @@ func ValidateResources(resources []parser.Resource, providers []parser.ProviderC
 			}
 		}
 
-		// Check for missing required tags and pattern violations
-		var missingTags []string
-		var patternViolations []PatternViolation
-		var exemptTags []string
-		var nonExemptMissingTags []string
-		var exemptReason string
+		validation := validateResource(resource, cfg, defaultTags, unknownDefaultTags)
+		if !validation.IsCompliant {
+			valid = false
+		}
+		if violation, ok := recordValidation(&stats, validation, "", cfg); ok {
+			violations = append(violations, violation)
+		}
+	}
+
+	stats.IgnoredRequiredTags = ignoredRequiredTags(providers, cfg)
 
-		for _, requiredTag := range cfg.Required {
-			// Check if the tag is in the resource's tags
-			var tagValue string
-			var tagExists bool
-			var tagKey string
-
-			if cfg.IgnoreTagCase {
-				// Case-insensitive comparison
-				requiredTagLower := strings.ToLower(requiredTag)
-				for key, value := range resource.Tags {
-					if strings.ToLower(key) == requiredTagLower {
-						tagExists = true
-						tagValue = value
-						tagKey = key
-						break
-					}
-				}
-			} else {
-				// Case-sensitive comparison (original behavior)
-				tagValue, tagExists = resource.Tags[requiredTag]
-				tagKey = requiredTag
+	return valid, violations, stats, resources
+}
+
+// newTagComplianceStats returns empty statistics
+func newTagComplianceStats() TagComplianceStats {
+	return TagComplianceStats{
+		ViolationsByTag:                make(map[string]int),
+		PatternViolationsByTag:         make(map[string]int),
+		ValueViolationsByTag:           make(map[string]int),
+		ForbiddenTagsByTag:             make(map[string]int),
+		ConstraintViolationsByProvider: make(map[string]int),
+		PropagationViolationsByTag:     make(map[string]int),
+	}
+}
+
+// recordExcluded counts a resource of a type excluded from validation in the statistics
+func recordExcluded(stats *TagComplianceStats, resourceType string) {
+	if !slices.Contains(stats.ExcludedAWSCCResources, resourceType) {
+		stats.ExcludedAWSCCResources = append(stats.ExcludedAWSCCResources, resourceType)
+	}
+	stats.ExcludedResourcesCount++
+}
+
+// recordValidation adds the result of validating a resource to the statistics, and returns
+// the violation to report for it, if any. Resources with missing tags they are exempt from
+// are reported, so exemptions show up in reports, but are not compliant in the statistics.
+// Module resources are reported with their module path.
+func recordValidation(stats *TagComplianceStats, validation ResourceValidation, modulePath string, cfg *config.Config) (TagViolation, bool) {
+	stats.TotalResources++
+	module := stats.moduleStats(modulePath, validation.ModuleSource)
+	if module != nil {
+		module.TotalResources++
+	}
+
+	path := validation.Path
+	if modulePath != "" {
+		path = modulePath
+	}
+
+	for _, tag := range validation.MissingTags {
+		if !slices.Contains(validation.ExemptTags, tag) {
+			stats.ViolationsByTag[tag]++
+		}
+	}
+	for _, change := range validation.RemovedTags {
+		stats.ViolationsByTag[change.TagName]++
+	}
+	for _, pv := range validation.PatternViolations {
+		stats.PatternViolationsByTag[requiredTagName(cfg, pv.TagName)]++
+	}
+	for _, vv := range validation.ValueViolations {
+		stats.ValueViolationsByTag[requiredTagName(cfg, vv.TagName)]++
+	}
+	for _, fv := range validation.ForbiddenTags {
+		stats.ForbiddenTagsByTag[fv.TagName]++
+	}
+	for _, cv := range validation.ConstraintViolations {
+		stats.ConstraintViolationsByProvider[cv.Provider]++
+	}
+	for _, tag := range validation.NotPropagatedTags {
+		stats.PropagationViolationsByTag[tag]++
+	}
+	for _, lv := range validation.LocationViolations {
+		for _, tag := range lv.MissingTags {
+			stats.ViolationsByTag[tag]++
+		}
+		for _, pv := range lv.PatternViolations {
+			stats.PatternViolationsByTag[requiredTagName(cfg, pv.TagName)]++
+		}
+		for _, vv := range lv.ValueViolations {
+			stats.ValueViolationsByTag[requiredTagName(cfg, vv.TagName)]++
+		}
+	}
+
+	// Required tags only known after apply are recorded whatever the unknown_tags policy
+	if len(validation.UnknownTags) > 0 {
+		stats.UnknownTags = append(stats.UnknownTags, UnknownTagResource{
+			ResourceType: validation.Type,
+			ResourceName: validation.Name,
+			ResourcePath: path,
+			ModulePath:   modulePath,
+			InstanceKey:  validation.InstanceKey,
+			Tags:         validation.UnknownTags,
+			Range:        validation.Range,
+		})
+	}
+
+	if validation.IsCompliant && !validation.IsExempt {
+		stats.CompliantResources++
+		if module != nil {
+			module.CompliantResources++
+		}
+		return TagViolation{}, false
+	}
+
+	// Determine if the resource is fully exempt (all missing tags are exempt), or partially
+	// exempt (some missing tags are exempt, but others aren't)
+	if validation.IsExempt {
+		if len(validation.MissingTags) == len(validation.ExemptTags) {
+			stats.FullyExemptResources++
+			if module != nil {
+				module.FullyExemptResources++
+			}
+		} else {
+			stats.PartiallyExemptResources++
+			if module != nil {
+				module.PartiallyExemptResources++
 			}
+		}
+	}
 
-			if !tagExists {
-				// If not in resource tags, check if it's in default tags
-				var defaultTagExists bool
-				if cfg.IgnoreTagCase {
-					// Case-insensitive comparison for default tags
-					requiredTagLower := strings.ToLower(requiredTag)
-					for key, value := range defaultTags {
-						if strings.ToLower(key) == requiredTagLower {
-							defaultTagExists = true
-							tagValue = value
-							tagKey = key
-							break
-						}
-					}
-				} else {
-					// Case-sensitive comparison (original behavior)
-					tagValue, defaultTagExists = defaultTags[requiredTag]
-					tagKey = requiredTag
-				}
+	return TagViolation{
+		ResourceType:         validation.Type,
+		ResourceName:         validation.Name,
+		ResourcePath:         path,
+		ModulePath:           modulePath,
+		Address:              validation.Address,
+		InstanceKey:          validation.InstanceKey,
+		ModuleSource:         validation.ModuleSource,
+		ModuleChain:          validation.ModuleChain,
+		MissingTags:          validation.MissingTags,
+		PatternViolations:    validation.PatternViolations,
+		ValueViolations:      validation.ValueViolations,
+		ForbiddenTags:        validation.ForbiddenTags,
+		ConstraintViolations: validation.ConstraintViolations,
+		IsExempt:             validation.IsExempt,
+		ExemptReason:         validation.ExemptReason,
+		TagErrors:            validation.TagErrors,
+		NotPropagatedTags:    validation.NotPropagatedTags,
+		LocationViolations:   validation.LocationViolations,
+		Range:                validation.Range,
+		TagsRange:            validation.TagsRange,
+		TagChanges:           validation.TagChanges,
+		RemovedTags:          validation.RemovedTags,
+		ImmutableTagChanges:  validation.ImmutableTagChanges,
+		UnknownTags:          validation.UnknownTags,
+		TagRules:             validation.TagRules,
+		TagConditions:        validation.TagConditions,
+	}, true
+}
 
-				if !defaultTagExists {
-					// Check if this resource is exempt from this tag requirement
-					exempt, reason := cfg.IsExemptFromTag(resource.Type, resource.Name, requiredTag)
-					if exempt {
-						exemptTags = append(exemptTags, requiredTag)
-						if exemptReason == "" {
-							exemptReason = reason
-						}
-						// Add to missingTags so it shows up in the report
-						missingTags = append(missingTags, requiredTag)
-					} else {
-						missingTags = append(missingTags, requiredTag)
-						nonExemptMissingTags = append(nonExemptMissingTags, requiredTag)
-						stats.ViolationsByTag[requiredTag]++
-					}
-				} else {
-					// Tag exists in default_tags, validate pattern if defined
-					if len(defaultTags) > 0 {
-						logging.Debug("Resource %s '%s' inherits tag '%s' from provider default_tags",
-							resource.Type, resource.Name, requiredTag)
-					}
-
-					// Validate pattern for tag from default_tags
-					if valid, errorMsg := cfg.ValidateTagValue(requiredTag, tagValue); !valid {
-						patternViolations = append(patternViolations, PatternViolation{
-							TagName:         tagKey,
-							ActualValue:     tagValue,
-							ExpectedPattern: getPatternForTag(cfg, requiredTag),
-							ErrorMessage:    errorMsg,
-						})
-						stats.PatternViolationsByTag[requiredTag]++
-					}
-				}
-			} else {
-				// Tag exists in resource tags, validate pattern if defined
-				if valid, errorMsg := cfg.ValidateTagValue(requiredTag, tagValue); !valid {
-					patternViolations = append(patternViolations, PatternViolation{
-						TagName:         tagKey,
-						ActualValue:     tagValue,
-						ExpectedPattern: getPatternForTag(cfg, requiredTag),
-						ErrorMessage:    errorMsg,
-					})
-					stats.PatternViolationsByTag[requiredTag]++
-				}
+// requiredTagName returns the name a tag is required with, which differs from the tag's key
+// when tag case is ignored
+func requiredTagName(cfg *config.Config, tagName string) string {
+	if cfg.IgnoreTagCase {
+		for _, name := range cfg.Required {
+			if strings.EqualFold(name, tagName) {
+				return name
 			}
 		}
+	}
+	return tagName
+}
 
-		// Determine if the resource has any exemptions
-		isExempt := len(exemptTags) > 0
+// providerDefaultTags returns the default tags, and the default tag keys whose values are
+// unknown, of the provider configuration a resource uses. Module calls receive the default
+// tags of every default (unaliased) provider configuration, since their resources may use any.
+func providerDefaultTags(resource parser.Resource, providers map[string]parser.ProviderConfig) (map[string]string, map[string]bool) {
+	if resource.Type != "module" {
+		provider := providers[resource.ProviderKey()]
+		return provider.DefaultTags, provider.UnknownTags
+	}
 
-		// Determine if the resource is fully exempt (all missing tags are exempt)
-		isFullyExempt := isExempt && len(nonExemptMissingTags) == 0 && len(missingTags) > 0
+	keys := make([]string, 0, len(providers))
+	for key, provider := range providers {
+		if provider.Alias == "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	sort.Strings(keys)
 
-		// Determine if the resource is partially exempt (some missing tags are exempt, but others aren't)
-		isPartiallyExempt := isExempt && len(nonExemptMissingTags) > 0
+	defaultTags := make(map[string]string)
+	unknownTags := make(map[string]bool)
+	for _, key := range keys {
+		maps.Copy(defaultTags, providers[key].DefaultTags)
+		maps.Copy(unknownTags, providers[key].UnknownTags)
+	}
+	return defaultTags, unknownTags
+}
 
-		// If the resource has any missing tags or pattern violations, add it to violations
-		if len(missingTags) > 0 || len(patternViolations) > 0 {
-			// If there are any non-exempt missing tags or pattern violations, the resource is not fully compliant
-			if len(nonExemptMissingTags) > 0 || len(patternViolations) > 0 {
-				valid = false
+// ignoredRequiredTags returns the required tags covered by the ignore_tags of provider
+// configurations
+func ignoredRequiredTags(providers []parser.ProviderConfig, cfg *config.Config) []IgnoredRequiredTag {
+	var ignored []IgnoredRequiredTag
+	for _, provider := range providers {
+		for _, requiredTag := range cfg.Required {
+			if !provider.IgnoreTags.Matches(requiredTag, cfg.IgnoreTagCase) {
+				continue
 			}
-
-			violations = append(violations, TagViolation{
-				ResourceType:      resource.Type,
-				ResourceName:      resource.Name,
-				ResourcePath:      resource.Path,
-				MissingTags:       missingTags,
-				PatternViolations: patternViolations,
-				IsExempt:          isExempt,
-				ExemptReason:      exemptReason,
+			ignored = append(ignored, IgnoredRequiredTag{
+				Tag:      requiredTag,
+				Provider: provider.Key(),
+				Path:     provider.Path,
 			})
+		}
+	}
+	sort.Slice(ignored, func(i, j int) bool {
+		if ignored[i].Provider != ignored[j].Provider {
+			return ignored[i].Provider < ignored[j].Provider
+		}
+		return ignored[i].Tag < ignored[j].Tag
+	})
+	return ignored
+}
 
-			// Update statistics based on exemption status
-			if isFullyExempt {
-				stats.FullyExemptResources++
-			} else if isPartiallyExempt {
-				stats.PartiallyExemptResources++
+// notPropagatedTags returns the tags the configuration requires to be propagated at launch
+// whose tag blocks set propagate_at_launch = false
+func notPropagatedTags(resource parser.Resource, cfg *config.Config) []string {
+	var tags []string
+	for tag, propagate := range resource.PropagateAtLaunch {
+		if propagate || !cfg.RequiresPropagateAtLaunch(tag) {
+			continue
+		}
+		if exempt, _ := cfg.IsExempt(exemptionTarget(resource), tag); exempt {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// validateLocationTags checks the required tags at the secondary tag locations of a resource
+// that the configuration requires. Provider default tags only apply to the locations that
+// inherit them, such as root_block_device.tags.
+func validateLocationTags(resource parser.Resource, cfg *config.Config, defaultTags map[string]string, unknownDefaultTags map[string]bool) []LocationViolation {
+	var violations []LocationViolation
+	for _, location := range resource.LocationTags {
+		if !cfg.RequiresTagLocation(location.Location) {
+			continue
+		}
+		if location.InheritsDefaultTags {
+			location = withDefaultTags(location, defaultTags, unknownDefaultTags)
+		}
+
+		violation := LocationViolation{Location: location.Name, TagErrors: location.TagErrors, Range: location.Range}
+		for _, requiredTag := range cfg.Required {
+			if exempt, _ := cfg.IsExempt(exemptionTarget(resource), requiredTag); exempt {
+				continue
 			}
-		} else {
-			// No missing tags, resource is compliant
-			stats.CompliantResources++
+			if resource.IgnoreTags.Matches(requiredTag, cfg.IgnoreTagCase) {
+				continue
+			}
+
+			tagKey, tagValue, exists := findTag(location.Tags, requiredTag, cfg.IgnoreTagCase)
+			if !exists {
+				if !location.TagsUnknown {
+					violation.MissingTags = append(violation.MissingTags, requiredTag)
+				}
+				continue
+			}
+			if location.UnknownTags[tagKey] {
+				continue
+			}
+			if valid, errorMsg := cfg.ValidateTagValue(requiredTag, tagValue); !valid {
+				violation.PatternViolations = append(violation.PatternViolations, PatternViolation{
+					TagName:         tagKey,
+					ActualValue:     tagValue,
+					ExpectedPattern: getPatternForTag(cfg, requiredTag),
+					ErrorMessage:    errorMsg,
+					Range:           location.Range,
+				})
+			}
+			if allowed, suggestion := cfg.ValidateAllowedValue(requiredTag, tagValue); !allowed {
+				violation.ValueViolations = append(violation.ValueViolations, AllowedValueViolation{
+					TagName:       tagKey,
+					ActualValue:   tagValue,
+					AllowedValues: cfg.AllowedValues(requiredTag),
+					Suggestion:    suggestion,
+					Range:         location.Range,
+				})
+			}
+		}
+
+		if len(violation.MissingTags) > 0 || len(violation.PatternViolations) > 0 || len(violation.ValueViolations) > 0 {
+			violations = append(violations, violation)
 		}
 	}
+	return violations
+}
 
-	// Set the total resources to only count non-excluded resources
-	stats.TotalResources = nonExcludedResources
+// withDefaultTags returns the tags of a secondary tag location merged over the provider
+// default tags, keeping track of the values only known after apply
+func withDefaultTags(location parser.LocationTags, defaultTags map[string]string, unknownDefaultTags map[string]bool) parser.LocationTags {
+	if len(defaultTags) == 0 {
+		return location
+	}
+	tags := maps.Clone(defaultTags)
+	maps.Copy(tags, location.Tags)
+	unknown := make(map[string]bool)
+	for key := range unknownDefaultTags {
+		if _, overridden := location.Tags[key]; !overridden {
+			unknown[key] = true
+		}
+	}
+	maps.Copy(unknown, location.UnknownTags)
+	location.Tags = tags
+	location.UnknownTags = unknown
+	return location
+}
 
-	return valid, violations, stats, resources
+// tagRange returns the range of a tag of a resource, or of its tags when the tag was not
+// written with a literal key
+func tagRange(resource parser.Resource, key string) parser.SourceRange {
+	if r, exists := resource.TagRanges[key]; exists {
+		return r
+	}
+	return resource.TagsRange
+}
+
+// exemptionTarget identifies a resource for matching exemptions. Resources without an address,
+// such as those built in tests, are addressed by their module path, type and name.
+func exemptionTarget(resource parser.Resource) config.ExemptionTarget {
+	address := resource.Address
+	if address == "" {
+		address = resource.Type + "." + resource.Name
+		if resource.ModulePath != "" {
+			address = resource.ModulePath + "." + address
+		}
+	}
+	return config.ExemptionTarget{
+		ResourceType: resource.Type,
+		ResourceName: resource.Name,
+		Address:      address,
+		ModulePath:   resource.ModulePath,
+		ModuleSource: resource.ModuleSource,
+	}
+}
+
+// knownTags returns the tags a resource ends up with, its own tags over the provider default
+// tags it inherits, without the values only known after apply
+func knownTags(resource parser.Resource, defaultTags map[string]string, unknownDefaultTags map[string]bool) map[string]string {
+	tags := make(map[string]string, len(resource.Tags)+len(defaultTags))
+	for key, value := range defaultTags {
+		if !unknownDefaultTags[key] {
+			tags[key] = value
+		}
+	}
+	for key, value := range resource.Tags {
+		if resource.UnknownTags[key] {
+			delete(tags, key)
+			continue
+		}
+		tags[key] = value
+	}
+	return tags
+}
+
+// ruleTarget returns what rules are matched against for a resource
+func ruleTarget(resource parser.Resource) config.RuleTarget {
+	// The provider meta-argument names the provider, e.g. "google-beta" or "aws.west", and
+	// the resource type implies it otherwise
+	provider, _, _ := strings.Cut(resource.ProviderKey(), ".")
+
+	// Path selectors match the file declaring the resource block: the file of its range when
+	// known, as recovered from the configuration in plan mode, or the file it was read from
+	// in directory mode. A plan or state file does not locate the resource.
+	path := resource.Range.Filename
+	if path == "" && parser.IsTerraformFile(resource.Path) {
+		path = resource.Path
+	}
+	return config.RuleTarget{
+		ResourceType: resource.Type,
+		Provider:     provider,
+		Family:       parser.ProviderFamily(resource.Type),
+		ModuleSource: resource.ModuleSource,
+		Path:         path,
+	}
+}
+
+// RuleFor returns the name of the rule that requires a tag, or an empty string when the tag
+// is required globally
+func (v TagViolation) RuleFor(tagName string) string {
+	if rule, ok := v.TagRules[tagName]; ok {
+		return rule
+	}
+	for name, rule := range v.TagRules {
+		if strings.EqualFold(name, tagName) {
+			return rule
+		}
+	}
+	return ""
+}
+
+// ConditionFor describes the condition that requires a tag, or returns an empty string when
+// no conditional requirement requires it
+func (v TagViolation) ConditionFor(tagName string) string {
+	if condition, ok := v.TagConditions[tagName]; ok {
+		return condition
+	}
+	for name, condition := range v.TagConditions {
+		if strings.EqualFold(name, tagName) {
+			return condition
+		}
+	}
+	return ""
+}
+
+// findTag looks up a tag by name, optionally ignoring the case of tag keys
+func findTag(tags map[string]string, name string, ignoreCase bool) (string, string, bool) {
+	if value, exists := tags[name]; exists {
+		return name, value, true
+	}
+	if ignoreCase {
+		for key, value := range tags {
+			if strings.EqualFold(key, name) {
+				return key, value, true
+			}
+		}
+	}
+	return "", "", false
 }
 
 // getPatternForTag returns the pattern for a given tag name
@@ -291,7 +670,7 @@ func ValidateDirectory(dir string, cfg *config.Config, logLevel string) (bool, [
 	}
 
 	// Find all Terraform files in the directory
-	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	files, err := parser.FindTerraformFiles(dir)
 	if err != nil {
 		return false, []TagViolation{{
 			ResourceType: "error",
@@ -309,32 +688,59 @@ func ValidateDirectory(dir string, cfg *config.Config, logLevel string) (bool, [
 			ResourceType: "error",
 			ResourceName: "error",
 			ResourcePath: dir,
-			MissingTags:  []string{fmt.Sprintf("No Terraform files (*.tf) found in directory: %s", dir)},
+			MissingTags:  []string{fmt.Sprintf("No Terraform files (*.tf, *.tf.json) found in directory: %s", dir)},
+		}}, TagComplianceStats{}, nil
+	}
+
+	// Resolve variables and locals so tag expressions that refer to them can be evaluated
+	moduleContext, err := parser.NewModuleContext(dir, parser.EvalOptions{
+		VarFiles: cfg.VarFiles,
+		Vars:     cfg.Vars,
+	})
+	if err != nil {
+		return false, []TagViolation{{
+			ResourceType: "error",
+			ResourceName: "error",
+			ResourcePath: dir,
+			MissingTags:  []string{fmt.Sprintf("Error loading variables: %s", err)},
 		}}, TagComplianceStats{}, nil
 	}
 
 	var allResources []parser.Resource
 	var allProviders []parser.ProviderConfig
+	var localModules []parser.LocalModule
+	var diagnostics []parser.Diagnostic
 
 	// Parse each file
 	for _, file := range files {
 		logging.Info("Analyzing file: %s", file)
 
 		// Parse resources
-		resources, err := parser.ParseFile(file, logLevel)
+		resources, fileDiagnostics, err := parser.ParseFileWithDiagnostics(file, moduleContext)
 		if err != nil {
 			logging.Warn("Error parsing file %s: %s", file, err)
+			diagnostics = append(diagnostics, parser.FileDiagnostics(file, err)...)
 			continue
 		}
+		diagnostics = append(diagnostics, fileDiagnostics...)
 		allResources = append(allResources, resources...)
 
-		// Parse provider blocks
-		providers, err := parser.ParseProviderBlocks(file)
+		// Parse provider blocks; module calls are still followed when they cannot be parsed
+		providers, err := parser.ParseProviderBlocksWithContext(file, moduleContext)
 		if err != nil {
 			logging.Warn("Error parsing provider blocks in %s: %s", file, err)
-			continue
+			diagnostics = append(diagnostics, parser.FileDiagnostics(file, err)...)
 		}
 		allProviders = append(allProviders, providers...)
+
+		// Parse module calls with local sources
+		modules, err := parser.ParseLocalModulesWithContext(file, moduleContext)
+		if err != nil {
+			logging.Warn("Error parsing module calls in %s: %s", file, err)
+			diagnostics = append(diagnostics, parser.FileDiagnostics(file, err)...)
+			continue
+		}
+		localModules = append(localModules, modules...)
 	}
 
 	logging.Info("Found %d taggable resources", len(allResources))
@@ -342,9 +748,39 @@ func ValidateDirectory(dir string, cfg *config.Config, logLevel string) (bool, [
 
 	// Validate resources
 	valid, violations, stats, _ := ValidateResources(allResources, allProviders, cfg)
+
+	// Validate the resources of local modules with the inputs passed by their module calls
+	if len(localModules) > 0 {
+		logging.Info("Found %d module calls with local sources", len(localModules))
+		modulesValid, moduleViolations, moduleStats, moduleResources := validateLocalModules(localModules, allProviders, cfg)
+		if !modulesValid {
+			valid = false
+		}
+		violations = append(violations, moduleViolations...)
+		allResources = append(allResources, moduleResources...)
+		mergeStats(&stats, moduleStats)
+	}
+
+	// Files that could not be fully analyzed may hide resources, which fails strict runs
+	stats.Diagnostics = appendDiagnostics(diagnostics, stats.Diagnostics)
+	if cfg.Strict && len(stats.Diagnostics) > 0 {
+		valid = false
+	}
+
 	return valid, violations, stats, allResources
 }
 
+// appendDiagnostics appends diagnostics that are not already listed. Local modules called more
+// than once are parsed once per call and report the same diagnostics each time.
+func appendDiagnostics(diagnostics []parser.Diagnostic, more []parser.Diagnostic) []parser.Diagnostic {
+	for _, diag := range more {
+		if !slices.Contains(diagnostics, diag) {
+			diagnostics = append(diagnostics, diag)
+		}
+	}
+	return diagnostics
+}
+
 // ValidateTerraformPlan validates a Terraform plan file including module resources
 func ValidateTerraformPlan(planPath string, cfg *config.Config, logLevel string) (bool, []TagViolation, TagComplianceStats, []parser.Resource) {
 	logging.Info("Validating Terraform plan with module resources: %s", planPath)
@@ -352,59 +788,108 @@ func ValidateTerraformPlan(planPath string, cfg *config.Config, logLevel string)
 	// Parse both direct and module resources from the plan
 	directResources, moduleResources, err := parser.ParseTerraformPlanWithModules(planPath, logLevel)
 	if err != nil {
-		logging.Error("Error parsing plan: %v", err)
-		return false, nil, TagComplianceStats{}, nil
+		return false, []TagViolation{{
+			ResourceType: "error",
+			ResourceName: "error",
+			ResourcePath: planPath,
+			MissingTags:  []string{fmt.Sprintf("Error parsing plan: %s", err)},
+		}}, TagComplianceStats{}, nil
 	}
 
-	// Plan-based validation uses only plan data - no .tf file parsing needed
-	// Provider defaults and module inheritance are already computed in the plan
-	providerTags := make(map[string]map[string]string)
+	// Source positions are not part of the plan; recover them from the Terraform
+	// configuration the plan was created from, where it is available
+	configDir := cfg.ConfigDir
+	if configDir == "" {
+		configDir = filepath.Dir(planPath)
+	}
+	if err := parser.LocatePlanResources(planPath, configDir, directResources, moduleResources); err != nil {
+		logging.Debug("Unable to locate plan resources in %s: %v", configDir, err)
+	}
 
 	logging.Info("Found %d direct resources and %d module resources", len(directResources), len(moduleResources))
 
-	// Validate both direct and module resources
-	result := ValidateWithModules(directResources, moduleResources, cfg, providerTags)
+	// Validate both direct and module resources. Plan-based validation uses only plan data:
+	// provider defaults and module inheritance are already computed in the plan.
+	result := ValidateWithModules(directResources, moduleResources, cfg)
 
 	// Extract violations and stats from the result
+	valid, violations, stats := resultViolations(result, cfg)
+
+	// Warn about required tags the plan's provider configurations ignore
+	planProviders, err := parser.ParseTerraformPlanProviders(planPath)
+	if err != nil {
+		logging.Warn("Error reading provider configurations from plan: %v", err)
+	}
+	stats.IgnoredRequiredTags = ignoredRequiredTags(planProviders, cfg)
+
+	return valid, violations, stats, moduleResourcesWithDirect(directResources, moduleResources)
+}
+
+// ValidateTerraformState validates the resources recorded in the JSON output of terraform show
+// for a state, including module resources, to audit deployed infrastructure
+func ValidateTerraformState(statePath string, cfg *config.Config, logLevel string) (bool, []TagViolation, TagComplianceStats, []parser.Resource) {
+	logging.Info("Validating Terraform state with module resources: %s", statePath)
+
+	directResources, moduleResources, err := parser.ParseTerraformStateWithModules(statePath)
+	if err != nil {
+		return false, []TagViolation{{
+			ResourceType: "error",
+			ResourceName: "error",
+			ResourcePath: statePath,
+			MissingTags:  []string{fmt.Sprintf("Error parsing state: %s", err)},
+		}}, TagComplianceStats{}, nil
+	}
+
+	logging.Info("Found %d direct resources and %d module resources", len(directResources), len(moduleResources))
+
+	// Tags recorded in the state already include provider default tags
+	result := ValidateWithModules(directResources, moduleResources, cfg)
+	valid, violations, stats := resultViolations(result, cfg)
+
+	return valid, violations, stats, moduleResourcesWithDirect(directResources, moduleResources)
+}
+
+// resultViolations converts the results of ValidateWithModules into violations and statistics
+func resultViolations(result ValidationResultWithModules, cfg *config.Config) (bool, []TagViolation, TagComplianceStats) {
 	var violations []TagViolation
-	var allResources []parser.Resource
+	stats := newTagComplianceStats()
+	valid := true
+
+	for _, resourceType := range result.ExcludedResources {
+		recordExcluded(&stats, resourceType)
+	}
 
 	// Collect violations from direct resources
 	for _, rv := range result.DirectResources {
 		if !rv.IsCompliant {
-			violations = append(violations, TagViolation{
-				ResourceType:      rv.Type,
-				ResourceName:      rv.Name,
-				ResourcePath:      rv.Path,
-				MissingTags:       rv.MissingTags,
-				PatternViolations: rv.PatternViolations,
-			})
+			valid = false
+		}
+		if violation, ok := recordValidation(&stats, rv, "", cfg); ok {
+			violations = append(violations, violation)
 		}
-		// Note: We can't reconstruct the full Resource from ResourceValidation
-		// This is a limitation of the current design
 	}
 
 	// Collect violations from module resources
 	for _, mrv := range result.ModuleResources {
 		if !mrv.IsCompliant {
-			violations = append(violations, TagViolation{
-				ResourceType:      mrv.Type,
-				ResourceName:      mrv.Name,
-				ResourcePath:      mrv.ModulePath,
-				MissingTags:       mrv.MissingTags,
-				PatternViolations: mrv.PatternViolations,
-			})
+			valid = false
+		}
+		if violation, ok := recordValidation(&stats, mrv.ResourceValidation, mrv.ModulePath, cfg); ok {
+			violations = append(violations, violation)
 		}
 	}
 
-	// Create stats
-	stats := TagComplianceStats{
-		TotalResources:     result.Summary.TotalResources,
-		CompliantResources: result.Summary.TotalCompliant,
-	}
+	return valid, violations, stats
+}
 
-	valid := len(violations) == 0
-	return valid, violations, stats, allResources
+// moduleResourcesWithDirect returns direct resources followed by the resources of module
+// resources, for remediation suggestions
+func moduleResourcesWithDirect(directResources []parser.Resource, moduleResources []parser.ModuleResource) []parser.Resource {
+	allResources := append([]parser.Resource(nil), directResources...)
+	for _, moduleResource := range moduleResources {
+		allResources = append(allResources, moduleResource.Resource)
+	}
+	return allResources
 }
 
 // GenerateRemediationCode generates HCL code to fix missing tags
@@ -470,70 +955,166 @@ func SuggestProviderDefaultTagsUpdate(missingTags []string) string {
 	return sb.String()
 }
 
-// validateResource validates a single resource and returns ResourceValidation
-func validateResource(resource parser.Resource, cfg *config.Config, providerTags map[string]map[string]string) ResourceValidation {
-	validation := ResourceValidation{
-		Type:              resource.Type,
-		Name:              resource.Name,
-		Path:              resource.Path,
-		IsCompliant:       true,
-		MissingTags:       []string{},
-		PatternViolations: []PatternViolation{},
+// validateResource validates a single resource against the required tags, with the default
+// tags of the provider configuration it uses and the default tag keys whose values are
+// unknown. Directory, plan and state validation share it, so they apply the same rules.
+func validateResource(resource parser.Resource, cfg *config.Config, defaultTags map[string]string, unknownDefaultTags map[string]bool) ResourceValidation {
+	// Rules matching the resource add to or replace the global required tags
+	cfg, tagRules := cfg.ForResource(ruleTarget(resource))
+
+	// Conditional requirements are evaluated on the tags the resource ends up with
+	var tagConditions map[string]string
+	if len(cfg.ConditionalTags) > 0 {
+		cfg, tagConditions = cfg.ForTags(knownTags(resource, defaultTags, unknownDefaultTags))
 	}
 
-	// Get provider default tags for this resource
-	var defaultTags map[string]string
-	if providerTags != nil {
-		// Determine provider type based on resource type
-		if strings.HasPrefix(resource.Type, "aws_") || strings.HasPrefix(resource.Type, "awscc_") {
-			defaultTags = providerTags["aws"]
-		} else if strings.HasPrefix(resource.Type, "azapi_") {
-			defaultTags = providerTags["azapi"]
-		}
+	validation := ResourceValidation{
+		Type:          resource.Type,
+		Name:          resource.Name,
+		Path:          resource.Path,
+		Address:       resource.Address,
+		InstanceKey:   resource.InstanceKey,
+		ModuleSource:  resource.ModuleSource,
+		ModuleChain:   resource.ModuleChain,
+		IsCompliant:   true,
+		TagErrors:     resource.TagErrors,
+		Range:         resource.Range,
+		TagsRange:     resource.TagsRange,
+		TagRules:      tagRules,
+		TagConditions: tagConditions,
 	}
 
 	// Check each required tag
-	for tagName := range cfg.RequiredTags {
-		// Check if resource is exempt from this tag
-		if isExempt, reason := cfg.IsExemptFromTag(resource.Type, resource.Name, tagName); isExempt {
-			validation.IsExempt = true
-			validation.ExemptReason = reason
+	for _, requiredTag := range cfg.Required {
+		// Required tags covered by the provider's ignore_tags are not checked
+		if resource.IgnoreTags.Matches(requiredTag, cfg.IgnoreTagCase) {
 			continue
 		}
 
-		// Check if tag exists in resource tags or provider default tags
-		tagValue := ""
-		hasTag := false
-
-		// First check resource-level tags
-		if value, exists := resource.Tags[tagName]; exists {
-			tagValue = value
-			hasTag = true
-		} else if defaultTags != nil {
-			// Then check provider default tags
-			if value, exists := defaultTags[tagName]; exists {
-				tagValue = value
-				hasTag = true
+		// Check if the tag is in the resource's tags, then in the provider's default tags
+		tagKey, tagValue, hasTag := findTag(resource.Tags, requiredTag, cfg.IgnoreTagCase)
+		fromResource := hasTag
+		unknown := isUnknownTag(resource, tagKey, hasTag)
+		if !hasTag {
+			tagKey, tagValue, hasTag = findTag(defaultTags, requiredTag, cfg.IgnoreTagCase)
+			if hasTag {
+				logging.Debug("Resource %s '%s' inherits tag '%s' from provider default_tags",
+					resource.Type, resource.Name, requiredTag)
+				// An inherited tag is only known after apply if its default_tags value is unknown, such
+				// as a value referencing a resource attribute
+				unknown = unknownDefaultTags[tagKey]
 			}
 		}
+		exempt, reason := cfg.IsExempt(exemptionTarget(resource), requiredTag)
+
+		// Compare the tag with its value before a planned update
+		var change TagChange
+		if resource.PreviousTags != nil && !unknown {
+			_, oldValue, hadTag := findTag(resource.PreviousTags, requiredTag, cfg.IgnoreTagCase)
+			change = classifyTagChange(requiredTag, oldValue, hadTag, tagValue, hasTag)
+			validation.TagChanges = append(validation.TagChanges, change)
+		}
 
-		if !hasTag {
-			validation.MissingTags = append(validation.MissingTags, tagName)
+		switch {
+		case unknown:
+			// Tags only known after apply are not missing, and cannot be checked against a pattern
+			if !exempt {
+				validation.UnknownTags = append(validation.UnknownTags, requiredTag)
+			}
+		case !hasTag && exempt:
+			// Exempt tags are added to the missing tags so they show up in the report
+			validation.MissingTags = append(validation.MissingTags, requiredTag)
+			validation.ExemptTags = append(validation.ExemptTags, requiredTag)
+			validation.IsExempt = true
+			if validation.ExemptReason == "" {
+				validation.ExemptReason = reason
+			}
+		case change.Kind == TagRemoved:
+			// The update removes a required tag the resource has
+			validation.RemovedTags = append(validation.RemovedTags, change)
 			validation.IsCompliant = false
-		} else {
+		case !hasTag:
+			validation.MissingTags = append(validation.MissingTags, requiredTag)
+			validation.IsCompliant = false
+		default:
 			// Validate tag value against pattern if defined
-			if isValid, errorMsg := cfg.ValidateTagValue(tagName, tagValue); !isValid {
-				validation.PatternViolations = append(validation.PatternViolations, PatternViolation{
-					TagName:         tagName,
+			if valid, errorMsg := cfg.ValidateTagValue(requiredTag, tagValue); !valid {
+				pv := PatternViolation{
+					TagName:         tagKey,
 					ActualValue:     tagValue,
-					ExpectedPattern: cfg.RequiredTags[tagName].Pattern,
+					ExpectedPattern: getPatternForTag(cfg, requiredTag),
 					ErrorMessage:    errorMsg,
-				})
+				}
+				if fromResource {
+					pv.Range = tagRange(resource, tagKey)
+				}
+				validation.PatternViolations = append(validation.PatternViolations, pv)
+				validation.IsCompliant = false
+			}
+			if allowed, suggestion := cfg.ValidateAllowedValue(requiredTag, tagValue); !allowed {
+				vv := AllowedValueViolation{
+					TagName:       tagKey,
+					ActualValue:   tagValue,
+					AllowedValues: cfg.AllowedValues(requiredTag),
+					Suggestion:    suggestion,
+				}
+				if fromResource {
+					vv.Range = tagRange(resource, tagKey)
+				}
+				validation.ValueViolations = append(validation.ValueViolations, vv)
 				validation.IsCompliant = false
 			}
 		}
 	}
 
+	// Every tag of the resource is checked against the forbidden tags
+	validation.ForbiddenTags = findForbiddenTags(resource, cfg, defaultTags, unknownDefaultTags)
+	if len(validation.ForbiddenTags) > 0 {
+		validation.IsCompliant = false
+	}
+
+	// and against the constraints of the resource's provider
+	validation.ConstraintViolations = findConstraintViolations(resource, cfg, defaultTags, unknownDefaultTags)
+	if len(validation.ConstraintViolations) > 0 {
+		validation.IsCompliant = false
+	}
+
+	sort.Strings(validation.UnknownTags)
+	logUnknownTags(resource, validation.UnknownTags)
+	if len(validation.UnknownTags) > 0 && failsOnUnknownTags(cfg) {
+		validation.IsCompliant = false
+	}
+
+	sort.Slice(validation.TagChanges, func(i, j int) bool {
+		return validation.TagChanges[i].TagName < validation.TagChanges[j].TagName
+	})
+	sort.Slice(validation.RemovedTags, func(i, j int) bool {
+		return validation.RemovedTags[i].TagName < validation.RemovedTags[j].TagName
+	})
+	for _, change := range validation.TagChanges {
+		logging.Debug("Resource %s '%s' tag '%s' is %s", resource.Type, resource.Name, change.TagName, change.Kind)
+	}
+
+	// Immutable tags must keep their value; removals of required tags are already reported.
+	// Removed required tags are named as configured and immutable tags by their key, which
+	// only differ in case when tag case is ignored.
+	for _, change := range immutableTagChanges(resource, cfg) {
+		if change.Kind == TagRemoved && slices.ContainsFunc(validation.RemovedTags, func(removed TagChange) bool {
+			return removed.TagName == change.TagName || (cfg.IgnoreTagCase && strings.EqualFold(removed.TagName, change.TagName))
+		}) {
+			continue
+		}
+		validation.ImmutableTagChanges = append(validation.ImmutableTagChanges, change)
+	}
+
+	// Check tags that must be propagated to instances launched by the resource, and
+	// secondary tag locations, such as volume_tags
+	validation.NotPropagatedTags = notPropagatedTags(resource, cfg)
+	validation.LocationViolations = validateLocationTags(resource, cfg, defaultTags, unknownDefaultTags)
+	if len(validation.NotPropagatedTags) > 0 || len(validation.LocationViolations) > 0 || len(validation.ImmutableTagChanges) > 0 {
+		validation.IsCompliant = false
+	}
+
 	return validation
 }
 
@@ -712,6 +1293,72 @@ func GenerateHTMLReport(violations []TagViolation, stats TagComplianceStats, cfg
             </div>
         </div>
         
+        <!-- Per-directory Statistics -->
+        {{if .Stats.Directories}}
+        <div class="card mb-4">
+            <div class="card-header bg-secondary text-white">
+                <h2 class="card-title h5 mb-0">Directories</h2>
+            </div>
+            <div class="card-body">
+                <table class="table table-striped">
+                    <thead>
+                        <tr>
+                            <th>Directory</th>
+                            <th>Resources</th>
+                            <th>Compliant</th>
+                            <th>Exempt</th>
+                            <th>Excluded</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Stats.Directories}}
+                        <tr>
+                            <td><code>{{.Dir}}</code></td>
+                            <td>{{.TotalResources}}</td>
+                            <td>{{.CompliantResources}}</td>
+                            <td>{{add .FullyExemptResources .PartiallyExemptResources}}</td>
+                            <td>{{.ExcludedResourcesCount}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+        </div>
+        {{end}}
+        
+        <!-- Per-module Statistics -->
+        {{if .Stats.Modules}}
+        <div class="card mb-4">
+            <div class="card-header bg-secondary text-white">
+                <h2 class="card-title h5 mb-0">Modules</h2>
+            </div>
+            <div class="card-body">
+                <table class="table table-striped">
+                    <thead>
+                        <tr>
+                            <th>Module</th>
+                            <th>Source</th>
+                            <th>Resources</th>
+                            <th>Compliant</th>
+                            <th>Exempt</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Stats.Modules}}
+                        <tr>
+                            <td><code>{{.ModulePath}}</code></td>
+                            <td><code>{{.ModuleSource}}</code></td>
+                            <td>{{.TotalResources}}</td>
+                            <td>{{.CompliantResources}}</td>
+                            <td>{{add .FullyExemptResources .PartiallyExemptResources}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+        </div>
+        {{end}}
+        
         <!-- Violations by Tag -->
         <div class="card mb-4">
             <div class="card-header bg-danger text-white">
@@ -757,7 +1404,7 @@ func GenerateHTMLReport(violations []TagViolation, stats TagComplianceStats, cfg
                                 {{if $v.IsExempt}}
                                 <span class="badge bg-warning ms-2">EXEMPT</span>
                                 {{else}}
-                                {{$totalViolations := add (len $v.MissingTags) (len $v.PatternViolations)}}
+                                {{$totalViolations := add (add (add (add (add (add (len $v.MissingTags) (len $v.PatternViolations)) (len $v.ValueViolations)) (len $v.ForbiddenTags)) (len $v.ConstraintViolations)) (len $v.NotPropagatedTags)) (len $v.LocationViolations)}}
                                 <span class="badge bg-danger ms-2">{{$totalViolations}} violations</span>
                                 {{end}}
                             </button>
@@ -766,15 +1413,17 @@ func GenerateHTMLReport(violations []TagViolation, stats TagComplianceStats, cfg
                              aria-labelledby="heading{{$index}}" data-bs-parent="#resourceAccordion">
                             <div class="accordion-body">
                                 <p><strong>Path:</strong> {{$v.ResourcePath}}</p>
+                                {{if not $v.Range.IsZero}}<p><strong>Location:</strong> <code>{{$v.Range.Span}}</code></p>{{end}}
+                                {{if $v.ModulePath}}<p><strong>Module:</strong> <code>{{$v.ModulePath}}</code></p>{{end}}
                                 {{if $v.IsExempt}}
                                 <p><strong>Status:</strong> <span class="exempt-tag">EXEMPT</span> - {{$v.ExemptReason}}</p>
                                 {{end}}
                                 
                                 {{if $v.MissingTags}}
-                                <p><strong>Missing Tags:</strong></p>
+                                <p><strong>Missing Tags:</strong>{{if not $v.TagsRange.IsZero}} (tags at <code>{{$v.TagsRange}}</code>){{end}}</p>
                                 <ul>
                                     {{range $v.MissingTags}}
-                                    <li><code>{{.}}</code></li>
+                                    <li><code>{{.}}</code>{{with $v.RuleFor .}} <span class="badge bg-secondary">rule {{.}}</span>{{end}}{{with $v.ConditionFor .}} <small class="text-muted">(required because {{.}})</small>{{end}}</li>
                                     {{end}}
                                 </ul>
                                 {{end}}
@@ -785,10 +1434,89 @@ func GenerateHTMLReport(violations []TagViolation, stats TagComplianceStats, cfg
                                     {{range $v.PatternViolations}}
                                     <li>
                                         <code>{{.TagName}}</code>: {{.ErrorMessage}}
+                                        {{with $v.RuleFor .TagName}}<span class="badge bg-secondary">rule {{.}}</span>{{end}}{{with $v.ConditionFor .TagName}}<small class="text-muted">(required because {{.}})</small>{{end}}
+                                        {{if not .Range.IsZero}}<small class="text-muted">({{.Range}})</small>{{end}}
                                     </li>
                                     {{end}}
                                 </ul>
                                 {{end}}
+
+                                {{if $v.ValueViolations}}
+                                <p><strong>Values Not Allowed:</strong></p>
+                                <ul>
+                                    {{range $v.ValueViolations}}
+                                    <li>
+                                        <code>{{.TagName}}</code>: '{{.ActualValue}}' is not one of {{join .AllowedValues ", "}}
+                                        {{if .Suggestion}}(did you mean '{{.Suggestion}}'?){{end}}
+                                        {{with $v.RuleFor .TagName}}<span class="badge bg-secondary">rule {{.}}</span>{{end}}{{with $v.ConditionFor .TagName}}<small class="text-muted">(required because {{.}})</small>{{end}}
+                                        {{if not .Range.IsZero}}<small class="text-muted">({{.Range}})</small>{{end}}
+                                    </li>
+                                    {{end}}
+                                </ul>
+                                {{end}}
+                                
+                                {{if $v.ForbiddenTags}}
+                                <p><strong>Forbidden Tags:</strong></p>
+                                <ul>
+                                    {{range $v.ForbiddenTags}}
+                                    <li>
+                                        <code>{{.TagName}}</code>: {{.Reason}}
+                                        {{if eq .Source "provider_default"}}(from provider default_tags){{end}}
+                                        {{if not .Range.IsZero}}<small class="text-muted">({{.Range}})</small>{{end}}
+                                    </li>
+                                    {{end}}
+                                </ul>
+                                {{end}}
+
+                                {{if $v.ConstraintViolations}}
+                                <p><strong>Rejected by Provider:</strong></p>
+                                <ul>
+                                    {{range $v.ConstraintViolations}}
+                                    <li>
+                                        {{with .TagName}}<code>{{.}}</code>: {{end}}{{.Message}}
+                                        {{if eq .Source "provider_default"}}(from provider default_tags){{end}}
+                                        {{if not .Range.IsZero}}<small class="text-muted">({{.Range}})</small>{{end}}
+                                    </li>
+                                    {{end}}
+                                </ul>
+                                {{end}}
+
+                                {{range $v.LocationViolations}}
+                                <p><strong>Tag Location <code>{{.Location}}</code>:</strong>{{if not .Range.IsZero}} <small class="text-muted">({{.Range}})</small>{{end}}</p>
+                                <ul>
+                                    {{range .MissingTags}}
+                                    <li>Missing <code>{{.}}</code></li>
+                                    {{end}}
+                                    {{range .PatternViolations}}
+                                    <li><code>{{.TagName}}</code>: {{.ErrorMessage}}</li>
+                                    {{end}}
+                                    {{range .ValueViolations}}
+                                    <li><code>{{.TagName}}</code>: '{{.ActualValue}}' is not one of {{join .AllowedValues ", "}}</li>
+                                    {{end}}
+                                </ul>
+                                {{end}}
+
+                                {{if $v.NotPropagatedTags}}
+                                <p><strong>Not Propagated at Launch:</strong></p>
+                                <ul>
+                                    {{range $v.NotPropagatedTags}}
+                                    <li><code>{{.}}</code> must set <code>propagate_at_launch = true</code></li>
+                                    {{end}}
+                                </ul>
+                                {{end}}
+
+                                {{if $v.UnknownTags}}
+                                <p><strong>Known Only After Apply:</strong> {{join $v.UnknownTags ", "}}</p>
+                                {{end}}
+
+                                {{if $v.TagErrors}}
+                                <p><strong>Unevaluated Tag Expressions:</strong></p>
+                                <ul>
+                                    {{range $v.TagErrors}}
+                                    <li><code>{{.}}</code></li>
+                                    {{end}}
+                                </ul>
+                                {{end}}
                             </div>
                         </div>
                     </div>
@@ -817,6 +1545,57 @@ func GenerateHTMLReport(violations []TagViolation, stats TagComplianceStats, cfg
         </div>
         {{end}}
         
+        <!-- Required Tags Ignored by Providers -->
+        {{if .Stats.IgnoredRequiredTags}}
+        <div class="card mt-4">
+            <div class="card-header bg-warning">
+                <h2 class="card-title h5 mb-0">Required Tags Ignored by Providers</h2>
+            </div>
+            <div class="card-body">
+                <p>These required tags are covered by a provider's <code>ignore_tags</code>. Terraform does not manage them, so they cannot be enforced and were not checked:</p>
+                <ul>
+                    {{range .Stats.IgnoredRequiredTags}}
+                    <li><code>{{.Tag}}</code> ignored by provider <code>{{.Provider}}</code> in {{.Path}}</li>
+                    {{end}}
+                </ul>
+            </div>
+        </div>
+        {{end}}
+        
+        <!-- Files Not Fully Analyzed -->
+        {{if .Stats.Diagnostics}}
+        <div class="card mt-4">
+            <div class="card-header bg-warning">
+                <h2 class="card-title h5 mb-0">Files Not Fully Analyzed ({{.Stats.UnanalyzedFiles}})</h2>
+            </div>
+            <div class="card-body">
+                <p>These parts of Terraform files could not be analyzed, so resources in them may not have been checked:</p>
+                <ul>
+                    {{range .Stats.Diagnostics}}
+                    <li><code>{{if .Range.StartLine}}{{.Range}}{{else}}{{.Range.Filename}}{{end}}</code> <span class="badge {{if eq .Severity "error"}}bg-danger{{else}}bg-warning text-dark{{end}}">{{.Severity}}</span> {{.Summary}}{{if .Detail}}: {{.Detail}}{{end}}</li>
+                    {{end}}
+                </ul>
+            </div>
+        </div>
+        {{end}}
+        
+        <!-- Tags Known Only After Apply -->
+        {{if .Stats.UnknownTags}}
+        <div class="card mt-4">
+            <div class="card-header bg-info">
+                <h2 class="card-title h5 mb-0">Tags Known Only After Apply</h2>
+            </div>
+            <div class="card-body">
+                <p>The values of these required tags are only known after apply, so they could not be checked:</p>
+                <ul>
+                    {{range .Stats.UnknownTags}}
+                    <li><code>{{.ResourceType}}.{{.ResourceName}}{{.InstanceKey}}</code>{{if .ModulePath}} in <code>{{.ModulePath}}</code>{{end}}: {{join .Tags ", "}}</li>
+                    {{end}}
+                </ul>
+            </div>
+        </div>
+        {{end}}
+        
         <footer class="mt-4 text-center text-muted">
             <p>Generated by <a href="https://github.com/terratags/terratags" target="_blank">Terratags</a></p>
         </footer>
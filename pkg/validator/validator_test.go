@@ -0,0 +1,402 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/terratags/terratags/pkg/config"
+	"github.com/terratags/terratags/pkg/parser"
+)
+
+func TestValidateResources_TagBlocks(t *testing.T) {
+	providers := []parser.ProviderConfig{{
+		Name:        "aws",
+		DefaultTags: map[string]string{"Environment": "dev"},
+		Path:        "main.tf",
+	}}
+	newResource := func(tags map[string]string, propagate map[string]bool) parser.Resource {
+		return parser.Resource{
+			Type:              "aws_autoscaling_group",
+			Name:              "web",
+			Tags:              tags,
+			Path:              "main.tf",
+			TagSources:        make(map[string]parser.TagSource),
+			PropagateAtLaunch: propagate,
+		}
+	}
+
+	tests := []struct {
+		name                string
+		resource            parser.Resource
+		propagateAtLaunch   []string
+		expectValid         bool
+		expectMissing       []string
+		expectNotPropagated []string
+	}{
+		{
+			name:          "Provider default tags do not apply",
+			resource:      newResource(map[string]string{"Name": "web"}, map[string]bool{"Name": true}),
+			expectValid:   false,
+			expectMissing: []string{"Environment"},
+		},
+		{
+			name: "Propagation not required",
+			resource: newResource(map[string]string{"Name": "web", "Environment": "dev"},
+				map[string]bool{"Name": false, "Environment": false}),
+			expectValid: true,
+		},
+		{
+			name: "Required tag not propagated",
+			resource: newResource(map[string]string{"Name": "web", "Environment": "dev"},
+				map[string]bool{"Name": false, "Environment": true}),
+			propagateAtLaunch:   []string{"Name", "Environment"},
+			expectValid:         false,
+			expectNotPropagated: []string{"Name"},
+		},
+		{
+			name: "All required tags propagated",
+			resource: newResource(map[string]string{"Name": "web", "Environment": "dev"},
+				map[string]bool{"Name": true, "Environment": true}),
+			propagateAtLaunch: []string{"Name", "Environment"},
+			expectValid:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Required:          []string{"Name", "Environment"},
+				PropagateAtLaunch: tt.propagateAtLaunch,
+			}
+			valid, violations, _, _ := ValidateResources([]parser.Resource{tt.resource}, providers, cfg)
+			if valid != tt.expectValid {
+				t.Errorf("Expected valid %v, got %v (violations: %+v)", tt.expectValid, valid, violations)
+			}
+			if tt.expectValid {
+				return
+			}
+			if len(violations) != 1 {
+				t.Fatalf("Expected 1 violation, got %d", len(violations))
+			}
+			if !reflect.DeepEqual(violations[0].MissingTags, tt.expectMissing) {
+				t.Errorf("Expected missing tags %v, got %v", tt.expectMissing, violations[0].MissingTags)
+			}
+			if !reflect.DeepEqual(violations[0].NotPropagatedTags, tt.expectNotPropagated) {
+				t.Errorf("Expected not propagated tags %v, got %v", tt.expectNotPropagated, violations[0].NotPropagatedTags)
+			}
+		})
+	}
+}
+
+func TestValidateResources_TagLocations(t *testing.T) {
+	resource := parser.Resource{
+		Type:       "aws_launch_template",
+		Name:       "web",
+		Tags:       map[string]string{"Name": "web", "Owner": "platform"},
+		Path:       "main.tf",
+		TagSources: make(map[string]parser.TagSource),
+		LocationTags: []parser.LocationTags{
+			{
+				Location: "tag_specifications[instance]",
+				Name:     "tag_specifications[instance]",
+				Tags:     map[string]string{"Name": "web"},
+			},
+			{
+				Location: "tag_specifications[volume]",
+				Name:     "tag_specifications[volume]",
+				Tags:     map[string]string{},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		tagLocations    []string
+		expectValid     bool
+		expectLocations map[string][]string
+	}{
+		{
+			name:        "Locations not required",
+			expectValid: true,
+		},
+		{
+			name:            "Required location missing tags",
+			tagLocations:    []string{"tag_specifications[instance]"},
+			expectValid:     false,
+			expectLocations: map[string][]string{"tag_specifications[instance]": {"Owner"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Required:     []string{"Name", "Owner"},
+				TagLocations: tt.tagLocations,
+			}
+			valid, violations, stats, _ := ValidateResources([]parser.Resource{resource}, nil, cfg)
+			if valid != tt.expectValid {
+				t.Fatalf("Expected valid %v, got %v (violations: %+v)", tt.expectValid, valid, violations)
+			}
+			if tt.expectValid {
+				return
+			}
+			if len(violations) != 1 {
+				t.Fatalf("Expected 1 violation, got %d", len(violations))
+			}
+			if len(violations[0].MissingTags) != 0 {
+				t.Errorf("Expected no missing resource tags, got %v", violations[0].MissingTags)
+			}
+			locations := make(map[string][]string)
+			for _, lv := range violations[0].LocationViolations {
+				locations[lv.Location] = lv.MissingTags
+			}
+			if !reflect.DeepEqual(locations, tt.expectLocations) {
+				t.Errorf("Expected location violations %v, got %v", tt.expectLocations, locations)
+			}
+			if stats.ViolationsByTag["Owner"] != 1 {
+				t.Errorf("Expected 1 Owner violation in stats, got %d", stats.ViolationsByTag["Owner"])
+			}
+		})
+	}
+}
+
+func TestValidateTagLocations_DirectoryAndPlanParity(t *testing.T) {
+	root := t.TempDir()
+	configuration := `
+provider "aws" {
+  default_tags {
+    tags = {
+      Environment = "dev"
+    }
+  }
+}
+
+resource "aws_instance" "web" {
+  tags = {
+    Name = "web"
+  }
+
+  root_block_device {
+    tags = {
+      Name = "web-root"
+    }
+  }
+}
+
+resource "aws_launch_template" "web" {
+  tags = {
+    Name = "web"
+  }
+
+  tag_specifications {
+    resource_type = "instance"
+    tags = {
+      Name = "web"
+    }
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "main.tf"), []byte(configuration), 0644); err != nil {
+		t.Fatalf("Failed to write configuration: %v", err)
+	}
+	plan := `{
+  "resource_changes": [
+    {
+      "address": "aws_instance.web",
+      "type": "aws_instance",
+      "name": "web",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "tags": {"Name": "web"},
+          "tags_all": {"Name": "web", "Environment": "dev"},
+          "volume_tags": null,
+          "root_block_device": [
+            {"tags": {"Name": "web-root"}, "tags_all": {"Name": "web-root", "Environment": "dev"}}
+          ],
+          "ebs_block_device": []
+        }
+      }
+    },
+    {
+      "address": "aws_launch_template.web",
+      "type": "aws_launch_template",
+      "name": "web",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "tags": {"Name": "web"},
+          "tags_all": {"Name": "web", "Environment": "dev"},
+          "tag_specifications": [
+            {"resource_type": "instance", "tags": {"Name": "web"}}
+          ]
+        }
+      }
+    }
+  ]
+}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	cfg := &config.Config{
+		Required:     []string{"Name", "Environment"},
+		TagLocations: []string{"root_block_device.tags", "tag_specifications[instance]"},
+	}
+	expected := map[string][]string{"aws_launch_template.tag_specifications[instance]": {"Environment"}}
+
+	dirValid, dirViolations, _, _ := ValidateDirectory(root, cfg, "ERROR")
+	planValid, planViolations, _, _ := ValidateTerraformPlan(planPath, cfg, "ERROR")
+	for mode, result := range map[string]struct {
+		valid      bool
+		violations []TagViolation
+	}{"directory": {dirValid, dirViolations}, "plan": {planValid, planViolations}} {
+		if result.valid {
+			t.Errorf("Expected %s mode to fail", mode)
+		}
+		locations := make(map[string][]string)
+		for _, v := range result.violations {
+			if len(v.MissingTags) != 0 {
+				t.Errorf("Expected no missing resource tags on %s in %s mode, got %v", v.ResourceType, mode, v.MissingTags)
+			}
+			for _, lv := range v.LocationViolations {
+				locations[v.ResourceType+"."+lv.Location] = lv.MissingTags
+			}
+		}
+		if !reflect.DeepEqual(locations, expected) {
+			t.Errorf("Expected location violations %v in %s mode, got %v", expected, mode, locations)
+		}
+	}
+}
+
+func TestValidateResources_IgnoreTags(t *testing.T) {
+	providers := []parser.ProviderConfig{{
+		Name:       "aws",
+		Path:       "providers.tf",
+		IgnoreTags: parser.IgnoreTags{KeyPrefixes: []string{"Cost"}},
+	}}
+	resources := []parser.Resource{
+		{
+			Type:       "aws_s3_bucket",
+			Name:       "logs",
+			Tags:       map[string]string{"Name": "logs"},
+			Path:       "main.tf",
+			TagSources: make(map[string]parser.TagSource),
+		},
+		{
+			Type:       "azurerm_resource_group",
+			Name:       "main",
+			Tags:       map[string]string{"Name": "main"},
+			Path:       "main.tf",
+			TagSources: make(map[string]parser.TagSource),
+		},
+	}
+
+	cfg := &config.Config{Required: []string{"Name", "CostCenter"}}
+	valid, violations, stats, _ := ValidateResources(resources, providers, cfg)
+	if valid {
+		t.Fatal("Expected validation to fail")
+	}
+	if len(violations) != 1 || violations[0].ResourceName != "main" {
+		t.Fatalf("Expected only azurerm_resource_group.main to be missing CostCenter, got %+v", violations)
+	}
+
+	expected := []IgnoredRequiredTag{{Tag: "CostCenter", Provider: "aws", Path: "providers.tf"}}
+	if !reflect.DeepEqual(stats.IgnoredRequiredTags, expected) {
+		t.Errorf("Expected ignored required tags %+v, got %+v", expected, stats.IgnoredRequiredTags)
+	}
+}
+
+func TestValidateResources_InlineSuppressions(t *testing.T) {
+	resources := []parser.Resource{{
+		Type:       "aws_s3_bucket",
+		Name:       "shared",
+		Tags:       map[string]string{"Name": "shared"},
+		Path:       "main.tf",
+		TagSources: make(map[string]parser.TagSource),
+		Suppressions: []parser.Suppression{{
+			Tags:   []string{"Owner"},
+			Reason: "shared bucket",
+			Path:   "main.tf",
+			Line:   2,
+		}},
+	}}
+
+	tests := []struct {
+		name        string
+		disable     bool
+		expectValid bool
+	}{
+		{name: "Suppression exempts the tag", expectValid: true},
+		{name: "Suppressions disabled", disable: true, expectValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Required:                  []string{"Name", "Owner"},
+				DisableInlineSuppressions: tt.disable,
+			}
+			valid, violations, stats, _ := ValidateResources(resources, nil, cfg)
+			if valid != tt.expectValid {
+				t.Fatalf("Expected valid %v, got %v (violations: %+v)", tt.expectValid, valid, violations)
+			}
+			if len(violations) != 1 {
+				t.Fatalf("Expected 1 violation, got %d", len(violations))
+			}
+			if !tt.expectValid {
+				if violations[0].IsExempt {
+					t.Error("Expected the violation not to be exempt")
+				}
+				return
+			}
+			expectedReason := "shared bucket (terratags:ignore at main.tf:2)"
+			if !violations[0].IsExempt || violations[0].ExemptReason != expectedReason {
+				t.Errorf("Expected exempt violation with reason %q, got %+v", expectedReason, violations[0])
+			}
+			if stats.FullyExemptResources != 1 {
+				t.Errorf("Expected 1 fully exempt resource, got %d", stats.FullyExemptResources)
+			}
+			if len(cfg.Exemptions) != 0 {
+				t.Errorf("Expected the configuration to be left unchanged, got %+v", cfg.Exemptions)
+			}
+		})
+	}
+}
+
+func TestValidateResources_InlineSuppressionsMatchAddress(t *testing.T) {
+	suppressed := parser.Resource{
+		Type:         "aws_s3_bucket",
+		Name:         "logs",
+		Tags:         map[string]string{"Name": "logs"},
+		Path:         "modules/app/main.tf",
+		ModulePath:   "module.app",
+		TagSources:   make(map[string]parser.TagSource),
+		Suppressions: []parser.Suppression{{Tags: []string{"Owner"}, Path: "modules/app/main.tf", Line: 2}},
+	}
+	other := parser.Resource{
+		Type:       "aws_s3_bucket",
+		Name:       "logs",
+		Tags:       map[string]string{"Name": "logs"},
+		Path:       "modules/data/main.tf",
+		ModulePath: "module.data",
+		TagSources: make(map[string]parser.TagSource),
+	}
+
+	cfg := &config.Config{Required: []string{"Name", "Owner"}}
+	valid, violations, _, _ := ValidateResources([]parser.Resource{suppressed, other}, nil, cfg)
+	if valid {
+		t.Fatal("Expected the resource without a suppression to fail")
+	}
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %+v", violations)
+	}
+	for _, violation := range violations {
+		if exempt := violation.ResourcePath == suppressed.Path; violation.IsExempt != exempt {
+			t.Errorf("Expected %s to be exempt %v, got %+v", violation.ResourcePath, exempt, violation)
+		}
+	}
+}